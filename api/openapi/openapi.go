@@ -0,0 +1,65 @@
+// Package openapi holds the OpenAPI documents of the HTTP API: http.yaml, written
+// by hand for the routes of pvz_http, and orders_v1.swagger.json, generated from
+// orders.v1 by make generate-orders-api.
+package openapi
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+//go:embed http.yaml
+var httpDocument []byte
+
+//go:embed orders_v1.swagger.json
+var ordersV1Document []byte
+
+// Load returns both documents merged into one OpenAPI 3 document. The generated
+// one is converted from Swagger 2; its schemas are named after proto messages and
+// do not clash with the hand-written ones.
+func Load(ctx context.Context) (*openapi3.T, error) {
+	loader := openapi3.NewLoader()
+
+	doc, err := loader.LoadFromData(httpDocument)
+	if err != nil {
+		return nil, fmt.Errorf("load http.yaml: %w", err)
+	}
+
+	var ordersV1Swagger openapi2.T
+	if err := json.Unmarshal(ordersV1Document, &ordersV1Swagger); err != nil {
+		return nil, fmt.Errorf("load orders_v1.swagger.json: %w", err)
+	}
+	ordersV1, err := openapi2conv.ToV3(&ordersV1Swagger)
+	if err != nil {
+		return nil, fmt.Errorf("convert orders_v1.swagger.json: %w", err)
+	}
+
+	for path, item := range ordersV1.Paths.Map() {
+		if doc.Paths.Value(path) != nil {
+			return nil, fmt.Errorf("path %s is described twice", path)
+		}
+		doc.Paths.Set(path, item)
+	}
+	for name, schema := range ordersV1.Components.Schemas {
+		if _, ok := doc.Components.Schemas[name]; ok {
+			return nil, fmt.Errorf("schema %s is described twice", name)
+		}
+		doc.Components.Schemas[name] = schema
+	}
+	doc.Tags = append(doc.Tags, ordersV1.Tags...)
+
+	if err := loader.ResolveRefsIn(doc, nil); err != nil {
+		return nil, fmt.Errorf("resolve references: %w", err)
+	}
+	if err := doc.Validate(ctx); err != nil {
+		return nil, fmt.Errorf("validate: %w", err)
+	}
+
+	return doc, nil
+}
@@ -29,7 +29,11 @@ func main() {
 		app_logger.MyLogger.Fatal("connect to postgres", zap.Error(err))
 	}
 
-	txManager := tx_manager.New(pool, ctx)
+	txManager := tx_manager.New(pool, nil, tx_manager.RetryPolicy{
+		MaxRetries: cfg.TxMaxRetries,
+		BaseDelay:  cfg.TxRetryBaseDelay,
+		MaxDelay:   cfg.TxRetryMaxDelay,
+	})
 
 	database := db.NewDatabase(txManager)
 
@@ -39,6 +43,6 @@ func main() {
 		app_logger.MyLogger.Fatal("create order repository", zap.Error(repoErr))
 	}
 
-	repo.SeedOrders(ctx)
+	repo.SeedOrders(ctx, cfg.DefaultPickupPointID)
 
 }
@@ -11,15 +11,21 @@ import (
 
 	"github.com/IBM/sarama"
 	"github.com/Staspol216/gh1/internal/config"
+	"github.com/Staspol216/gh1/internal/domain/order"
 	"github.com/Staspol216/gh1/internal/handlers/grpc"
 	"github.com/Staspol216/gh1/internal/handlers/http"
+	"github.com/Staspol216/gh1/internal/health"
+	"github.com/Staspol216/gh1/internal/infra/order_events"
 	"github.com/Staspol216/gh1/internal/infra/order_outbox"
 	"github.com/Staspol216/gh1/internal/infra/postgres"
+	"github.com/Staspol216/gh1/internal/infra/repository/cell"
 	"github.com/Staspol216/gh1/internal/infra/repository/order"
+	"github.com/Staspol216/gh1/internal/infra/repository/pickupcode"
 	"github.com/Staspol216/gh1/internal/infra/tx_manager"
 	"github.com/Staspol216/gh1/internal/service/order"
 	"github.com/Staspol216/gh1/internal/service/order_audit"
 	"github.com/Staspol216/gh1/pkg/api/orders.proto"
+	"github.com/Staspol216/gh1/pkg/api/orders/v1"
 	"github.com/Staspol216/gh1/pkg/logger"
 	"github.com/Staspol216/gh1/pkg/monitoring"
 	"github.com/Staspol216/gh1/pkg/tracing"
@@ -27,6 +33,9 @@ import (
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
 func main() {
@@ -56,17 +65,57 @@ func main() {
 		app_logger.MyLogger.Fatal("connect to postgres", zap.Error(err))
 	}
 
+	var replica *tx_manager.Replica
+	if replicaConnString := cfg.DBReplicaConnString(); replicaConnString != "" {
+		replicaPool, err := pgxpool.Connect(ctx, replicaConnString)
+		if err != nil {
+			app_logger.MyLogger.Fatal("connect to postgres replica", zap.Error(err))
+		}
+		defer replicaPool.Close()
+
+		replica = tx_manager.NewReplica(replicaPool, cfg.DBReplicaMaxLag, cfg.DBReplicaLagCheckInterval)
+	}
+
 	rdb := redis.NewClient(&redis.Options{
 		Addr: cfg.RedisAddr(),
 	})
 	defer rdb.Close()
 
-	txManager := tx_manager.New(pool, sigCtx)
+	txManager := tx_manager.New(pool, replica, tx_manager.RetryPolicy{
+		MaxRetries: cfg.TxMaxRetries,
+		BaseDelay:  cfg.TxRetryBaseDelay,
+		MaxDelay:   cfg.TxRetryMaxDelay,
+	})
+
+	cacheBreaker := order.NewCacheBreaker(rdb, cfg.CacheBreakerThreshold, cfg.CacheBreakerProbeInterval)
 
-	orderCache := order.NewOrderCache(rdb)
+	cacheCodec, err := order.NewCacheCodec(cfg.CacheEncoding, cfg.CacheCompression, cfg.CacheCompressionThreshold)
+	if err != nil {
+		app_logger.MyLogger.Fatal("create cache codec", zap.Error(err))
+	}
+
+	orderCache := order.NewOrderCache(cacheBreaker, cacheCodec, cfg.OrderCacheTTL, cfg.OrderPageCacheTTL, cfg.OrderNegativeCacheTTL)
+	cacheBreaker.OnRecovery(orderCache.ReplaySkippedInvalidations)
+	wg.Go(func() {
+		cacheBreaker.Run(sigCtx)
+	})
 
+	// The cache is optional: orders are served from postgres until redis comes back.
 	if redisPingErr := orderCache.Healthcheck(sigCtx); redisPingErr != nil {
-		app_logger.MyLogger.Fatal("redis healthcheck failed", zap.Error(redisPingErr))
+		app_logger.MyLogger.Warn("redis healthcheck failed, starting without cache", zap.Error(redisPingErr))
+		cacheBreaker.Trip()
+	}
+
+	var ordersCache pvz_order_service.OrdersCache = orderCache
+	if cfg.OrderL1CacheEnabled {
+		l1Cache, err := order.NewL1Cache(orderCache, cfg.OrderL1CacheSize, cfg.OrderL1CacheTTL)
+		if err != nil {
+			app_logger.MyLogger.Fatal("create order l1 cache", zap.Error(err))
+		}
+		wg.Go(func() {
+			l1Cache.Run(sigCtx)
+		})
+		ordersCache = l1Cache
 	}
 
 	database := db.NewDatabase(txManager)
@@ -127,9 +176,48 @@ func main() {
 		app_logger.MyLogger.Fatal("create order repository", zap.Error(err))
 	}
 
-	pvzService := pvz_order_service.NewPvzService(orderRepo, orderOutbox, orderCache, txManager)
+	cellRepo, err := cell.NewCellRepo(database)
+
+	if err != nil {
+		app_logger.MyLogger.Fatal("create cell repository", zap.Error(err))
+	}
+
+	pickupCodeRepo, err := pickupcode.NewPickupCodeRepo(database)
 
-	httpHandler := pvz_http.New(sigCtx, pvzService)
+	if err != nil {
+		app_logger.MyLogger.Fatal("create pickup code repository", zap.Error(err))
+	}
+
+	pickupCodePolicy := pvz_domain.PickupCodePolicy{
+		Secret:      []byte(cfg.PickupCodeSecret),
+		TTL:         cfg.PickupCodeTTL,
+		MaxAttempts: cfg.PickupCodeMaxAttempts,
+		Lockout:     cfg.PickupCodeLockout,
+	}
+
+	orderEvents := order_events.NewFeed(pool, cfg.OrderWatchBuffer)
+
+	wg.Go(func() {
+		orderEvents.Run(sigCtx)
+	})
+
+	pvzService := pvz_order_service.NewPvzService(orderRepo, cellRepo, pickupCodeRepo, pickupCodePolicy, orderOutbox, ordersCache, orderEvents, txManager)
+
+	kafkaClient, err := sarama.NewClient([]string{cfg.KafkaAddr()}, nil)
+	if err != nil {
+		app_logger.MyLogger.Fatal("create kafka client", zap.Error(err))
+	}
+	defer kafkaClient.Close()
+
+	readinessChecks := []pvz_health.Check{
+		{Name: "postgres", Critical: true, Check: pool.Ping},
+		{Name: "redis", Check: orderCache.Healthcheck},
+		{Name: "kafka", Check: func(ctx context.Context) error {
+			return kafkaClient.RefreshMetadata()
+		}},
+	}
+
+	httpHandler := pvz_http.New(sigCtx, pvzService, readinessChecks...)
 
 	tcpListener, err := net.Listen("tcp", fmt.Sprintf(":%d", cfg.BackendGRPCPort))
 
@@ -137,11 +225,43 @@ func main() {
 		app_logger.MyLogger.Fatal("listen tcp", zap.Error(err), zap.Int("port", cfg.BackendGRPCPort))
 	}
 
-	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(tracing.UnaryInterceptor))
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			tracing.UnaryInterceptor,
+			pvz_grpc.LoggingInterceptor,
+			pvz_grpc.MetricsInterceptor,
+			pvz_grpc.RecoveryInterceptor,
+			pvz_grpc.DeadlineInterceptor(cfg.GRPCDefaultDeadline),
+			pvz_grpc.StaffInterceptor(cfg.StaffToken),
+			pvz_grpc.PickupPointInterceptor(cfg.DefaultPickupPointID),
+			pvz_grpc.ExpectedVersionInterceptor,
+			pvz_grpc.ValidationInterceptor,
+		),
+		grpc.ChainStreamInterceptor(
+			tracing.StreamInterceptor,
+			pvz_grpc.LoggingStreamInterceptor,
+			pvz_grpc.MetricsStreamInterceptor,
+			pvz_grpc.RecoveryStreamInterceptor,
+			pvz_grpc.PickupPointStreamInterceptor(cfg.DefaultPickupPointID),
+			pvz_grpc.ValidationStreamInterceptor,
+		),
+	)
 
 	grcpHandler := pvz_grpc.New(pvzService)
 
-	orders_proto.RegisterOrdersServiceServer(grpcServer, grcpHandler)
+	orders_v1.RegisterOrdersServiceServer(grpcServer, grcpHandler)
+	orders_proto.RegisterOrdersServiceServer(grpcServer, pvz_grpc.NewLegacy(grcpHandler))
+
+	healthServer := health.NewServer()
+	grpc_health_v1.RegisterHealthServer(grpcServer, healthServer)
+
+	wg.Go(func() {
+		pvz_grpc.ReportHealth(sigCtx, healthServer, cfg.GRPCHealthCheckInterval, readinessChecks...)
+	})
+
+	if cfg.GRPCReflectionEnabled {
+		reflection.Register(grpcServer)
+	}
 
 	app_logger.MyLogger.Info("gRPC server listening", zap.String("address", tcpListener.Addr().String()))
 
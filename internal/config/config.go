@@ -4,6 +4,7 @@ import (
 	"net/url"
 	"os"
 	"strconv"
+	"time"
 
 	"github.com/Staspol216/gh1/pkg/logger"
 	"github.com/joho/godotenv"
@@ -21,6 +22,28 @@ type Config struct {
 	BackendHTTPPort int    `envconfig:"BACKEND_HTTP_PORT" default:"8080"`
 	BackendGRPCPort int    `envconfig:"BACKEND_GRPC_PORT" default:"50051"`
 
+	// gRPC server. Reflection exposes the schema to tools like grpcurl; unary calls
+	// without a client deadline are cut off after GRPC_DEFAULT_DEADLINE.
+	GRPCReflectionEnabled   bool          `envconfig:"GRPC_REFLECTION_ENABLED" default:"false"`
+	GRPCHealthCheckInterval time.Duration `envconfig:"GRPC_HEALTH_CHECK_INTERVAL" default:"5s"`
+	GRPCDefaultDeadline     time.Duration `envconfig:"GRPC_DEFAULT_DEADLINE" default:"10s"`
+
+	// HTTP API. orders.v1 is always served under /v1; the hand-written routes it
+	// replaces stay available until HTTP_LEGACY_ROUTES_ENABLED is turned off.
+	HTTPLegacyRoutesEnabled bool `envconfig:"HTTP_LEGACY_ROUTES_ENABLED" default:"true"`
+
+	// Pickup point used when a request does not carry one explicitly; 0 makes it mandatory.
+	DefaultPickupPointID int64 `envconfig:"DEFAULT_PICKUP_POINT_ID" default:"1"`
+
+	// Pickup codes shown by recipients at handover
+	PickupCodeSecret      string        `envconfig:"PICKUP_CODE_SECRET" required:"true"`
+	PickupCodeTTL         time.Duration `envconfig:"PICKUP_CODE_TTL" default:"168h"`
+	PickupCodeMaxAttempts int64         `envconfig:"PICKUP_CODE_MAX_ATTEMPTS" default:"5"`
+	PickupCodeLockout     time.Duration `envconfig:"PICKUP_CODE_LOCKOUT" default:"15m"`
+	// Bearer token of pickup point staff, required to regenerate pickup codes. Calls
+	// that need it are refused while it is empty.
+	StaffToken string `envconfig:"STAFF_TOKEN"`
+
 	// Database (Postgres)
 	DBHost    string `envconfig:"DB_HOST" required:"true"`
 	DBPort    int    `envconfig:"DB_PORT" default:"5432"`
@@ -29,11 +52,46 @@ type Config struct {
 	DBName    string `envconfig:"DB_NAME" required:"true"`
 	DBSSLMode string `envconfig:"DB_SSLMODE" default:"disable"`
 
+	// Read replica; reads go to the primary when DB_REPLICA_HOST is empty or the
+	// replica lags more than DB_REPLICA_MAX_LAG.
+	DBReplicaHost             string        `envconfig:"DB_REPLICA_HOST"`
+	DBReplicaPort             int           `envconfig:"DB_REPLICA_PORT" default:"5432"`
+	DBReplicaMaxLag           time.Duration `envconfig:"DB_REPLICA_MAX_LAG" default:"5s"`
+	DBReplicaLagCheckInterval time.Duration `envconfig:"DB_REPLICA_LAG_CHECK_INTERVAL" default:"1s"`
+
+	// Replays of transactions aborted by serialization failures or deadlocks
+	TxMaxRetries     int           `envconfig:"TX_MAX_RETRIES" default:"3"`
+	TxRetryBaseDelay time.Duration `envconfig:"TX_RETRY_BASE_DELAY" default:"10ms"`
+	TxRetryMaxDelay  time.Duration `envconfig:"TX_RETRY_MAX_DELAY" default:"200ms"`
+
 	// Redis
 	RedisHost        string `envconfig:"REDIS_HOST" required:"true"`
 	RedisPort        int    `envconfig:"REDIS_PORT" default:"6379"`
 	RedisInsightPort int    `envconfig:"REDIS_INSIGHT_PORT" default:"5540"`
 
+	// Lifetime of cached orders; bounds staleness if an invalidation is lost.
+	OrderCacheTTL time.Duration `envconfig:"ORDER_CACHE_TTL" default:"10m"`
+	// Lifetime of cached order list pages; they are also dropped on every order change.
+	OrderPageCacheTTL time.Duration `envconfig:"ORDER_PAGE_CACHE_TTL" default:"1m"`
+	// Lifetime of remembered lookups of missing orders.
+	OrderNegativeCacheTTL time.Duration `envconfig:"ORDER_NEGATIVE_CACHE_TTL" default:"30s"`
+	// Redis is bypassed after CACHE_BREAKER_THRESHOLD failures in a row and probed
+	// every CACHE_BREAKER_PROBE_INTERVAL until it recovers.
+	CacheBreakerThreshold     int64         `envconfig:"CACHE_BREAKER_THRESHOLD" default:"5"`
+	CacheBreakerProbeInterval time.Duration `envconfig:"CACHE_BREAKER_PROBE_INTERVAL" default:"5s"`
+	// Format of cached orders: json or protobuf, compressed with none, zstd or snappy
+	// once the encoded value reaches CACHE_COMPRESSION_THRESHOLD bytes.
+	CacheEncoding             string `envconfig:"CACHE_ENCODING" default:"protobuf"`
+	CacheCompression          string `envconfig:"CACHE_COMPRESSION" default:"zstd"`
+	CacheCompressionThreshold int    `envconfig:"CACHE_COMPRESSION_THRESHOLD" default:"512"`
+	// In-process cache in front of Redis, kept coherent across instances over pub/sub.
+	OrderL1CacheEnabled bool          `envconfig:"ORDER_L1_CACHE_ENABLED" default:"false"`
+	OrderL1CacheSize    int           `envconfig:"ORDER_L1_CACHE_SIZE" default:"10000"`
+	OrderL1CacheTTL     time.Duration `envconfig:"ORDER_L1_CACHE_TTL" default:"5s"`
+
+	// Status changes a gRPC order watcher may fall behind by before it is cut off.
+	OrderWatchBuffer int `envconfig:"ORDER_WATCH_BUFFER" default:"256"`
+
 	// Kafka
 	KafkaHost string `envconfig:"KAFKA_HOST" required:"true"`
 	KafkaPort int    `envconfig:"KAFKA_PORT" default:"9092"`
@@ -66,11 +124,30 @@ func Load() (*Config, error) {
 		zap.String("backend_host", cfg.BackendHost),
 		zap.Int("backend_http_port", cfg.BackendHTTPPort),
 		zap.Int("backend_grpc_port", cfg.BackendGRPCPort),
+		zap.Bool("grpc_reflection_enabled", cfg.GRPCReflectionEnabled),
+		zap.Duration("grpc_health_check_interval", cfg.GRPCHealthCheckInterval),
+		zap.Duration("grpc_default_deadline", cfg.GRPCDefaultDeadline),
+		zap.Bool("http_legacy_routes_enabled", cfg.HTTPLegacyRoutesEnabled),
+		zap.Int64("default_pickup_point_id", cfg.DefaultPickupPointID),
+		zap.Duration("pickup_code_ttl", cfg.PickupCodeTTL),
+		zap.Int64("pickup_code_max_attempts", cfg.PickupCodeMaxAttempts),
+		zap.Duration("pickup_code_lockout", cfg.PickupCodeLockout),
+		zap.Bool("staff_token_set", cfg.StaffToken != ""),
 		zap.String("db_host", cfg.DBHost),
 		zap.Int("db_port", cfg.DBPort),
 		zap.String("db_name", cfg.DBName),
+		zap.String("db_replica_host", cfg.DBReplicaHost),
+		zap.Duration("db_replica_max_lag", cfg.DBReplicaMaxLag),
+		zap.Int("tx_max_retries", cfg.TxMaxRetries),
+		zap.Duration("tx_retry_base_delay", cfg.TxRetryBaseDelay),
+		zap.Duration("tx_retry_max_delay", cfg.TxRetryMaxDelay),
 		zap.String("redis_host", cfg.RedisHost),
 		zap.Int("redis_port", cfg.RedisPort),
+		zap.Duration("order_cache_ttl", cfg.OrderCacheTTL),
+		zap.Duration("order_page_cache_ttl", cfg.OrderPageCacheTTL),
+		zap.Duration("order_negative_cache_ttl", cfg.OrderNegativeCacheTTL),
+		zap.Bool("order_l1_cache_enabled", cfg.OrderL1CacheEnabled),
+		zap.Int("order_watch_buffer", cfg.OrderWatchBuffer),
 		zap.String("kafka_host", cfg.KafkaHost),
 		zap.Int("kafka_port", cfg.KafkaPort),
 		zap.String("jaeger_host", cfg.JaegerHost),
@@ -82,10 +159,22 @@ func Load() (*Config, error) {
 }
 
 func (c *Config) DBConnString() string {
+	return c.dbConnString(c.DBHost, c.DBPort)
+}
+
+// DBReplicaConnString returns the replica connection string, or "" if no replica is configured.
+func (c *Config) DBReplicaConnString() string {
+	if c.DBReplicaHost == "" {
+		return ""
+	}
+	return c.dbConnString(c.DBReplicaHost, c.DBReplicaPort)
+}
+
+func (c *Config) dbConnString(host string, port int) string {
 
 	u := &url.URL{
 		Scheme: "postgres",
-		Host:   c.DBHost + ":" + strconv.Itoa(c.DBPort),
+		Host:   host + ":" + strconv.Itoa(port),
 		Path:   c.DBName,
 	}
 
@@ -119,3 +208,9 @@ func (c *Config) HTTPAddr() string {
 func (c *Config) GRPCAddr() string {
 	return c.BackendHost + ":" + strconv.Itoa(c.BackendGRPCPort)
 }
+
+// GRPCLoopbackAddr is where in-process clients, like the HTTP gateway, reach the
+// gRPC server of this instance.
+func (c *Config) GRPCLoopbackAddr() string {
+	return "localhost:" + strconv.Itoa(c.BackendGRPCPort)
+}
@@ -0,0 +1,50 @@
+package pvz_domain
+
+import "errors"
+
+var ErrNoFreeCell = errors.New("no free storage cell fits the order")
+
+var ErrOrderNotInCell = errors.New("order is not placed in a storage cell")
+
+var ErrCellCodeTaken = errors.New("storage cell code is already taken at the pickup point")
+
+type CellSize int
+
+const (
+	CellSizeSmall CellSize = iota + 1
+	CellSizeMedium
+	CellSizeLarge
+)
+
+// Cell is a shelf slot at a pickup point that holds received orders.
+type Cell struct {
+	ID            int64    `json:"id"`
+	PickupPointID int64    `json:"pickup_point_id"`
+	Code          string   `json:"code"`
+	Size          CellSize `json:"size"`
+	Capacity      int64    `json:"capacity"`
+	MaxWeight     float64  `json:"max_weight"`
+	OrdersCount   int64    `json:"orders_count"`
+	LoadWeight    float64  `json:"load_weight"`
+}
+
+func (c *Cell) CanFit(weight float64) bool {
+	return c.OrdersCount < c.Capacity && c.LoadWeight+weight <= c.MaxWeight
+}
+
+// RequiredCellSize returns the smallest cell size that fits a parcel with the given packaging and weight.
+func RequiredCellSize(packagingType string, weight float64) CellSize {
+	size := CellSizeMedium
+	if packagingType == "bag" || packagingType == "membrana" {
+		size = CellSizeSmall
+	}
+
+	switch {
+	case weight > 20.00:
+		size = max(size, CellSizeLarge)
+	case weight > 10.00:
+		size = max(size, CellSizeMedium)
+	}
+
+	return size
+}
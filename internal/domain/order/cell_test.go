@@ -0,0 +1,42 @@
+package pvz_domain
+
+import "testing"
+
+func TestRequiredCellSize(t *testing.T) {
+	tests := []struct {
+		name          string
+		packagingType string
+		weight        float64
+		want          CellSize
+	}{
+		{name: "bag fits small cell", packagingType: "bag", weight: 5, want: CellSizeSmall},
+		{name: "membrana fits small cell", packagingType: "membrana", weight: 1, want: CellSizeSmall},
+		{name: "box needs medium cell", packagingType: "box", weight: 1, want: CellSizeMedium},
+		{name: "unknown packaging needs medium cell", packagingType: "", weight: 1, want: CellSizeMedium},
+		{name: "heavy membrana needs medium cell", packagingType: "membrana", weight: 15, want: CellSizeMedium},
+		{name: "very heavy membrana needs large cell", packagingType: "membrana", weight: 30, want: CellSizeLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RequiredCellSize(tt.packagingType, tt.weight); got != tt.want {
+				t.Errorf("RequiredCellSize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCell_CanFit(t *testing.T) {
+	cell := &Cell{Capacity: 2, MaxWeight: 10, OrdersCount: 1, LoadWeight: 6}
+
+	if !cell.CanFit(4) {
+		t.Errorf("CanFit(4) = false, want true")
+	}
+	if cell.CanFit(4.5) {
+		t.Errorf("CanFit(4.5) = true, want false")
+	}
+
+	cell.OrdersCount = 2
+	if cell.CanFit(0) {
+		t.Errorf("CanFit(0) on full cell = true, want false")
+	}
+}
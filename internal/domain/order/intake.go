@@ -0,0 +1,33 @@
+package pvz_domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MaxIntakeBatchSize caps how many parcels a courier hands over in one intake call.
+const MaxIntakeBatchSize = 500
+
+var (
+	ErrInvalidOrderParams = errors.New("invalid order params")
+	ErrIntakeBatchSize    = errors.New("intake batch size is out of range")
+)
+
+func ValidateIntakeBatchSize(size int) error {
+	if size == 0 || size > MaxIntakeBatchSize {
+		return fmt.Errorf("%w: %d parcels, expected 1 to %d", ErrIntakeBatchSize, size, MaxIntakeBatchSize)
+	}
+	return nil
+}
+
+// ValidateForIntake rejects params of an order that can't be accepted at now.
+func (p *OrderParams) ValidateForIntake(now time.Time) error {
+	if p == nil {
+		return fmt.Errorf("%w: order is required", ErrInvalidOrderParams)
+	}
+	if !now.Before(p.ExpirationDate) {
+		return fmt.Errorf("%w: expiration date can't be in the past", ErrInvalidOrderParams)
+	}
+	return nil
+}
@@ -4,10 +4,10 @@ type MembranaDecorator struct {
 	Strategy PackagingStrategy
 }
 
-func (md *MembranaDecorator) Validate(baseCost float64) error {
-	return md.Strategy.Validate(baseCost)
+func (md *MembranaDecorator) Validate(weight float64) error {
+	return md.Strategy.Validate(weight)
 }
 
-func (md *MembranaDecorator) CalculateWorth(baseCost float64) float64 {
-	return md.Strategy.CalculateWorth(baseCost) + 1.0
+func (md *MembranaDecorator) CalculateWorth(baseCost Money) Money {
+	return md.Strategy.CalculateWorth(baseCost).AddMinor(1 * minorUnitScale)
 }
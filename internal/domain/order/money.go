@@ -0,0 +1,179 @@
+package pvz_domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultCurrency = "RUB"
+
+	// minorUnitDigits is the number of fractional digits kept for every
+	// supported currency (kopecks for RUB).
+	minorUnitDigits = 2
+	minorUnitScale  = 100
+
+	// MaxMoneyAmount is the largest amount, in minor units, that the NUMERIC(14, 2)
+	// columns hold.
+	MaxMoneyAmount int64 = 99_999_999_999_999
+)
+
+var (
+	ErrInvalidMoney     = errors.New("invalid money amount")
+	ErrCurrencyMismatch = errors.New("money currencies do not match")
+)
+
+// Money is an exact monetary amount stored as an integer number of minor units.
+type Money struct {
+	Amount   int64
+	Currency string
+}
+
+func NewMoney(amount int64, currency string) Money {
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	return Money{Amount: amount, Currency: currency}
+}
+
+// MoneyFromFloat converts a legacy floating point amount, rounding to the nearest minor unit.
+func MoneyFromFloat(value float64, currency string) Money {
+	return NewMoney(int64(math.Round(value*minorUnitScale)), currency)
+}
+
+// ParseMoney parses a decimal string such as "120.50" without going through float64.
+// Amounts beyond MaxMoneyAmount are rejected.
+func ParseMoney(value string, currency string) (Money, error) {
+	s := strings.TrimSpace(value)
+	negative := false
+	if strings.HasPrefix(s, "-") || strings.HasPrefix(s, "+") {
+		negative = s[0] == '-'
+		s = s[1:]
+	}
+
+	whole, fraction, hasPoint := strings.Cut(s, ".")
+	if whole == "" || (hasPoint && fraction == "") || len(fraction) > minorUnitDigits {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidMoney, value)
+	}
+	fraction += strings.Repeat("0", minorUnitDigits-len(fraction))
+
+	if strings.ContainsAny(whole+fraction, "+-") {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidMoney, value)
+	}
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > MaxMoneyAmount/minorUnitScale {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidMoney, value)
+	}
+	minor, err := strconv.ParseInt(fraction, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidMoney, value)
+	}
+
+	amount := units*minorUnitScale + minor
+	if negative {
+		amount = -amount
+	}
+	return NewMoney(amount, currency), nil
+}
+
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != "" && other.Currency != "" && m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	currency := m.Currency
+	if currency == "" {
+		currency = other.Currency
+	}
+	return NewMoney(m.Amount+other.Amount, currency), nil
+}
+
+// AddMinor returns m increased by amount minor units of the same currency.
+func (m Money) AddMinor(amount int64) Money {
+	return NewMoney(m.Amount+amount, m.Currency)
+}
+
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// Float64 is only meant for legacy representations that cannot carry exact amounts.
+func (m Money) Float64() float64 {
+	return float64(m.Amount) / minorUnitScale
+}
+
+// Decimal formats the amount as a plain decimal string, e.g. "120.50".
+func (m Money) Decimal() string {
+	amount := m.Amount
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+	}
+	abs := uint64(amount)
+	if amount < 0 {
+		abs = uint64(-(amount + 1)) + 1
+	}
+	return fmt.Sprintf("%s%d.%0*d", sign, abs/minorUnitScale, minorUnitDigits, abs%minorUnitScale)
+}
+
+func (m Money) String() string {
+	return m.Decimal() + " " + NewMoney(0, m.Currency).Currency
+}
+
+type moneyJSON struct {
+	Amount   json.RawMessage `json:"amount"`
+	Currency string          `json:"currency"`
+}
+
+// MarshalJSON encodes the amount as a decimal string so that clients never round it.
+func (m Money) MarshalJSON() ([]byte, error) {
+	amount, err := json.Marshal(m.Decimal())
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(moneyJSON{
+		Amount:   amount,
+		Currency: NewMoney(0, m.Currency).Currency,
+	})
+}
+
+// UnmarshalJSON accepts {"amount": "120.50", "currency": "RUB"} as well as a bare
+// number or string, which is interpreted in DefaultCurrency.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	currency := DefaultCurrency
+	amount := data
+	if len(data) > 0 && data[0] == '{' {
+		var v moneyJSON
+		if err := json.Unmarshal(data, &v); err != nil {
+			return err
+		}
+		if v.Currency != "" {
+			currency = v.Currency
+		}
+		amount = bytes.TrimSpace(v.Amount)
+	}
+
+	raw := string(amount)
+	if len(amount) > 0 && amount[0] == '"' {
+		if err := json.Unmarshal(amount, &raw); err != nil {
+			return err
+		}
+	}
+
+	parsed, err := ParseMoney(raw, currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
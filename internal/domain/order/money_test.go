@@ -0,0 +1,166 @@
+package pvz_domain
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    Money
+		wantErr bool
+	}{
+		{
+			name:  "parses integer amount",
+			value: "120",
+			want:  NewMoney(12000, DefaultCurrency),
+		},
+		{
+			name:  "parses amount with kopecks",
+			value: "0.10",
+			want:  NewMoney(10, DefaultCurrency),
+		},
+		{
+			name:  "parses amount with single fractional digit",
+			value: "99.9",
+			want:  NewMoney(9990, DefaultCurrency),
+		},
+		{
+			name:  "parses negative amount",
+			value: "-1.05",
+			want:  NewMoney(-105, DefaultCurrency),
+		},
+		{
+			name:    "rejects sub-kopeck precision",
+			value:   "1.005",
+			wantErr: true,
+		},
+		{
+			name:    "rejects garbage",
+			value:   "1.2x",
+			wantErr: true,
+		},
+		{
+			name:    "rejects empty fraction",
+			value:   "1.",
+			wantErr: true,
+		},
+		{
+			name:  "parses the largest amount the column holds",
+			value: "999999999999.99",
+			want:  NewMoney(MaxMoneyAmount, DefaultCurrency),
+		},
+		{
+			name:  "parses the smallest amount the column holds",
+			value: "-999999999999.99",
+			want:  NewMoney(-MaxMoneyAmount, DefaultCurrency),
+		},
+		{
+			name:    "rejects amount beyond the column",
+			value:   "1000000000000",
+			wantErr: true,
+		},
+		{
+			name:    "rejects amount that would overflow int64",
+			value:   "92233720368547758.08",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMoney(tt.value, DefaultCurrency)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMoney() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseMoney() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoney_Add(t *testing.T) {
+	sum := NewMoney(0, DefaultCurrency)
+	for i := 0; i < 10; i++ {
+		var err error
+		if sum, err = sum.Add(NewMoney(10, DefaultCurrency)); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+	if sum.Decimal() != "1.00" {
+		t.Errorf("Add() = %s, want 1.00", sum.Decimal())
+	}
+
+	if _, err := sum.Add(NewMoney(1, "USD")); !errors.Is(err, ErrCurrencyMismatch) {
+		t.Errorf("Add() error = %v, want %v", err, ErrCurrencyMismatch)
+	}
+}
+
+func TestMoney_Decimal(t *testing.T) {
+	tests := []struct {
+		amount int64
+		want   string
+	}{
+		{amount: 0, want: "0.00"},
+		{amount: 5, want: "0.05"},
+		{amount: 12150, want: "121.50"},
+		{amount: -105, want: "-1.05"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := NewMoney(tt.amount, DefaultCurrency).Decimal(); got != tt.want {
+				t.Errorf("Decimal() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoney_JSON(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want Money
+	}{
+		{
+			name: "decodes object with string amount",
+			data: `{"amount":"120.10","currency":"RUB"}`,
+			want: NewMoney(12010, "RUB"),
+		},
+		{
+			name: "decodes object with number amount",
+			data: `{"amount":0.3,"currency":"USD"}`,
+			want: NewMoney(30, "USD"),
+		},
+		{
+			name: "decodes bare number in default currency",
+			data: `121.1`,
+			want: NewMoney(12110, DefaultCurrency),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Money
+			if err := json.Unmarshal([]byte(tt.data), &got); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Unmarshal() = %v, want %v", got, tt.want)
+			}
+
+			encoded, err := json.Marshal(got)
+			if err != nil {
+				t.Fatalf("Marshal() error = %v", err)
+			}
+			var decoded Money
+			if err := json.Unmarshal(encoded, &decoded); err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
+			if decoded != tt.want {
+				t.Errorf("round trip = %v, want %v", decoded, tt.want)
+			}
+		})
+	}
+}
@@ -1,33 +1,66 @@
 package pvz_domain
 
 import (
+	"errors"
+	"fmt"
 	"time"
 )
 
+var ErrOrderNotFound = errors.New("order not found")
+
+// Errors of operations the status of the order does not allow, see IsOrderStateError.
+var (
+	ErrOrderNotReceived      = errors.New("order is not received at the pickup point")
+	ErrOrderNotExpired       = errors.New("order storage period has not expired")
+	ErrOrderNotTransferable  = errors.New("order must be received and not expired to be transferred")
+	ErrOrderNotInTransfer    = errors.New("order is not in transfer")
+	ErrOrderNotRefundable    = errors.New("order refund period has expired or it was refunded already")
+	ErrOrderHasNoItems       = errors.New("order has no items")
+	ErrInvalidTransferTarget = errors.New("order can not be transferred to the pickup point")
+)
+
+// IsOrderStateError reports whether err is an operation the order, or its items,
+// can't undergo in their current status rather than a failure.
+func IsOrderStateError(err error) bool {
+	return errors.Is(err, ErrOrderNotReceived) ||
+		errors.Is(err, ErrOrderNotExpired) ||
+		errors.Is(err, ErrOrderNotTransferable) ||
+		errors.Is(err, ErrOrderNotInTransfer) ||
+		errors.Is(err, ErrOrderNotRefundable) ||
+		errors.Is(err, ErrOrderHasNoItems) ||
+		errors.Is(err, ErrOrderItemState)
+}
+
 type OrderStatus string
 
 type Order struct {
 	ID             int64         `json:"id"`
+	PickupPointID  int64         `json:"pickup_point_id"`
 	RecipientID    int64         `json:"recipient_id"`
 	ExpirationDate time.Time     `json:"expiration_date"`
 	DeliveredDate  *time.Time    `json:"delivered_date"`
 	RefundedDate   *time.Time    `json:"refunded_date"`
 	ReturnedDate   *time.Time    `json:"returned_date"`
 	Status         OrderStatus   `json:"status"`
+	CellID         *int64        `json:"cell_id"`
 	History        []OrderRecord `json:"history"`
 	Weight         float64       `json:"weight"`
-	Worth          float64       `json:"worth"`
+	Worth          Money         `json:"worth"`
+	Items          []*OrderItem  `json:"items"`
+	Version        int64         `json:"version"`
 }
 
 type OrderParams struct {
-	RecipientId    int64     `json:"recipient_id"`
-	ExpirationDate time.Time `json:"expiration_date"`
-	Weight         float64   `json:"weight"`
-	Worth          float64   `json:"worth"`
+	RecipientId    int64             `json:"recipient_id"`
+	ExpirationDate time.Time         `json:"expiration_date"`
+	Weight         float64           `json:"weight"`
+	Worth          Money             `json:"worth"`
+	Items          []OrderItemParams `json:"items"`
 }
 
-func NewOrder(data *OrderParams) *Order {
+func NewOrder(pickupPointID int64, data *OrderParams) *Order {
 	return &Order{
+		PickupPointID:  pickupPointID,
 		ExpirationDate: data.ExpirationDate,
 		RecipientID:    data.RecipientId,
 		Status:         OrderStatusNone,
@@ -49,13 +82,40 @@ func (o *Order) IsReceived() bool {
 	return o.Status == OrderStatusReceived
 }
 
+func (o *Order) IsPartiallyDelivered() bool {
+	return o.Status == OrderStatusPartiallyDelivered
+}
+
+func (o *Order) IsPartiallyRefunded() bool {
+	return o.Status == OrderStatusPartiallyRefunded
+}
+
+func (o *Order) IsInTransfer() bool {
+	return o.Status == OrderStatusInTransfer
+}
+
 func (o *Order) IsExpired() bool {
 	res := o.ExpirationDate.Compare(time.Now())
 	return res == -1
 }
 
+// CanBeDelivered reports whether some of the order is still waiting for the recipient.
+func (o *Order) CanBeDelivered() bool {
+	return o.IsReceived() || o.IsPartiallyDelivered()
+}
+
+// HasItemsToDeliver reports whether the order still keeps undelivered items at the pickup point.
+func (o *Order) HasItemsToDeliver() bool {
+	for _, item := range o.Items {
+		if item.IsReceived() {
+			return true
+		}
+	}
+	return false
+}
+
 func (o *Order) CanBeRefunded() bool {
-	if !o.IsDelivered() {
+	if !o.IsDelivered() && !o.IsPartiallyDelivered() && !o.IsPartiallyRefunded() {
 		return false
 	}
 	const DaysForRefunding = 2
@@ -64,26 +124,210 @@ func (o *Order) CanBeRefunded() bool {
 	return canBeRefunded
 }
 
+func (o *Order) CanBeTransferred() bool {
+	return o.IsReceived() && !o.IsExpired()
+}
+
+// StartTransfer moves the order to the target pickup point, where it waits in transfer until accepted.
+func (o *Order) StartTransfer(targetPickupPointID int64) {
+	o.PickupPointID = targetPickupPointID
+	o.setStatus(OrderStatusInTransfer)
+}
+
 func (o *Order) Refund() {
 	now := time.Now()
 	o.RefundedDate = &now
+	for _, item := range o.Items {
+		if item.IsDelivered() {
+			item.Status = OrderStatusRefunded
+		}
+	}
 	o.setStatus(OrderStatusRefunded)
 }
 
 func (o *Order) Deliver() {
 	now := time.Now()
 	o.DeliveredDate = &now
+	for _, item := range o.Items {
+		if item.IsReceived() {
+			item.Status = OrderStatusDelivered
+		}
+	}
 	o.setStatus(OrderStatusDelivered)
 }
 
+// AddItems attaches line items to a new order. Weight and worth of an order with
+// items are the sums over its items.
+func (o *Order) AddItems(params []OrderItemParams) error {
+	if len(params) == 0 {
+		return nil
+	}
+
+	weight := 0.0
+	worth := NewMoney(0, params[0].Price.Currency)
+	for _, p := range params {
+		if err := p.Validate(); err != nil {
+			return err
+		}
+
+		item := NewOrderItem(p)
+
+		itemTotal, err := item.Total()
+		if err != nil {
+			return err
+		}
+		total, err := worth.Add(itemTotal)
+		if err != nil {
+			return err
+		}
+		if total.Amount > MaxMoneyAmount {
+			return fmt.Errorf("%w: worth of the order exceeds %s", ErrInvalidMoney, NewMoney(MaxMoneyAmount, total.Currency))
+		}
+		worth = total
+		weight += item.TotalWeight()
+
+		o.Items = append(o.Items, item)
+	}
+
+	o.Weight = weight
+	o.Worth = worth
+	return nil
+}
+
+// DeliverItems hands over the given received items, or every received item when
+// itemIDs is empty, and returns the items that changed.
+func (o *Order) DeliverItems(itemIDs []int64) ([]*OrderItem, error) {
+	if len(o.Items) == 0 {
+		if len(itemIDs) > 0 {
+			return nil, fmt.Errorf("%w: order %d", ErrOrderHasNoItems, o.ID)
+		}
+		o.Deliver()
+		return nil, nil
+	}
+
+	items, err := o.selectItems(itemIDs, OrderStatusReceived)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	o.DeliveredDate = &now
+	for _, item := range items {
+		item.Status = OrderStatusDelivered
+	}
+	o.recomputeStatus()
+
+	return items, nil
+}
+
+// RefundItems takes back the given delivered items, or every delivered item when
+// itemIDs is empty, and returns the items that changed.
+func (o *Order) RefundItems(itemIDs []int64) ([]*OrderItem, error) {
+	if len(o.Items) == 0 {
+		if len(itemIDs) > 0 {
+			return nil, fmt.Errorf("%w: order %d", ErrOrderHasNoItems, o.ID)
+		}
+		o.Refund()
+		return nil, nil
+	}
+
+	items, err := o.selectItems(itemIDs, OrderStatusDelivered)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	o.RefundedDate = &now
+	for _, item := range items {
+		item.Status = OrderStatusRefunded
+	}
+	o.recomputeStatus()
+
+	return items, nil
+}
+
+func (o *Order) selectItems(itemIDs []int64, status OrderStatus) ([]*OrderItem, error) {
+	var selected []*OrderItem
+
+	if len(itemIDs) == 0 {
+		for _, item := range o.Items {
+			if item.Status == status {
+				selected = append(selected, item)
+			}
+		}
+	}
+
+	for _, id := range itemIDs {
+		var found *OrderItem
+		for _, item := range o.Items {
+			if item.ID == id {
+				found = item
+				break
+			}
+		}
+		if found == nil {
+			return nil, fmt.Errorf("%w: item %d is not part of order %d", ErrInvalidOrderItem, id, o.ID)
+		}
+		if found.Status != status {
+			return nil, fmt.Errorf("%w: item %d of order %d is %s", ErrOrderItemState, id, o.ID, found.Status)
+		}
+		selected = append(selected, found)
+	}
+
+	if len(selected) == 0 {
+		return nil, fmt.Errorf("%w: order %d has no %s items", ErrOrderItemState, o.ID, status)
+	}
+
+	return selected, nil
+}
+
+// recomputeStatus derives the order status from the statuses of its items.
+func (o *Order) recomputeStatus() {
+	var received, refunded int
+	for _, item := range o.Items {
+		switch item.Status {
+		case OrderStatusReceived:
+			received++
+		case OrderStatusRefunded:
+			refunded++
+		}
+	}
+
+	switch {
+	case received == len(o.Items):
+		o.setStatus(OrderStatusReceived)
+	case received > 0:
+		o.setStatus(OrderStatusPartiallyDelivered)
+	case refunded == len(o.Items):
+		o.setStatus(OrderStatusRefunded)
+	case refunded > 0:
+		o.setStatus(OrderStatusPartiallyRefunded)
+	default:
+		o.setStatus(OrderStatusDelivered)
+	}
+}
+
 func (o *Order) Expire() {
 	o.setStatus(OrderStatusExpired)
 }
 
 func (o *Order) Received() {
+	for _, item := range o.Items {
+		if item.Status == OrderStatusNone {
+			item.Status = OrderStatusReceived
+		}
+	}
 	o.setStatus(OrderStatusReceived)
 }
 
+func (o *Order) PlaceInCell(cellID int64) {
+	o.CellID = &cellID
+}
+
+func (o *Order) TakeFromCell() {
+	o.CellID = nil
+}
+
 func (o *Order) setStatus(status OrderStatus) {
 	o.Status = status
 }
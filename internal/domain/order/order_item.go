@@ -0,0 +1,74 @@
+package pvz_domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidOrderItem = errors.New("invalid order item")
+	ErrOrderItemState   = errors.New("order item is not in a suitable state")
+)
+
+// OrderItem is a line of a multi-item order. Weight is per unit, Price is per unit.
+type OrderItem struct {
+	ID       int64       `json:"id"`
+	SKU      string      `json:"sku"`
+	Quantity int64       `json:"quantity"`
+	Price    Money       `json:"price"`
+	Weight   float64     `json:"weight"`
+	Status   OrderStatus `json:"status"`
+}
+
+type OrderItemParams struct {
+	SKU      string  `json:"sku"`
+	Quantity int64   `json:"quantity"`
+	Price    Money   `json:"price"`
+	Weight   float64 `json:"weight"`
+}
+
+func (p OrderItemParams) Validate() error {
+	if p.SKU == "" {
+		return fmt.Errorf("%w: sku is required", ErrInvalidOrderItem)
+	}
+	if p.Quantity <= 0 {
+		return fmt.Errorf("%w: quantity of %s must be positive", ErrInvalidOrderItem, p.SKU)
+	}
+	if p.Price.Amount < 0 {
+		return fmt.Errorf("%w: price of %s can't be negative", ErrInvalidOrderItem, p.SKU)
+	}
+	if p.Weight <= 0 {
+		return fmt.Errorf("%w: weight of %s must be positive", ErrInvalidOrderItem, p.SKU)
+	}
+	return nil
+}
+
+func NewOrderItem(params OrderItemParams) *OrderItem {
+	return &OrderItem{
+		SKU:      params.SKU,
+		Quantity: params.Quantity,
+		Price:    params.Price,
+		Weight:   params.Weight,
+		Status:   OrderStatusNone,
+	}
+}
+
+// Total is the price of all units of the item; totals beyond MaxMoneyAmount are rejected.
+func (i *OrderItem) Total() (Money, error) {
+	if i.Quantity > 0 && (i.Price.Amount > MaxMoneyAmount/i.Quantity || i.Price.Amount < -MaxMoneyAmount/i.Quantity) {
+		return Money{}, fmt.Errorf("%w: total of %d x %s exceeds %s", ErrInvalidMoney, i.Quantity, i.Price, NewMoney(MaxMoneyAmount, i.Price.Currency))
+	}
+	return NewMoney(i.Price.Amount*i.Quantity, i.Price.Currency), nil
+}
+
+func (i *OrderItem) TotalWeight() float64 {
+	return i.Weight * float64(i.Quantity)
+}
+
+func (i *OrderItem) IsReceived() bool {
+	return i.Status == OrderStatusReceived
+}
+
+func (i *OrderItem) IsDelivered() bool {
+	return i.Status == OrderStatusDelivered
+}
@@ -3,60 +3,122 @@ package pvz_domain
 import "time"
 
 const (
-	OrderStatusReceived  OrderStatus = "received"
-	OrderStatusReturned  OrderStatus = "returned"
-	OrderStatusDelivered OrderStatus = "delivered"
-	OrderStatusRefunded  OrderStatus = "refunded"
-	OrderStatusExpired   OrderStatus = "storage_ended"
-	OrderStatusNone      OrderStatus = "none"
+	OrderStatusReceived    OrderStatus = "received"
+	OrderStatusReturned    OrderStatus = "returned"
+	OrderStatusDelivered   OrderStatus = "delivered"
+	OrderStatusRefunded    OrderStatus = "refunded"
+	OrderStatusExpired     OrderStatus = "storage_ended"
+	OrderStatusInTransfer  OrderStatus = "in_transfer"
+	OrderStatusTransferred OrderStatus = "transferred"
+	OrderStatusNone        OrderStatus = "none"
+
+	OrderStatusPartiallyDelivered OrderStatus = "partially_delivered"
+	OrderStatusPartiallyRefunded  OrderStatus = "partially_refunded"
 )
 
 var OrderStatusDescription = map[OrderStatus]string{
-	OrderStatusReceived:  "Заказ получен от курьера",
-	OrderStatusRefunded:  "Заказ возвращен от клиента",
-	OrderStatusDelivered: "Заказ выдан клиенту",
-	OrderStatusExpired:   "Срок хранения заказа истек",
-	OrderStatusNone:      "",
+	OrderStatusReceived:    "Заказ получен от курьера",
+	OrderStatusRefunded:    "Заказ возвращен от клиента",
+	OrderStatusDelivered:   "Заказ выдан клиенту",
+	OrderStatusExpired:     "Срок хранения заказа истек",
+	OrderStatusInTransfer:  "Заказ в пути в другой пункт выдачи",
+	OrderStatusTransferred: "Заказ передан в другой пункт выдачи",
+	OrderStatusNone:        "",
+
+	OrderStatusPartiallyDelivered: "Заказ выдан клиенту частично",
+	OrderStatusPartiallyRefunded:  "Заказ возвращен от клиента частично",
+}
+
+var OrderItemStatusDescription = map[OrderStatus]string{
+	OrderStatusDelivered: "Товар выдан клиенту",
+	OrderStatusRefunded:  "Товар возвращен от клиента",
 }
 
 type OrderRecord struct {
-	Timestamp   time.Time   `json:"timestamp"`
-	Status      OrderStatus `json:"status"`
-	Description string      `json:"description"`
+	PickupPointID int64       `json:"pickup_point_id"`
+	ItemID        *int64      `json:"item_id,omitempty"`
+	Timestamp     time.Time   `json:"timestamp"`
+	Status        OrderStatus `json:"status"`
+	Description   string      `json:"description"`
 }
 
-func NewOrderRecordReceived() *OrderRecord {
+func NewOrderRecordReceived(pickupPointID int64) *OrderRecord {
 	Status := OrderStatusReceived
 	return &OrderRecord{
-		Timestamp:   time.Now(),
-		Status:      Status,
-		Description: OrderStatusDescription[Status],
+		PickupPointID: pickupPointID,
+		Timestamp:     time.Now(),
+		Status:        Status,
+		Description:   OrderStatusDescription[Status],
 	}
 }
 
-func NewOrderRecordRefunded() *OrderRecord {
+func NewOrderRecordRefunded(pickupPointID int64) *OrderRecord {
 	Status := OrderStatusRefunded
 	return &OrderRecord{
-		Timestamp:   time.Now(),
-		Status:      Status,
-		Description: OrderStatusDescription[Status],
+		PickupPointID: pickupPointID,
+		Timestamp:     time.Now(),
+		Status:        Status,
+		Description:   OrderStatusDescription[Status],
 	}
 }
 
-func NewOrderRecordDelivered() *OrderRecord {
+func NewOrderRecordDelivered(pickupPointID int64) *OrderRecord {
 	Status := OrderStatusDelivered
 	return &OrderRecord{
-		Timestamp:   time.Now(),
-		Status:      Status,
-		Description: OrderStatusDescription[Status],
+		PickupPointID: pickupPointID,
+		Timestamp:     time.Now(),
+		Status:        Status,
+		Description:   OrderStatusDescription[Status],
 	}
 }
 
-func NewOrderRecordExpired() *OrderRecord {
+func NewOrderRecordExpired(pickupPointID int64) *OrderRecord {
 	Status := OrderStatusExpired
 	return &OrderRecord{
-		Timestamp:   time.Now(),
-		Status:      Status,
-		Description: OrderStatusDescription[Status],
+		PickupPointID: pickupPointID,
+		Timestamp:     time.Now(),
+		Status:        Status,
+		Description:   OrderStatusDescription[Status],
+	}
+}
+
+func NewOrderRecordInTransfer(pickupPointID int64) *OrderRecord {
+	Status := OrderStatusInTransfer
+	return &OrderRecord{
+		PickupPointID: pickupPointID,
+		Timestamp:     time.Now(),
+		Status:        Status,
+		Description:   OrderStatusDescription[Status],
+	}
+}
+
+func NewOrderRecordTransferred(pickupPointID int64) *OrderRecord {
+	Status := OrderStatusTransferred
+	return &OrderRecord{
+		PickupPointID: pickupPointID,
+		Timestamp:     time.Now(),
+		Status:        Status,
+		Description:   OrderStatusDescription[Status],
+	}
+}
+
+// NewOrderRecord records the order reaching status, e.g. a status recomputed from its items.
+func NewOrderRecord(pickupPointID int64, status OrderStatus) *OrderRecord {
+	return &OrderRecord{
+		PickupPointID: pickupPointID,
+		Timestamp:     time.Now(),
+		Status:        status,
+		Description:   OrderStatusDescription[status],
+	}
+}
+
+func NewOrderItemRecord(pickupPointID int64, item *OrderItem) *OrderRecord {
+	itemID := item.ID
+	return &OrderRecord{
+		PickupPointID: pickupPointID,
+		ItemID:        &itemID,
+		Timestamp:     time.Now(),
+		Status:        item.Status,
+		Description:   OrderItemStatusDescription[item.Status],
 	}
 }
@@ -0,0 +1,30 @@
+package pvz_domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrStatusFeedInterrupted means a watcher fell behind or lost the feed and has to
+// resume from the last change it received.
+var ErrStatusFeedInterrupted = errors.New("order status feed interrupted")
+
+// OrderExportFilter narrows an export. Zero fields match every order.
+type OrderExportFilter struct {
+	Statuses      []OrderStatus
+	RecipientID   int64
+	ExpiresAfter  *time.Time
+	ExpiresBefore *time.Time
+}
+
+// OrderStatusChange is a committed history record of an order. RecordID grows with
+// every change and is what watchers resume from.
+type OrderStatusChange struct {
+	RecordID      int64
+	OrderID       int64
+	PickupPointID int64
+	ItemID        *int64
+	Status        OrderStatus
+	Description   string
+	Timestamp     time.Time
+}
@@ -1,6 +1,8 @@
 package pvz_domain
 
 import (
+	"errors"
+	"math"
 	"testing"
 	"time"
 )
@@ -16,7 +18,7 @@ func TestOrder_ApplyPackaging(t *testing.T) {
 		Status         OrderStatus
 		History        []OrderRecord
 		Weight         float64
-		Worth          float64
+		Worth          Money
 	}
 	type args struct {
 		packagingType      string
@@ -26,99 +28,99 @@ func TestOrder_ApplyPackaging(t *testing.T) {
 		name      string
 		fields    fields
 		args      args
-		wantWorth float64
+		wantWorth Money
 		wantErr   bool
 	}{
 		{
 			name: "applies box packaging",
 			fields: fields{
 				Weight: 20,
-				Worth:  100,
+				Worth:  NewMoney(10000, DefaultCurrency),
 			},
 			args: args{
 				packagingType: "box",
 			},
-			wantWorth: 120,
+			wantWorth: NewMoney(12000, DefaultCurrency),
 		},
 		{
 			name: "applies bag packaging",
 			fields: fields{
 				Weight: 10,
-				Worth:  100,
+				Worth:  NewMoney(10000, DefaultCurrency),
 			},
 			args: args{
 				packagingType: "bag",
 			},
-			wantWorth: 105,
+			wantWorth: NewMoney(10500, DefaultCurrency),
 		},
 		{
 			name: "applies membrana packaging",
 			fields: fields{
 				Weight: 25,
-				Worth:  100,
+				Worth:  NewMoney(10000, DefaultCurrency),
 			},
 			args: args{
 				packagingType: "membrana",
 			},
-			wantWorth: 101,
+			wantWorth: NewMoney(10100, DefaultCurrency),
 		},
 		{
 			name: "adds additional membrana to box packaging",
 			fields: fields{
 				Weight: 20,
-				Worth:  100,
+				Worth:  NewMoney(10000, DefaultCurrency),
 			},
 			args: args{
 				packagingType:      "box",
 				additionalMembrana: true,
 			},
-			wantWorth: 121,
+			wantWorth: NewMoney(12100, DefaultCurrency),
 		},
 		{
 			name: "does not add additional membrana to membrana packaging",
 			fields: fields{
 				Weight: 25,
-				Worth:  100,
+				Worth:  NewMoney(10000, DefaultCurrency),
 			},
 			args: args{
 				packagingType:      "membrana",
 				additionalMembrana: true,
 			},
-			wantWorth: 101,
+			wantWorth: NewMoney(10100, DefaultCurrency),
 		},
 		{
 			name: "uses box packaging by default",
 			fields: fields{
 				Weight: 20,
-				Worth:  100,
+				Worth:  NewMoney(10000, DefaultCurrency),
 			},
 			args: args{
 				packagingType: "unknown",
 			},
-			wantWorth: 120,
+			wantWorth: NewMoney(12000, DefaultCurrency),
 		},
 		{
 			name: "returns error and keeps worth unchanged when bag is too heavy",
 			fields: fields{
 				Weight: 10.01,
-				Worth:  100,
+				Worth:  NewMoney(10000, DefaultCurrency),
 			},
 			args: args{
 				packagingType: "bag",
 			},
-			wantWorth: 100,
+			wantWorth: NewMoney(10000, DefaultCurrency),
 			wantErr:   true,
 		},
 		{
 			name: "returns error and keeps worth unchanged when box is too heavy",
 			fields: fields{
 				Weight: 20.01,
-				Worth:  100,
+				Worth:  NewMoney(10000, DefaultCurrency),
 			},
 			args: args{
 				packagingType: "box",
 			},
-			wantWorth: 100,
+			wantWorth: NewMoney(10000, DefaultCurrency),
 			wantErr:   true,
 		},
 	}
@@ -145,3 +147,131 @@ func TestOrder_ApplyPackaging(t *testing.T) {
 		})
 	}
 }
+
+func newTestOrderWithItems(t *testing.T) *Order {
+	t.Helper()
+
+	order := NewOrder(1, &OrderParams{RecipientId: 1, ExpirationDate: time.Now().Add(time.Hour)})
+	err := order.AddItems([]OrderItemParams{
+		{SKU: "phone", Quantity: 1, Price: NewMoney(1000000, DefaultCurrency), Weight: 0.5},
+		{SKU: "case", Quantity: 2, Price: NewMoney(50000, DefaultCurrency), Weight: 0.1},
+	})
+	if err != nil {
+		t.Fatalf("AddItems() error = %v", err)
+	}
+	for i, item := range order.Items {
+		item.ID = int64(i + 1)
+	}
+	order.Received()
+	return order
+}
+
+func TestOrder_AddItems(t *testing.T) {
+	order := newTestOrderWithItems(t)
+
+	if got := order.Worth; got != NewMoney(1100000, DefaultCurrency) {
+		t.Errorf("Worth = %v, want 11000.00 RUB", got)
+	}
+	if got := order.Weight; got < 0.69 || got > 0.71 {
+		t.Errorf("Weight = %v, want 0.7", got)
+	}
+	for _, item := range order.Items {
+		if !item.IsReceived() {
+			t.Errorf("item %s status = %s, want %s", item.SKU, item.Status, OrderStatusReceived)
+		}
+	}
+
+	if err := NewOrder(1, &OrderParams{}).AddItems([]OrderItemParams{{SKU: "phone", Weight: 1}}); err == nil {
+		t.Error("AddItems() accepted an item without quantity")
+	}
+
+	maxPrice := NewMoney(MaxMoneyAmount, DefaultCurrency)
+	if err := NewOrder(1, &OrderParams{}).AddItems([]OrderItemParams{
+		{SKU: "phone", Quantity: 1, Price: maxPrice, Weight: 1},
+		{SKU: "case", Quantity: 1, Price: NewMoney(1, DefaultCurrency), Weight: 1},
+	}); !errors.Is(err, ErrInvalidMoney) {
+		t.Errorf("AddItems() error = %v, want %v for worth beyond the column", err, ErrInvalidMoney)
+	}
+}
+
+func TestOrderItem_Total(t *testing.T) {
+	tests := []struct {
+		name    string
+		item    OrderItem
+		want    Money
+		wantErr bool
+	}{
+		{
+			name: "multiplies price by quantity",
+			item: OrderItem{Quantity: 3, Price: NewMoney(1050, DefaultCurrency)},
+			want: NewMoney(3150, DefaultCurrency),
+		},
+		{
+			name: "accepts the largest total the column holds",
+			item: OrderItem{Quantity: 1, Price: NewMoney(MaxMoneyAmount, DefaultCurrency)},
+			want: NewMoney(MaxMoneyAmount, DefaultCurrency),
+		},
+		{
+			name:    "rejects total beyond the column",
+			item:    OrderItem{Quantity: 2, Price: NewMoney(MaxMoneyAmount/2+1, DefaultCurrency)},
+			wantErr: true,
+		},
+		{
+			name:    "rejects total that would overflow int64",
+			item:    OrderItem{Quantity: math.MaxInt64, Price: NewMoney(2, DefaultCurrency)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.item.Total()
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidMoney) {
+					t.Fatalf("Total() error = %v, want %v", err, ErrInvalidMoney)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Total() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Total() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrder_PartialHandover(t *testing.T) {
+	tests := []struct {
+		name       string
+		deliver    []int64
+		refund     []int64
+		wantStatus OrderStatus
+		wantErr    bool
+	}{
+		{name: "delivers one of two items", deliver: []int64{1}, wantStatus: OrderStatusPartiallyDelivered},
+		{name: "delivers every item", deliver: []int64{1, 2}, wantStatus: OrderStatusDelivered},
+		{name: "delivers the whole order by default", wantStatus: OrderStatusDelivered},
+		{name: "refunds one of delivered items", deliver: []int64{1, 2}, refund: []int64{2}, wantStatus: OrderStatusPartiallyRefunded},
+		{name: "refunds every delivered item", deliver: []int64{1, 2}, refund: []int64{1, 2}, wantStatus: OrderStatusRefunded},
+		{name: "keeps remaining item after refund", deliver: []int64{1}, refund: []int64{1}, wantStatus: OrderStatusPartiallyDelivered},
+		{name: "rejects refund of undelivered item", deliver: []int64{1}, refund: []int64{2}, wantErr: true},
+		{name: "rejects unknown item", deliver: []int64{3}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := newTestOrderWithItems(t)
+
+			_, err := order.DeliverItems(tt.deliver)
+			if err == nil && tt.refund != nil {
+				_, err = order.RefundItems(tt.refund)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && order.Status != tt.wantStatus {
+				t.Errorf("Status = %v, want %v", order.Status, tt.wantStatus)
+			}
+		})
+	}
+}
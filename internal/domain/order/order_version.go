@@ -0,0 +1,33 @@
+package pvz_domain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrOrderVersionConflict means the order changed between reading and writing it.
+	ErrOrderVersionConflict = errors.New("order was modified concurrently")
+	// ErrOrderVersionMismatch means the client acted on an outdated version of the order.
+	ErrOrderVersionMismatch = errors.New("order version does not match")
+)
+
+type expectedVersionCtxKey struct{}
+
+// WithExpectedVersion makes mutations in ctx fail unless the order is still at version.
+func WithExpectedVersion(ctx context.Context, version int64) context.Context {
+	return context.WithValue(ctx, expectedVersionCtxKey{}, version)
+}
+
+func ExpectedVersionFromContext(ctx context.Context) (int64, bool) {
+	version, ok := ctx.Value(expectedVersionCtxKey{}).(int64)
+	return version, ok
+}
+
+func (o *Order) MatchVersion(expected int64) error {
+	if o.Version != expected {
+		return fmt.Errorf("%w: order %d is at version %d, not %d", ErrOrderVersionMismatch, o.ID, o.Version, expected)
+	}
+	return nil
+}
@@ -4,7 +4,7 @@ import "errors"
 
 type PackagingStrategy interface {
 	Validate(weight float64) error
-	CalculateWorth(baseCost float64) float64
+	CalculateWorth(baseCost Money) Money
 }
 
 type PackagingBagStrategy struct{}
@@ -17,15 +17,15 @@ func (pbs *PackagingBagStrategy) Validate(weight float64) error {
 	}
 	return nil
 }
-func (pbs *PackagingBagStrategy) CalculateWorth(baseCost float64) float64 {
-	return baseCost + 5
+func (pbs *PackagingBagStrategy) CalculateWorth(baseCost Money) Money {
+	return baseCost.AddMinor(5 * minorUnitScale)
 }
 
 func (pbs *PackagingMembranaStrategy) Validate(_ float64) error {
 	return nil
 }
-func (pbs *PackagingMembranaStrategy) CalculateWorth(baseCost float64) float64 {
-	return baseCost + 1
+func (pbs *PackagingMembranaStrategy) CalculateWorth(baseCost Money) Money {
+	return baseCost.AddMinor(1 * minorUnitScale)
 }
 
 func (pbs *PackagingBoxStrategy) Validate(weight float64) error {
@@ -34,8 +34,8 @@ func (pbs *PackagingBoxStrategy) Validate(weight float64) error {
 	}
 	return nil
 }
-func (pbs *PackagingBoxStrategy) CalculateWorth(baseCost float64) float64 {
-	return baseCost + 20
+func (pbs *PackagingBoxStrategy) CalculateWorth(baseCost Money) Money {
+	return baseCost.AddMinor(20 * minorUnitScale)
 }
 
 func GetPackagingStrategy(packagingType string) PackagingStrategy {
@@ -0,0 +1,110 @@
+package pvz_domain
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"time"
+)
+
+const pickupCodeDigits = 6
+
+var (
+	ErrPickupCodeRequired = errors.New("pickup code is required")
+	ErrPickupCodeInvalid  = errors.New("pickup code is invalid")
+	ErrPickupCodeExpired  = errors.New("pickup code has expired")
+	ErrPickupCodeLocked   = errors.New("pickup code is locked after too many failed attempts")
+	// ErrPickupCodeNotIssued means the order has no code to verify, e.g. it was used
+	// up by a partial handover. RegeneratePickupCode issues a new one.
+	ErrPickupCodeNotIssued = errors.New("no pickup code is issued for the order, regenerate it")
+)
+
+// IsPickupCodeError reports whether err is a rejected pickup code rather than a failure.
+func IsPickupCodeError(err error) bool {
+	return errors.Is(err, ErrPickupCodeRequired) ||
+		errors.Is(err, ErrPickupCodeInvalid) ||
+		errors.Is(err, ErrPickupCodeExpired) ||
+		errors.Is(err, ErrPickupCodeLocked)
+}
+
+// PickupCode is the stored, hashed form of the one-time code a recipient shows at handover.
+type PickupCode struct {
+	OrderID     int64
+	CodeHash    string
+	ExpiresAt   time.Time
+	Attempts    int64
+	LockedUntil *time.Time
+}
+
+func (c *PickupCode) IsLocked(now time.Time) bool {
+	return c.LockedUntil != nil && now.Before(*c.LockedUntil)
+}
+
+// PickupCodePolicy issues and verifies pickup codes. Codes are stored as HMAC
+// digests, so a leaked table cannot be brute-forced without the secret.
+type PickupCodePolicy struct {
+	Secret      []byte
+	TTL         time.Duration
+	MaxAttempts int64
+	Lockout     time.Duration
+}
+
+// Issue generates a new code for the order and returns it together with its stored form.
+func (p PickupCodePolicy) Issue(orderID int64, now time.Time) (*PickupCode, string, error) {
+	limit := big.NewInt(1)
+	for i := 0; i < pickupCodeDigits; i++ {
+		limit.Mul(limit, big.NewInt(10))
+	}
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return nil, "", err
+	}
+	code := fmt.Sprintf("%0*d", pickupCodeDigits, n.Int64())
+
+	return &PickupCode{
+		OrderID:   orderID,
+		CodeHash:  p.hash(orderID, code),
+		ExpiresAt: now.Add(p.TTL),
+	}, code, nil
+}
+
+// Verify checks code against the stored one and records failed attempts on it.
+// After MaxAttempts failures in a row the code is locked for Lockout.
+func (p PickupCodePolicy) Verify(stored *PickupCode, code string, now time.Time) error {
+	if code == "" {
+		return ErrPickupCodeRequired
+	}
+	if stored.IsLocked(now) {
+		return ErrPickupCodeLocked
+	}
+	if !now.Before(stored.ExpiresAt) {
+		return ErrPickupCodeExpired
+	}
+
+	if !hmac.Equal([]byte(p.hash(stored.OrderID, code)), []byte(stored.CodeHash)) {
+		stored.Attempts++
+		if stored.Attempts >= p.MaxAttempts {
+			lockedUntil := now.Add(p.Lockout)
+			stored.LockedUntil = &lockedUntil
+			stored.Attempts = 0
+		}
+		return ErrPickupCodeInvalid
+	}
+
+	stored.Attempts = 0
+	stored.LockedUntil = nil
+	return nil
+}
+
+func (p PickupCodePolicy) hash(orderID int64, code string) string {
+	mac := hmac.New(sha256.New, p.Secret)
+	mac.Write([]byte(strconv.FormatInt(orderID, 10)))
+	mac.Write([]byte{':'})
+	mac.Write([]byte(code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,52 @@
+package pvz_domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPickupCodePolicy_Verify(t *testing.T) {
+	policy := PickupCodePolicy{
+		Secret:      []byte("secret"),
+		TTL:         time.Hour,
+		MaxAttempts: 2,
+		Lockout:     time.Minute,
+	}
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	stored, code, err := policy.Issue(1, now)
+	if err != nil {
+		t.Fatalf("Issue() error = %v", err)
+	}
+	if len(code) != pickupCodeDigits {
+		t.Fatalf("Issue() code = %q, want %d digits", code, pickupCodeDigits)
+	}
+	if stored.CodeHash == code {
+		t.Fatal("Issue() stores the code in plain form")
+	}
+
+	wrong := "x" + code[1:]
+
+	tests := []struct {
+		name    string
+		code    string
+		now     time.Time
+		wantErr error
+	}{
+		{name: "rejects empty code", code: "", now: now, wantErr: ErrPickupCodeRequired},
+		{name: "rejects wrong code", code: wrong, now: now, wantErr: ErrPickupCodeInvalid},
+		{name: "locks after max attempts", code: wrong, now: now, wantErr: ErrPickupCodeInvalid},
+		{name: "rejects valid code while locked", code: code, now: now.Add(30 * time.Second), wantErr: ErrPickupCodeLocked},
+		{name: "accepts valid code after lock-out", code: code, now: now.Add(2 * time.Minute)},
+		{name: "rejects expired code", code: code, now: now.Add(2 * time.Hour), wantErr: ErrPickupCodeExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Verify(stored, tt.code, tt.now)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package pvz_domain
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrPickupPointRequired = errors.New("pickup point id is required")
+
+type pickupPointCtxKey struct{}
+
+// WithPickupPoint scopes ctx to the pickup point that serves the current request.
+func WithPickupPoint(ctx context.Context, pickupPointID int64) context.Context {
+	return context.WithValue(ctx, pickupPointCtxKey{}, pickupPointID)
+}
+
+func PickupPointFromContext(ctx context.Context) (int64, error) {
+	pickupPointID, ok := ctx.Value(pickupPointCtxKey{}).(int64)
+	if !ok || pickupPointID <= 0 {
+		return 0, ErrPickupPointRequired
+	}
+	return pickupPointID, nil
+}
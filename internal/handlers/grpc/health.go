@@ -0,0 +1,63 @@
+package pvz_grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/Staspol216/gh1/internal/health"
+	"github.com/Staspol216/gh1/pkg/api/orders.proto"
+	"github.com/Staspol216/gh1/pkg/api/orders/v1"
+	"github.com/Staspol216/gh1/pkg/logger"
+	"google.golang.org/grpc/health"
+	"google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// servedServices are reported as serving while no critical check is down, along
+// with the whole server under the empty name.
+var servedServices = []string{
+	"",
+	orders_v1.OrdersService_ServiceDesc.ServiceName,
+	orders_proto.OrdersService_ServiceDesc.ServiceName,
+}
+
+// ReportHealth runs the readiness checks every interval and publishes the outcome
+// through the grpc.health.v1 server. Every check is also reported by its own name,
+// so a degraded dependency can be watched without failing the whole server.
+// On ctx done the server is shut down, turning every status to not serving.
+func ReportHealth(ctx context.Context, server *health.Server, interval time.Duration, checks ...pvz_health.Check) {
+	report := func() {
+		result := pvz_health.Run(ctx, checks)
+
+		serving := grpc_health_v1.HealthCheckResponse_SERVING
+		if result.Status == pvz_health.StatusDown {
+			serving = grpc_health_v1.HealthCheckResponse_NOT_SERVING
+		}
+		for _, service := range servedServices {
+			server.SetServingStatus(service, serving)
+		}
+
+		for name, status := range result.Checks {
+			checkServing := grpc_health_v1.HealthCheckResponse_SERVING
+			if status != pvz_health.StatusUp {
+				checkServing = grpc_health_v1.HealthCheckResponse_NOT_SERVING
+			}
+			server.SetServingStatus(name, checkServing)
+		}
+	}
+
+	report()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			server.Shutdown()
+			app_logger.MyLogger.Info("gRPC health reporter finished by context done")
+			return
+		case <-ticker.C:
+			report()
+		}
+	}
+}
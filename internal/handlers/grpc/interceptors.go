@@ -0,0 +1,298 @@
+package pvz_grpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Staspol216/gh1/internal/domain/order"
+	"github.com/Staspol216/gh1/pkg/logger"
+	"github.com/Staspol216/gh1/pkg/monitoring"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const pickupPointIDMetadataKey = "x-pickup-point-id"
+
+// PickupPointInterceptor scopes every call to the pickup point passed in the
+// x-pickup-point-id metadata, falling back to defaultPickupPointID.
+func PickupPointInterceptor(defaultPickupPointID int64) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		pickupPointID, err := pickupPointFromMetadata(ctx, defaultPickupPointID)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(pvz_domain.WithPickupPoint(ctx, pickupPointID), req)
+	}
+}
+
+// PickupPointStreamInterceptor is PickupPointInterceptor for streaming calls.
+func PickupPointStreamInterceptor(defaultPickupPointID int64) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		pickupPointID, err := pickupPointFromMetadata(ss.Context(), defaultPickupPointID)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &contextServerStream{
+			ServerStream: ss,
+			ctx:          pvz_domain.WithPickupPoint(ss.Context(), pickupPointID),
+		})
+	}
+}
+
+func pickupPointFromMetadata(ctx context.Context, defaultPickupPointID int64) (int64, error) {
+	pickupPointID := defaultPickupPointID
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(pickupPointIDMetadataKey); len(values) > 0 {
+			parsed, err := strconv.ParseInt(strings.TrimSpace(values[0]), 10, 64)
+			if err != nil || parsed <= 0 {
+				return 0, status.Errorf(codes.InvalidArgument, "invalid %s metadata", pickupPointIDMetadataKey)
+			}
+			pickupPointID = parsed
+		}
+	}
+
+	if pickupPointID <= 0 {
+		return 0, status.Error(codes.InvalidArgument, pvz_domain.ErrPickupPointRequired.Error())
+	}
+
+	return pickupPointID, nil
+}
+
+const authorizationMetadataKey = "authorization"
+
+// staffMethods are the methods, of orders.v1 and the legacy package alike, that only
+// pickup point staff may call.
+var staffMethods = map[string]bool{
+	"RegeneratePickupCode": true,
+}
+
+// StaffInterceptor lets only callers sending "Bearer <staffToken>" in the
+// authorization metadata, which the HTTP gateway forwards from the Authorization
+// header, into staff methods. With an empty staffToken they are refused to everyone.
+func StaffInterceptor(staffToken string) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if !staffMethods[path.Base(info.FullMethod)] {
+			return handler(ctx, req)
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get(authorizationMetadataKey)
+		if len(values) == 0 {
+			return nil, status.Error(codes.Unauthenticated, "staff token is required")
+		}
+
+		token, ok := strings.CutPrefix(values[0], "Bearer ")
+		if !ok || staffToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(staffToken)) != 1 {
+			return nil, status.Error(codes.PermissionDenied, "staff token is invalid")
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+const ifMatchMetadataKey = "if-match"
+
+// ExpectedVersionInterceptor makes a mutation conditional on the order version
+// passed in the if-match metadata, as a bare number or as the quoted ETag the HTTP
+// gateway forwards from the If-Match header. Without it, or with "*", calls are
+// unconditional.
+func ExpectedVersionInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return handler(ctx, req)
+	}
+
+	values := md.Get(ifMatchMetadataKey)
+	if len(values) == 0 {
+		return handler(ctx, req)
+	}
+
+	value := strings.TrimSpace(values[0])
+	if value == "" || value == "*" {
+		return handler(ctx, req)
+	}
+
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		value = unquoted
+	}
+	version, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || version <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid %s metadata: %q", ifMatchMetadataKey, values[0])
+	}
+
+	return handler(pvz_domain.WithExpectedVersion(ctx, version), req)
+}
+
+// contextServerStream replaces the context of a server stream.
+type contextServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *contextServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// RecoveryInterceptor turns a panic of a handler into Internal instead of taking
+// the server down.
+func RecoveryInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (resp interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recoveredError(info.FullMethod, r)
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+// RecoveryStreamInterceptor is RecoveryInterceptor for streaming calls.
+func RecoveryStreamInterceptor(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recoveredError(info.FullMethod, r)
+		}
+	}()
+
+	return handler(srv, ss)
+}
+
+func recoveredError(method string, r interface{}) error {
+	app_logger.MyLogger.Error("gRPC handler panicked",
+		zap.String("method", method),
+		zap.Any("panic", r),
+		zap.Stack("stack"),
+	)
+	return status.Error(codes.Internal, "Internal service error")
+}
+
+// LoggingInterceptor logs every call with its status code. Calls that failed on
+// the server side are logged as errors, rejected ones as warnings.
+func LoggingInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	startTime := time.Now()
+	resp, err := handler(ctx, req)
+	logCall(info.FullMethod, err, time.Since(startTime))
+	return resp, err
+}
+
+// LoggingStreamInterceptor is LoggingInterceptor for streaming calls.
+func LoggingStreamInterceptor(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	startTime := time.Now()
+	err := handler(srv, ss)
+	logCall(info.FullMethod, err, time.Since(startTime))
+	return err
+}
+
+func logCall(method string, err error, duration time.Duration) {
+	code := status.Code(err)
+	fields := []zap.Field{
+		zap.String("method", method),
+		zap.String("code", code.String()),
+		zap.Duration("duration", duration),
+	}
+
+	switch code {
+	case codes.OK:
+		app_logger.MyLogger.Debug("gRPC call", fields...)
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unimplemented:
+		app_logger.MyLogger.Error("gRPC call failed", append(fields, zap.Error(err))...)
+	default:
+		app_logger.MyLogger.Warn("gRPC call rejected", append(fields, zap.Error(err))...)
+	}
+}
+
+// MetricsInterceptor records the outcome and duration of every call by method name.
+func MetricsInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	startTime := time.Now()
+	resp, err := handler(ctx, req)
+	monitoring.ObserveGRPCRequest(path.Base(info.FullMethod), err, time.Since(startTime))
+	return resp, err
+}
+
+// MetricsStreamInterceptor is MetricsInterceptor for streaming calls; the duration
+// is how long the stream stayed open.
+func MetricsStreamInterceptor(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	startTime := time.Now()
+	err := handler(srv, ss)
+	monitoring.ObserveGRPCRequest(path.Base(info.FullMethod), err, time.Since(startTime))
+	return err
+}
+
+// DeadlineInterceptor bounds unary calls that came without a deadline by
+// defaultTimeout. Streams are left alone, as watching orders is meant to last.
+func DeadlineInterceptor(defaultTimeout time.Duration) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if _, ok := ctx.Deadline(); ok || defaultTimeout <= 0 {
+			return handler(ctx, req)
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, defaultTimeout)
+		defer cancel()
+
+		return handler(ctx, req)
+	}
+}
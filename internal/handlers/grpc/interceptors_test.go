@@ -0,0 +1,172 @@
+package pvz_grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Staspol216/gh1/internal/domain/order"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestExpectedVersionInterceptor(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		md          metadata.MD
+		wantVersion int64
+		wantSet     bool
+		wantCode    codes.Code
+	}{
+		{
+			name: "no metadata",
+		},
+		{
+			name: "no if-match",
+			md:   metadata.Pairs(pickupPointIDMetadataKey, "7"),
+		},
+		{
+			name: "any version",
+			md:   metadata.Pairs(ifMatchMetadataKey, "*"),
+		},
+		{
+			name:        "bare version",
+			md:          metadata.Pairs(ifMatchMetadataKey, "3"),
+			wantVersion: 3,
+			wantSet:     true,
+		},
+		{
+			name:        "quoted etag forwarded by the gateway",
+			md:          metadata.Pairs(ifMatchMetadataKey, `"3"`),
+			wantVersion: 3,
+			wantSet:     true,
+		},
+		{
+			name:     "malformed version",
+			md:       metadata.Pairs(ifMatchMetadataKey, `W/"3"`),
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "version below one",
+			md:       metadata.Pairs(ifMatchMetadataKey, "0"),
+			wantCode: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			// arrange
+			ctx := context.Background()
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+			var handled context.Context
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				handled = ctx
+				return req, nil
+			}
+
+			// act
+			_, err := ExpectedVersionInterceptor(ctx, "request", &grpc.UnaryServerInfo{}, handler)
+
+			// assert
+			if tt.wantCode != codes.OK {
+				assert.Equal(t, tt.wantCode, status.Code(err))
+				assert.Nil(t, handled)
+				return
+			}
+			require.NoError(t, err)
+			require.NotNil(t, handled)
+			version, ok := pvz_domain.ExpectedVersionFromContext(handled)
+			assert.Equal(t, tt.wantSet, ok)
+			assert.Equal(t, tt.wantVersion, version)
+		})
+	}
+}
+
+func TestStaffInterceptor(t *testing.T) {
+	t.Parallel()
+
+	const regenerate = "/orders.v1.OrdersService/RegeneratePickupCode"
+
+	tests := []struct {
+		name       string
+		staffToken string
+		method     string
+		md         metadata.MD
+		wantCode   codes.Code
+	}{
+		{
+			name:       "other methods need no token",
+			staffToken: "secret",
+			method:     "/orders.v1.OrdersService/GetOrder",
+		},
+		{
+			name:       "staff token",
+			staffToken: "secret",
+			method:     regenerate,
+			md:         metadata.Pairs(authorizationMetadataKey, "Bearer secret"),
+		},
+		{
+			name:       "legacy package",
+			staffToken: "secret",
+			method:     "/orders.OrdersService/RegeneratePickupCode",
+			md:         metadata.Pairs(authorizationMetadataKey, "Bearer secret"),
+		},
+		{
+			name:       "no token",
+			staffToken: "secret",
+			method:     regenerate,
+			wantCode:   codes.Unauthenticated,
+		},
+		{
+			name:       "wrong token",
+			staffToken: "secret",
+			method:     regenerate,
+			md:         metadata.Pairs(authorizationMetadataKey, "Bearer guess"),
+			wantCode:   codes.PermissionDenied,
+		},
+		{
+			name:       "token without scheme",
+			staffToken: "secret",
+			method:     regenerate,
+			md:         metadata.Pairs(authorizationMetadataKey, "secret"),
+			wantCode:   codes.PermissionDenied,
+		},
+		{
+			name:     "no staff token configured",
+			method:   regenerate,
+			md:       metadata.Pairs(authorizationMetadataKey, "Bearer "),
+			wantCode: codes.PermissionDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			// arrange
+			ctx := context.Background()
+			if tt.md != nil {
+				ctx = metadata.NewIncomingContext(ctx, tt.md)
+			}
+			handled := false
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				handled = true
+				return req, nil
+			}
+
+			// act
+			_, err := StaffInterceptor(tt.staffToken)(ctx, "request", &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+
+			// assert
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			assert.Equal(t, tt.wantCode == codes.OK, handled)
+		})
+	}
+}
@@ -0,0 +1,261 @@
+package pvz_grpc
+
+import (
+	"context"
+
+	"github.com/Staspol216/gh1/internal/domain/order"
+	"github.com/Staspol216/gh1/pkg/api/orders.proto"
+	"github.com/Staspol216/gh1/pkg/api/orders/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// OrderStatus numbers that differ between orders.proto and orders.v1. orders.proto
+// used 0 for received, so unset statuses read as received there; orders.v1 keeps
+// 0 unset and moved received to 10. Every other status has the same number in both.
+const (
+	legacyStatusReceived = protoreflect.EnumNumber(orders_proto.OrderStatus_RECEVIED)
+	legacyStatusNone     = protoreflect.EnumNumber(orders_proto.OrderStatus_NONE)
+	statusUnspecified    = protoreflect.EnumNumber(orders_v1.OrderStatus_ORDER_STATUS_UNSPECIFIED)
+	statusReceived       = protoreflect.EnumNumber(orders_v1.OrderStatus_ORDER_STATUS_RECEIVED)
+)
+
+var (
+	legacyToStatus = map[protoreflect.EnumNumber]protoreflect.EnumNumber{
+		legacyStatusReceived: statusReceived,
+	}
+	// Unset statuses go out as none, which is what orders.proto sent for statuses
+	// it did not know.
+	statusToLegacy = map[protoreflect.EnumNumber]protoreflect.EnumNumber{
+		statusReceived:    legacyStatusReceived,
+		statusUnspecified: legacyStatusNone,
+	}
+)
+
+// LegacyGrpcHandler serves the deprecated orders.proto package on top of the
+// orders.v1 handler until its clients have migrated. Messages of both packages
+// share field numbers, so they are converted through the wire format and only the
+// statuses and the float worth fields dropped in orders.v1 are fixed up.
+type LegacyGrpcHandler struct {
+	v1 *GrpcHandler
+	orders_proto.UnimplementedOrdersServiceServer
+}
+
+func NewLegacy(v1 *GrpcHandler) *LegacyGrpcHandler {
+	return &LegacyGrpcHandler{
+		v1: v1,
+	}
+}
+
+func (h *LegacyGrpcHandler) GetOrders(ctx context.Context, req *orders_proto.GetOrdersRequest) (*orders_proto.GetOrdersResponse, error) {
+	return callV1(ctx, req, h.v1.GetOrders, &orders_proto.GetOrdersResponse{})
+}
+
+func (h *LegacyGrpcHandler) GetOrder(ctx context.Context, req *orders_proto.GetOrderRequest) (*orders_proto.GetOrderResponse, error) {
+	return callV1(ctx, req, h.v1.GetOrder, &orders_proto.GetOrderResponse{})
+}
+
+func (h *LegacyGrpcHandler) ListRefunds(ctx context.Context, req *orders_proto.ListRefundsRequest) (*orders_proto.ListRefundsResponse, error) {
+	return callV1(ctx, req, h.v1.ListRefunds, &orders_proto.ListRefundsResponse{})
+}
+
+func (h *LegacyGrpcHandler) GetHistory(ctx context.Context, req *orders_proto.GetHistoryRequest) (*orders_proto.GetHistoryResponse, error) {
+	return callV1(ctx, req, h.v1.GetHistory, &orders_proto.GetHistoryResponse{})
+}
+
+func (h *LegacyGrpcHandler) UpdateOrders(ctx context.Context, req *orders_proto.UpdateOrdersRequest) (*orders_proto.UpdateOrdersResponse, error) {
+	return callV1(ctx, req, h.v1.UpdateOrders, &orders_proto.UpdateOrdersResponse{})
+}
+
+func (h *LegacyGrpcHandler) CreateOrder(ctx context.Context, req *orders_proto.CreateOrderRequest) (*orders_proto.CreateOrderResponse, error) {
+	return callV1(ctx, req, h.v1.CreateOrder, &orders_proto.CreateOrderResponse{})
+}
+
+func (h *LegacyGrpcHandler) CreateOrders(ctx context.Context, req *orders_proto.CreateOrdersRequest) (*orders_proto.CreateOrdersResponse, error) {
+	return callV1(ctx, req, h.v1.CreateOrders, &orders_proto.CreateOrdersResponse{})
+}
+
+func (h *LegacyGrpcHandler) DeleteOrder(ctx context.Context, req *orders_proto.DeleteOrderRequest) (*orders_proto.DeleteOrderResponse, error) {
+	return callV1(ctx, req, h.v1.DeleteOrder, &orders_proto.DeleteOrderResponse{})
+}
+
+func (h *LegacyGrpcHandler) TransferOrder(ctx context.Context, req *orders_proto.TransferOrderRequest) (*orders_proto.TransferOrderResponse, error) {
+	return callV1(ctx, req, h.v1.TransferOrder, &orders_proto.TransferOrderResponse{})
+}
+
+func (h *LegacyGrpcHandler) AcceptTransfer(ctx context.Context, req *orders_proto.AcceptTransferRequest) (*orders_proto.AcceptTransferResponse, error) {
+	return callV1(ctx, req, h.v1.AcceptTransfer, &orders_proto.AcceptTransferResponse{})
+}
+
+func (h *LegacyGrpcHandler) RegeneratePickupCode(ctx context.Context, req *orders_proto.RegeneratePickupCodeRequest) (*orders_proto.RegeneratePickupCodeResponse, error) {
+	return callV1(ctx, req, h.v1.RegeneratePickupCode, &orders_proto.RegeneratePickupCodeResponse{})
+}
+
+func (h *LegacyGrpcHandler) ExportOrders(req *orders_proto.ExportOrdersRequest, stream grpc.ServerStreamingServer[orders_proto.ExportOrdersResponse]) error {
+	v1Req := &orders_v1.ExportOrdersRequest{}
+	if err := fromLegacy(req, v1Req); err != nil {
+		return status.Errorf(codes.InvalidArgument, "Invalid request: %s", err)
+	}
+	if err := validateRequest(v1Req); err != nil {
+		return err
+	}
+	return h.v1.ExportOrders(v1Req, &legacyStream[orders_v1.ExportOrdersResponse, orders_proto.ExportOrdersResponse]{ServerStreamingServer: stream})
+}
+
+func (h *LegacyGrpcHandler) WatchOrders(req *orders_proto.WatchOrdersRequest, stream grpc.ServerStreamingServer[orders_proto.OrderStatusEvent]) error {
+	v1Req := &orders_v1.WatchOrdersRequest{}
+	if err := fromLegacy(req, v1Req); err != nil {
+		return status.Errorf(codes.InvalidArgument, "Invalid request: %s", err)
+	}
+	if err := validateRequest(v1Req); err != nil {
+		return err
+	}
+	return h.v1.WatchOrders(v1Req, &legacyStream[orders_v1.OrderStatusEvent, orders_proto.OrderStatusEvent]{ServerStreamingServer: stream})
+}
+
+// callV1 converts a legacy request, passes it to the orders.v1 handler and converts
+// the response back. The request is validated here, as interceptors only know the
+// rules of orders.v1 messages.
+func callV1[Req, Res any, PReq interface {
+	*Req
+	proto.Message
+}, PRes interface {
+	*Res
+	proto.Message
+}, LegacyRes proto.Message](ctx context.Context, legacyReq proto.Message, call func(context.Context, PReq) (PRes, error), legacyRes LegacyRes) (LegacyRes, error) {
+	var none LegacyRes
+
+	req := PReq(new(Req))
+	if err := fromLegacy(legacyReq, req); err != nil {
+		return none, status.Errorf(codes.InvalidArgument, "Invalid request: %s", err)
+	}
+	if err := validateRequest(req); err != nil {
+		return none, err
+	}
+
+	res, err := call(ctx, req)
+	if err != nil {
+		return none, err
+	}
+
+	if err := toLegacy(res, legacyRes); err != nil {
+		return none, status.Errorf(codes.Internal, "Internal service error: %s", err)
+	}
+
+	return legacyRes, nil
+}
+
+// legacyStream sends orders.v1 messages of a stream to a legacy client.
+type legacyStream[Res, LegacyRes any] struct {
+	grpc.ServerStreamingServer[LegacyRes]
+}
+
+func (s *legacyStream[Res, LegacyRes]) Send(res *Res) error {
+	legacyRes := new(LegacyRes)
+	if err := toLegacy(any(res).(proto.Message), any(legacyRes).(proto.Message)); err != nil {
+		return status.Errorf(codes.Internal, "Internal service error: %s", err)
+	}
+	return s.ServerStreamingServer.Send(legacyRes)
+}
+
+func fromLegacy(legacy proto.Message, v1 proto.Message) error {
+	eachMessage(legacy.ProtoReflect(), func(m protoreflect.Message) {
+		if params, ok := m.Interface().(*orders_proto.CreateOrderRequest_OrderParams); ok {
+			if params.WorthMoney == nil {
+				worth := pvz_domain.MoneyFromFloat(params.GetWorth(), pvz_domain.DefaultCurrency)
+				params.WorthMoney = &orders_proto.Money{MinorUnits: worth.Amount, CurrencyCode: worth.Currency}
+			}
+			// worth is reserved in orders.v1 and would be carried as an unknown field.
+			params.Worth = 0
+		}
+	})
+
+	b, err := proto.Marshal(legacy)
+	if err != nil {
+		return err
+	}
+	if err := proto.Unmarshal(b, v1); err != nil {
+		return err
+	}
+
+	eachMessage(v1.ProtoReflect(), func(m protoreflect.Message) {
+		remapStatuses(m, legacyToStatus)
+	})
+	return nil
+}
+
+func toLegacy(v1 proto.Message, legacy proto.Message) error {
+	b, err := proto.Marshal(v1)
+	if err != nil {
+		return err
+	}
+	if err := proto.Unmarshal(b, legacy); err != nil {
+		return err
+	}
+
+	eachMessage(legacy.ProtoReflect(), func(m protoreflect.Message) {
+		remapStatuses(m, statusToLegacy)
+		if order, ok := m.Interface().(*orders_proto.Order); ok {
+			order.Worth = pvz_domain.NewMoney(order.GetWorthMoney().GetMinorUnits(), order.GetWorthMoney().GetCurrencyCode()).Float64()
+		}
+	})
+	return nil
+}
+
+// remapStatuses rewrites the OrderStatus fields of m, including unset ones, by
+// statuses. Numbers missing from statuses are kept.
+func remapStatuses(m protoreflect.Message, statuses map[protoreflect.EnumNumber]protoreflect.EnumNumber) {
+	remap := func(n protoreflect.EnumNumber) protoreflect.Value {
+		if mapped, ok := statuses[n]; ok {
+			return protoreflect.ValueOfEnum(mapped)
+		}
+		return protoreflect.ValueOfEnum(n)
+	}
+
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if fd.Enum() == nil || fd.Enum().Name() != "OrderStatus" {
+			continue
+		}
+		if fd.IsList() {
+			if !m.Has(fd) {
+				continue
+			}
+			list := m.Mutable(fd).List()
+			for j := 0; j < list.Len(); j++ {
+				list.Set(j, remap(list.Get(j).Enum()))
+			}
+			continue
+		}
+		m.Set(fd, remap(m.Get(fd).Enum()))
+	}
+}
+
+// eachMessage calls fn for m and then for every message nested in it.
+func eachMessage(m protoreflect.Message, fn func(protoreflect.Message)) {
+	fn(m)
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsMap():
+			if fd.MapValue().Message() != nil {
+				v.Map().Range(func(_ protoreflect.MapKey, value protoreflect.Value) bool {
+					eachMessage(value.Message(), fn)
+					return true
+				})
+			}
+		case fd.IsList():
+			if fd.Message() != nil {
+				for i := 0; i < v.List().Len(); i++ {
+					eachMessage(v.List().Get(i).Message(), fn)
+				}
+			}
+		case fd.Message() != nil:
+			eachMessage(v.Message(), fn)
+		}
+		return true
+	})
+}
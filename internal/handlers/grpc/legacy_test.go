@@ -0,0 +1,172 @@
+package pvz_grpc
+
+import (
+	"testing"
+
+	"github.com/Staspol216/gh1/pkg/api/orders.proto"
+	"github.com/Staspol216/gh1/pkg/api/orders/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestFromLegacy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		legacy proto.Message
+		v1     proto.Message
+		want   proto.Message
+	}{
+		{
+			name:   "RECEVIED status reads as received",
+			legacy: &orders_proto.Order{Id: 1, Status: orders_proto.OrderStatus_RECEVIED},
+			v1:     &orders_v1.Order{},
+			want:   &orders_v1.Order{Id: 1, Status: orders_v1.OrderStatus_ORDER_STATUS_RECEIVED},
+		},
+		{
+			name: "unset nested statuses read as received",
+			legacy: &orders_proto.Order{
+				Id:      1,
+				Status:  orders_proto.OrderStatus_PARTIALLY_DELIVERED,
+				History: []*orders_proto.OrderRecord{{Description: "received"}},
+				Items:   []*orders_proto.OrderItem{{Id: 2}, {Id: 3, Status: orders_proto.OrderStatus_DELIVERED}},
+			},
+			v1: &orders_v1.Order{},
+			want: &orders_v1.Order{
+				Id:      1,
+				Status:  orders_v1.OrderStatus_ORDER_STATUS_PARTIALLY_DELIVERED,
+				History: []*orders_v1.OrderRecord{{Description: "received", Status: orders_v1.OrderStatus_ORDER_STATUS_RECEIVED}},
+				Items: []*orders_v1.OrderItem{
+					{Id: 2, Status: orders_v1.OrderStatus_ORDER_STATUS_RECEIVED},
+					{Id: 3, Status: orders_v1.OrderStatus_ORDER_STATUS_DELIVERED},
+				},
+			},
+		},
+		{
+			name: "repeated statuses filter is remapped element by element",
+			legacy: &orders_proto.ExportOrdersRequest{Statuses: []orders_proto.OrderStatus{
+				orders_proto.OrderStatus_RECEVIED,
+				orders_proto.OrderStatus_DELIVERED,
+				orders_proto.OrderStatus_RECEVIED,
+			}},
+			v1: &orders_v1.ExportOrdersRequest{},
+			want: &orders_v1.ExportOrdersRequest{Statuses: []orders_v1.OrderStatus{
+				orders_v1.OrderStatus_ORDER_STATUS_RECEIVED,
+				orders_v1.OrderStatus_ORDER_STATUS_DELIVERED,
+				orders_v1.OrderStatus_ORDER_STATUS_RECEIVED,
+			}},
+		},
+		{
+			name:   "empty statuses filter stays empty",
+			legacy: &orders_proto.ExportOrdersRequest{RecipientId: 123},
+			v1:     &orders_v1.ExportOrdersRequest{},
+			want:   &orders_v1.ExportOrdersRequest{RecipientId: 123},
+		},
+		{
+			name:   "float worth becomes worth money",
+			legacy: &orders_proto.CreateOrderRequest{Order: &orders_proto.CreateOrderRequest_OrderParams{RecipientId: 123, Worth: 120.5}},
+			v1:     &orders_v1.CreateOrderRequest{},
+			want: &orders_v1.CreateOrderRequest{Order: &orders_v1.CreateOrderRequest_OrderParams{
+				RecipientId: 123,
+				WorthMoney:  &orders_v1.Money{MinorUnits: 12050, CurrencyCode: "RUB"},
+			}},
+		},
+		{
+			name: "worth money wins over float worth",
+			legacy: &orders_proto.CreateOrderRequest{Order: &orders_proto.CreateOrderRequest_OrderParams{
+				Worth:      1,
+				WorthMoney: &orders_proto.Money{MinorUnits: 500, CurrencyCode: "USD"},
+			}},
+			v1: &orders_v1.CreateOrderRequest{},
+			want: &orders_v1.CreateOrderRequest{Order: &orders_v1.CreateOrderRequest_OrderParams{
+				WorthMoney: &orders_v1.Money{MinorUnits: 500, CurrencyCode: "USD"},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			err := fromLegacy(tt.legacy, tt.v1)
+
+			// assert
+			require.NoError(t, err)
+			assert.Truef(t, proto.Equal(tt.want, tt.v1), "got %v, want %v", tt.v1, tt.want)
+		})
+	}
+}
+
+func TestToLegacy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		v1     proto.Message
+		legacy proto.Message
+		want   proto.Message
+	}{
+		{
+			name:   "received status goes out as RECEVIED",
+			v1:     &orders_v1.OrderStatusEvent{OrderId: 1, Status: orders_v1.OrderStatus_ORDER_STATUS_RECEIVED},
+			legacy: &orders_proto.OrderStatusEvent{},
+			want:   &orders_proto.OrderStatusEvent{OrderId: 1, Status: orders_proto.OrderStatus_RECEVIED},
+		},
+		{
+			name:   "unspecified status goes out as NONE",
+			v1:     &orders_v1.OrderStatusEvent{OrderId: 1},
+			legacy: &orders_proto.OrderStatusEvent{},
+			want:   &orders_proto.OrderStatusEvent{OrderId: 1, Status: orders_proto.OrderStatus_NONE},
+		},
+		{
+			name: "nested statuses are remapped",
+			v1: &orders_v1.ExportOrdersResponse{Order: &orders_v1.Order{
+				Id:      1,
+				Status:  orders_v1.OrderStatus_ORDER_STATUS_RECEIVED,
+				History: []*orders_v1.OrderRecord{{Status: orders_v1.OrderStatus_ORDER_STATUS_RECEIVED}},
+				Items:   []*orders_v1.OrderItem{{Id: 2}, {Id: 3, Status: orders_v1.OrderStatus_ORDER_STATUS_DELIVERED}},
+			}},
+			legacy: &orders_proto.ExportOrdersResponse{},
+			want: &orders_proto.ExportOrdersResponse{Order: &orders_proto.Order{
+				Id:      1,
+				Status:  orders_proto.OrderStatus_RECEVIED,
+				History: []*orders_proto.OrderRecord{{Status: orders_proto.OrderStatus_RECEVIED}},
+				Items: []*orders_proto.OrderItem{
+					{Id: 2, Status: orders_proto.OrderStatus_NONE},
+					{Id: 3, Status: orders_proto.OrderStatus_DELIVERED},
+				},
+			}},
+		},
+		{
+			name: "worth money is also sent as float worth",
+			v1: &orders_v1.GetOrderResponse{Order: &orders_v1.Order{
+				Id:         1,
+				Status:     orders_v1.OrderStatus_ORDER_STATUS_DELIVERED,
+				WorthMoney: &orders_v1.Money{MinorUnits: 12050, CurrencyCode: "RUB"},
+			}},
+			legacy: &orders_proto.GetOrderResponse{},
+			want: &orders_proto.GetOrderResponse{Order: &orders_proto.Order{
+				Id:         1,
+				Status:     orders_proto.OrderStatus_DELIVERED,
+				Worth:      120.5,
+				WorthMoney: &orders_proto.Money{MinorUnits: 12050, CurrencyCode: "RUB"},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			err := toLegacy(tt.v1, tt.legacy)
+
+			// assert
+			require.NoError(t, err)
+			assert.Truef(t, proto.Equal(tt.want, tt.legacy), "got %v, want %v", tt.legacy, tt.want)
+		})
+	}
+}
@@ -2,14 +2,14 @@ package pvz_grpc
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/Staspol216/gh1/internal/domain/order"
 	"github.com/Staspol216/gh1/internal/infra/order_outbox"
 	"github.com/Staspol216/gh1/internal/service/order"
-	"github.com/Staspol216/gh1/pkg/api/orders.proto"
+	"github.com/Staspol216/gh1/pkg/api/orders/v1"
 	"github.com/Staspol216/gh1/pkg/logger"
-	"github.com/Staspol216/gh1/pkg/monitoring"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -18,7 +18,7 @@ import (
 
 type GrpcHandler struct {
 	service *pvz_order_service.PvzService
-	orders_proto.UnimplementedOrdersServiceServer
+	orders_v1.UnimplementedOrdersServiceServer
 }
 
 func New(p *pvz_order_service.PvzService) *GrpcHandler {
@@ -27,12 +27,7 @@ func New(p *pvz_order_service.PvzService) *GrpcHandler {
 	}
 }
 
-func (s *GrpcHandler) GetOrders(ctx context.Context, req *orders_proto.GetOrdersRequest) (resp *orders_proto.GetOrdersResponse, err error) {
-	startTime := time.Now()
-	defer func() {
-		monitoring.ObserveGRPCRequest("GetOrders", err, time.Since(startTime))
-	}()
-
+func (s *GrpcHandler) GetOrders(ctx context.Context, req *orders_v1.GetOrdersRequest) (resp *orders_v1.GetOrdersResponse, err error) {
 	pagination := &pvz_domain.Pagination{
 		Offset: req.GetOffset(),
 		Limit:  req.GetLimit(),
@@ -46,24 +41,83 @@ func (s *GrpcHandler) GetOrders(ctx context.Context, req *orders_proto.GetOrders
 			zap.Int64("limit", req.GetLimit()),
 			zap.Error(err),
 		)
-		err = status.Errorf(codes.Internal, "Internal service error: %s", err)
+		err = mapServiceError(err)
+		return nil, err
+	}
+
+	return &orders_v1.GetOrdersResponse{
+		Orders: NewOrdersListResponse(orders),
+	}, nil
+}
+
+func (s *GrpcHandler) GetOrder(ctx context.Context, req *orders_v1.GetOrderRequest) (resp *orders_v1.GetOrderResponse, err error) {
+	order, err := s.service.GetOrderByID(ctx, req.GetOrderId(), req.GetRecipientId())
+
+	if err != nil {
+		app_logger.MyLogger.Error("gRPC GetOrder failed",
+			zap.Int64("order_id", req.GetOrderId()),
+			zap.Int64("recipient_id", req.GetRecipientId()),
+			zap.Error(err),
+		)
+		err = mapServiceError(err)
+		return nil, err
+	}
+
+	return &orders_v1.GetOrderResponse{
+		Order: mapDomainOrderToProtoOrder(order),
+	}, nil
+}
+
+func (s *GrpcHandler) ListRefunds(ctx context.Context, req *orders_v1.ListRefundsRequest) (resp *orders_v1.ListRefundsResponse, err error) {
+	pagination := &pvz_domain.Pagination{
+		Offset: req.GetOffset(),
+		Limit:  req.GetLimit(),
+	}
+
+	orders, err := s.service.GetAllRefunds(ctx, pagination)
+
+	if err != nil {
+		app_logger.MyLogger.Error("gRPC ListRefunds failed",
+			zap.Int64("offset", req.GetOffset()),
+			zap.Int64("limit", req.GetLimit()),
+			zap.Error(err),
+		)
+		err = mapServiceError(err)
 		return nil, err
 	}
 
-	return &orders_proto.GetOrdersResponse{
+	return &orders_v1.ListRefundsResponse{
 		Orders: NewOrdersListResponse(orders),
 	}, nil
 }
 
-func (s *GrpcHandler) CreateOrder(ctx context.Context, req *orders_proto.CreateOrderRequest) (resp *orders_proto.CreateOrderResponse, err error) {
-	startTime := time.Now()
-	defer func() {
-		monitoring.ObserveGRPCRequest("CreateOrder", err, time.Since(startTime))
-	}()
+func (s *GrpcHandler) GetHistory(ctx context.Context, req *orders_v1.GetHistoryRequest) (resp *orders_v1.GetHistoryResponse, err error) {
+	pagination := &pvz_domain.Pagination{
+		Offset: req.GetOffset(),
+		Limit:  req.GetLimit(),
+	}
+
+	orders, err := s.service.GetHistory(ctx, pagination)
+
+	if err != nil {
+		app_logger.MyLogger.Error("gRPC GetHistory failed",
+			zap.Int64("offset", req.GetOffset()),
+			zap.Int64("limit", req.GetLimit()),
+			zap.Error(err),
+		)
+		err = mapServiceError(err)
+		return nil, err
+	}
+
+	return &orders_v1.GetHistoryResponse{
+		Orders: NewOrdersListResponse(orders),
+	}, nil
+}
 
+func (s *GrpcHandler) CreateOrder(ctx context.Context, req *orders_v1.CreateOrderRequest) (resp *orders_v1.CreateOrderResponse, err error) {
 	order := mapToDomainOrderParams(req.GetOrder())
 
-	orderId, err := s.service.AcceptFromCourier(ctx, order, req.GetPackagingType(), req.GetMembranaIncluded())
+	accepted, err := s.service.AcceptFromCourier(ctx, order, req.GetPackagingType(), req.GetMembranaIncluded())
 
 	if err != nil {
 		app_logger.MyLogger.Error("gRPC CreateOrder failed",
@@ -71,22 +125,50 @@ func (s *GrpcHandler) CreateOrder(ctx context.Context, req *orders_proto.CreateO
 			zap.Bool("membrana_included", req.GetMembranaIncluded()),
 			zap.Error(err),
 		)
-		err = status.Errorf(codes.Internal, "Internal service error: %s", err)
+		err = mapServiceError(err)
 		return nil, err
 	}
 
-	return &orders_proto.CreateOrderResponse{
-		OrderId: *orderId,
+	return &orders_v1.CreateOrderResponse{
+		OrderId:    accepted.OrderID,
+		PickupCode: accepted.PickupCode,
 	}, nil
 }
 
-func (s *GrpcHandler) UpdateOrders(ctx context.Context, req *orders_proto.UpdateOrdersRequest) (resp *orders_proto.UpdateOrdersResponse, err error) {
-	startTime := time.Now()
-	defer func() {
-		monitoring.ObserveGRPCRequest("UpdateOrders", err, time.Since(startTime))
-	}()
+func (s *GrpcHandler) CreateOrders(ctx context.Context, req *orders_v1.CreateOrdersRequest) (resp *orders_v1.CreateOrdersResponse, err error) {
+	parcels := make([]*pvz_order_service.CourierParcel, 0, len(req.GetOrders()))
+	for _, order := range req.GetOrders() {
+		parcels = append(parcels, &pvz_order_service.CourierParcel{
+			Order:              mapToDomainOrderParams(order.GetOrder()),
+			PackagingType:      order.GetPackagingType(),
+			AdditionalMembrana: order.GetMembranaIncluded(),
+		})
+	}
+
+	results, err := s.service.AcceptBatchFromCourier(ctx, parcels)
+
+	if err != nil {
+		app_logger.MyLogger.Error("gRPC CreateOrders failed",
+			zap.Int("orders_count", len(parcels)),
+			zap.Error(err),
+		)
+		err = mapServiceError(err)
+		return nil, err
+	}
+
+	return &orders_v1.CreateOrdersResponse{
+		Results: mapIntakeResultsToProto(results),
+	}, nil
+}
 
-	err = s.service.ServeRecipient(ctx, req.GetOrderIds(), req.GetRecipientId(), req.GetAction())
+func (s *GrpcHandler) UpdateOrders(ctx context.Context, req *orders_v1.UpdateOrdersRequest) (resp *orders_v1.UpdateOrdersResponse, err error) {
+	err = s.service.ServeRecipient(ctx, &pvz_order_service.ServeRecipientParams{
+		OrderIDs:    req.GetOrderIds(),
+		RecipientID: req.GetRecipientId(),
+		Action:      req.GetAction(),
+		PickupCodes: req.GetPickupCodes(),
+		ItemIDs:     mapProtoToItemIDs(req.GetItemIds()),
+	})
 
 	if err != nil {
 		app_logger.MyLogger.Error("gRPC UpdateOrders failed",
@@ -95,19 +177,14 @@ func (s *GrpcHandler) UpdateOrders(ctx context.Context, req *orders_proto.Update
 			zap.String("action", req.GetAction()),
 			zap.Error(err),
 		)
-		err = status.Errorf(codes.Internal, "Internal service error: %s", err)
+		err = mapServiceError(err)
 		return nil, err
 	}
 
-	return &orders_proto.UpdateOrdersResponse{}, nil
+	return &orders_v1.UpdateOrdersResponse{}, nil
 }
 
-func (s *GrpcHandler) DeleteOrder(ctx context.Context, req *orders_proto.DeleteOrderRequest) (resp *orders_proto.DeleteOrderResponse, err error) {
-	startTime := time.Now()
-	defer func() {
-		monitoring.ObserveGRPCRequest("DeleteOrder", err, time.Since(startTime))
-	}()
-
+func (s *GrpcHandler) DeleteOrder(ctx context.Context, req *orders_v1.DeleteOrderRequest) (resp *orders_v1.DeleteOrderResponse, err error) {
 	err = s.service.ReturnToCourier(ctx, req.GetOrderId())
 
 	if err != nil {
@@ -115,11 +192,94 @@ func (s *GrpcHandler) DeleteOrder(ctx context.Context, req *orders_proto.DeleteO
 			zap.Int64("order_id", req.GetOrderId()),
 			zap.Error(err),
 		)
-		err = status.Errorf(codes.Internal, "Internal service error: %s", err)
+		err = mapServiceError(err)
+		return nil, err
+	}
+
+	return &orders_v1.DeleteOrderResponse{}, nil
+}
+
+func (s *GrpcHandler) TransferOrder(ctx context.Context, req *orders_v1.TransferOrderRequest) (resp *orders_v1.TransferOrderResponse, err error) {
+	err = s.service.TransferOrder(ctx, req.GetOrderId(), req.GetTargetPickupPointId())
+
+	if err != nil {
+		app_logger.MyLogger.Error("gRPC TransferOrder failed",
+			zap.Int64("order_id", req.GetOrderId()),
+			zap.Int64("target_pickup_point_id", req.GetTargetPickupPointId()),
+			zap.Error(err),
+		)
+		err = mapServiceError(err)
+		return nil, err
+	}
+
+	return &orders_v1.TransferOrderResponse{}, nil
+}
+
+func (s *GrpcHandler) AcceptTransfer(ctx context.Context, req *orders_v1.AcceptTransferRequest) (resp *orders_v1.AcceptTransferResponse, err error) {
+	err = s.service.AcceptTransfer(ctx, req.GetOrderId())
+
+	if err != nil {
+		app_logger.MyLogger.Error("gRPC AcceptTransfer failed",
+			zap.Int64("order_id", req.GetOrderId()),
+			zap.Error(err),
+		)
+		err = mapServiceError(err)
+		return nil, err
+	}
+
+	return &orders_v1.AcceptTransferResponse{}, nil
+}
+
+func (s *GrpcHandler) RegeneratePickupCode(ctx context.Context, req *orders_v1.RegeneratePickupCodeRequest) (resp *orders_v1.RegeneratePickupCodeResponse, err error) {
+	code, err := s.service.RegeneratePickupCode(ctx, req.GetOrderId(), req.GetRecipientId())
+
+	if err != nil {
+		app_logger.MyLogger.Error("gRPC RegeneratePickupCode failed",
+			zap.Int64("order_id", req.GetOrderId()),
+			zap.Int64("recipient_id", req.GetRecipientId()),
+			zap.Error(err),
+		)
+		err = mapServiceError(err)
 		return nil, err
 	}
 
-	return &orders_proto.DeleteOrderResponse{}, nil
+	return &orders_v1.RegeneratePickupCodeResponse{
+		PickupCode: code,
+	}, nil
+}
+
+// mapServiceError maps errors of order operations to status codes the client can act on.
+func mapServiceError(err error) error {
+	switch {
+	case errors.Is(err, pvz_domain.ErrOrderNotFound):
+		return status.Errorf(codes.NotFound, "Order not found: %s", err)
+	case errors.Is(err, pvz_domain.ErrOrderNotInCell):
+		return status.Errorf(codes.NotFound, "Order cell not found: %s", err)
+	case pvz_domain.IsPickupCodeError(err):
+		return status.Errorf(codes.PermissionDenied, "Pickup code rejected: %s", err)
+	case errors.Is(err, pvz_domain.ErrPickupCodeNotIssued):
+		return status.Errorf(codes.FailedPrecondition, "Pickup code not issued: %s", err)
+	case pvz_domain.IsOrderStateError(err):
+		return status.Errorf(codes.FailedPrecondition, "Order state does not allow this: %s", err)
+	case errors.Is(err, pvz_domain.ErrNoFreeCell):
+		return status.Errorf(codes.FailedPrecondition, "No free storage cell: %s", err)
+	case errors.Is(err, pvz_domain.ErrOrderVersionMismatch):
+		return status.Errorf(codes.FailedPrecondition, "Order version mismatch: %s", err)
+	case errors.Is(err, pvz_domain.ErrOrderVersionConflict):
+		return status.Errorf(codes.Aborted, "Order was modified concurrently: %s", err)
+	case errors.Is(err, pvz_domain.ErrIntakeBatchSize):
+		return status.Errorf(codes.InvalidArgument, "Invalid batch: %s", err)
+	case errors.Is(err, pvz_domain.ErrInvalidOrderParams):
+		return invalidArgument(err)
+	case errors.Is(err, pvz_domain.ErrPickupPointRequired), errors.Is(err, pvz_domain.ErrInvalidTransferTarget),
+		errors.Is(err, pvz_domain.ErrInvalidOrderItem), errors.Is(err, pvz_domain.ErrInvalidMoney),
+		errors.Is(err, pvz_domain.ErrCurrencyMismatch):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, pvz_domain.ErrStatusFeedInterrupted):
+		return status.Errorf(codes.Unavailable, "Status feed interrupted, resume from the last event: %s", err)
+	default:
+		return status.Errorf(codes.Internal, "Internal service error: %s", err)
+	}
 }
 
 func (s *GrpcHandler) createOutboxTask() *order_outbox.OrderOutboxTask {
@@ -134,8 +294,8 @@ func (s *GrpcHandler) createOutboxTask() *order_outbox.OrderOutboxTask {
 	return log
 }
 
-func NewOrdersListResponse(orders []*pvz_domain.Order) []*orders_proto.Order {
-	list := make([]*orders_proto.Order, 0, len(orders))
+func NewOrdersListResponse(orders []*pvz_domain.Order) []*orders_v1.Order {
+	list := make([]*orders_v1.Order, 0, len(orders))
 	for _, order := range orders {
 		list = append(list, mapDomainOrderToProtoOrder(order))
 	}
@@ -149,45 +309,82 @@ func timePtrToProto(t *time.Time) *timestamppb.Timestamp {
 	return timestamppb.New(*t)
 }
 
-func mapStatusToProto(s pvz_domain.OrderStatus) orders_proto.OrderStatus {
+func mapStatusToProto(s pvz_domain.OrderStatus) orders_v1.OrderStatus {
 	switch s {
 	case pvz_domain.OrderStatusReceived:
-		return orders_proto.OrderStatus_RECEVIED
+		return orders_v1.OrderStatus_ORDER_STATUS_RECEIVED
 	case pvz_domain.OrderStatusReturned:
-		return orders_proto.OrderStatus_RETURNED
+		return orders_v1.OrderStatus_ORDER_STATUS_RETURNED
 	case pvz_domain.OrderStatusRefunded:
-		return orders_proto.OrderStatus_REFUNDED
+		return orders_v1.OrderStatus_ORDER_STATUS_REFUNDED
 	case pvz_domain.OrderStatusDelivered:
-		return orders_proto.OrderStatus_DELIVERED
+		return orders_v1.OrderStatus_ORDER_STATUS_DELIVERED
 	case pvz_domain.OrderStatusExpired:
-		return orders_proto.OrderStatus_STRAGE_ENDED
+		return orders_v1.OrderStatus_ORDER_STATUS_STORAGE_ENDED
+	case pvz_domain.OrderStatusInTransfer:
+		return orders_v1.OrderStatus_ORDER_STATUS_IN_TRANSFER
+	case pvz_domain.OrderStatusTransferred:
+		return orders_v1.OrderStatus_ORDER_STATUS_TRANSFERRED
+	case pvz_domain.OrderStatusPartiallyDelivered:
+		return orders_v1.OrderStatus_ORDER_STATUS_PARTIALLY_DELIVERED
+	case pvz_domain.OrderStatusPartiallyRefunded:
+		return orders_v1.OrderStatus_ORDER_STATUS_PARTIALLY_REFUNDED
 	case pvz_domain.OrderStatusNone:
-		return orders_proto.OrderStatus_NONE
+		return orders_v1.OrderStatus_ORDER_STATUS_NONE
+	default:
+		return orders_v1.OrderStatus_ORDER_STATUS_UNSPECIFIED
+	}
+}
+
+func mapStatusFromProto(s orders_v1.OrderStatus) (pvz_domain.OrderStatus, bool) {
+	switch s {
+	case orders_v1.OrderStatus_ORDER_STATUS_RECEIVED:
+		return pvz_domain.OrderStatusReceived, true
+	case orders_v1.OrderStatus_ORDER_STATUS_RETURNED:
+		return pvz_domain.OrderStatusReturned, true
+	case orders_v1.OrderStatus_ORDER_STATUS_REFUNDED:
+		return pvz_domain.OrderStatusRefunded, true
+	case orders_v1.OrderStatus_ORDER_STATUS_DELIVERED:
+		return pvz_domain.OrderStatusDelivered, true
+	case orders_v1.OrderStatus_ORDER_STATUS_STORAGE_ENDED:
+		return pvz_domain.OrderStatusExpired, true
+	case orders_v1.OrderStatus_ORDER_STATUS_IN_TRANSFER:
+		return pvz_domain.OrderStatusInTransfer, true
+	case orders_v1.OrderStatus_ORDER_STATUS_TRANSFERRED:
+		return pvz_domain.OrderStatusTransferred, true
+	case orders_v1.OrderStatus_ORDER_STATUS_PARTIALLY_DELIVERED:
+		return pvz_domain.OrderStatusPartiallyDelivered, true
+	case orders_v1.OrderStatus_ORDER_STATUS_PARTIALLY_REFUNDED:
+		return pvz_domain.OrderStatusPartiallyRefunded, true
+	case orders_v1.OrderStatus_ORDER_STATUS_NONE:
+		return pvz_domain.OrderStatusNone, true
 	default:
-		return orders_proto.OrderStatus_NONE
+		return "", false
 	}
 }
 
-func mapHistoryToProto(records []pvz_domain.OrderRecord) []*orders_proto.OrderRecord {
-	result := make([]*orders_proto.OrderRecord, 0, len(records))
+func mapHistoryToProto(records []pvz_domain.OrderRecord) []*orders_v1.OrderRecord {
+	result := make([]*orders_v1.OrderRecord, 0, len(records))
 
 	for _, r := range records {
-		result = append(result, &orders_proto.OrderRecord{
-			Timestamp:   timestamppb.New(r.Timestamp),
-			Status:      mapStatusToProto(r.Status),
-			Description: r.Description,
+		result = append(result, &orders_v1.OrderRecord{
+			Timestamp:     timestamppb.New(r.Timestamp),
+			Status:        mapStatusToProto(r.Status),
+			Description:   r.Description,
+			ItemId:        r.ItemID,
+			PickupPointId: r.PickupPointID,
 		})
 	}
 
 	return result
 }
 
-func mapDomainOrderToProtoOrder(o *pvz_domain.Order) *orders_proto.Order {
+func mapDomainOrderToProtoOrder(o *pvz_domain.Order) *orders_v1.Order {
 	if o == nil {
 		return nil
 	}
 
-	return &orders_proto.Order{
+	return &orders_v1.Order{
 		Id:             o.ID,
 		RecipientId:    o.RecipientID,
 		ExpirationDate: timestamppb.New(o.ExpirationDate),
@@ -196,19 +393,90 @@ func mapDomainOrderToProtoOrder(o *pvz_domain.Order) *orders_proto.Order {
 		Status:         mapStatusToProto(o.Status),
 		History:        mapHistoryToProto(o.History),
 		Weight:         o.Weight,
-		Worth:          o.Worth,
+		WorthMoney:     mapMoneyToProto(o.Worth),
+		Items:          mapItemsToProto(o.Items),
+		Version:        o.Version,
+		PickupPointId:  o.PickupPointID,
+		ReturnedDate:   timePtrToProto(o.ReturnedDate),
+		CellId:         o.CellID,
+	}
+}
+
+func mapItemsToProto(items []*pvz_domain.OrderItem) []*orders_v1.OrderItem {
+	result := make([]*orders_v1.OrderItem, 0, len(items))
+
+	for _, item := range items {
+		result = append(result, &orders_v1.OrderItem{
+			Id:       item.ID,
+			Sku:      item.SKU,
+			Quantity: item.Quantity,
+			Price:    mapMoneyToProto(item.Price),
+			Weight:   item.Weight,
+			Status:   mapStatusToProto(item.Status),
+		})
+	}
+
+	return result
+}
+
+func mapIntakeResultsToProto(results []*pvz_order_service.ParcelIntake) []*orders_v1.CreateOrdersResponse_Result {
+	result := make([]*orders_v1.CreateOrdersResponse_Result, 0, len(results))
+
+	for _, r := range results {
+		if r.Err != nil {
+			result = append(result, &orders_v1.CreateOrdersResponse_Result{Error: r.Err.Error()})
+			continue
+		}
+		result = append(result, &orders_v1.CreateOrdersResponse_Result{
+			OrderId:    r.Accepted.OrderID,
+			PickupCode: r.Accepted.PickupCode,
+		})
 	}
+
+	return result
+}
+
+func mapProtoToItemIDs(itemIDs map[int64]*orders_v1.ItemIDs) map[int64][]int64 {
+	result := make(map[int64][]int64, len(itemIDs))
+
+	for orderID, ids := range itemIDs {
+		result[orderID] = ids.GetIds()
+	}
+
+	return result
 }
 
-func mapToDomainOrderParams(p *orders_proto.CreateOrderRequest_OrderParams) *pvz_domain.OrderParams {
+func mapMoneyToProto(m pvz_domain.Money) *orders_v1.Money {
+	return &orders_v1.Money{
+		MinorUnits:   m.Amount,
+		CurrencyCode: m.Currency,
+	}
+}
+
+func mapProtoToMoney(m *orders_v1.Money) pvz_domain.Money {
+	return pvz_domain.NewMoney(m.GetMinorUnits(), m.GetCurrencyCode())
+}
+
+func mapToDomainOrderParams(p *orders_v1.CreateOrderRequest_OrderParams) *pvz_domain.OrderParams {
 	if p == nil {
 		return nil
 	}
 
+	items := make([]pvz_domain.OrderItemParams, 0, len(p.GetItems()))
+	for _, item := range p.GetItems() {
+		items = append(items, pvz_domain.OrderItemParams{
+			SKU:      item.GetSku(),
+			Quantity: item.GetQuantity(),
+			Price:    mapProtoToMoney(item.GetPrice()),
+			Weight:   item.GetWeight(),
+		})
+	}
+
 	return &pvz_domain.OrderParams{
 		RecipientId:    p.GetRecipientId(),
 		ExpirationDate: p.GetExpirationDate().AsTime(),
 		Weight:         p.GetWeight(),
-		Worth:          p.GetWorth(),
+		Worth:          mapProtoToMoney(p.GetWorthMoney()),
+		Items:          items,
 	}
 }
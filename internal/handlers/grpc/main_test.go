@@ -0,0 +1,164 @@
+package pvz_grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/Staspol216/gh1/internal/domain/order"
+	portsMocks "github.com/Staspol216/gh1/internal/ports/mocks"
+	"github.com/Staspol216/gh1/internal/service/order"
+	"github.com/Staspol216/gh1/internal/service/order/mocks"
+	"github.com/Staspol216/gh1/pkg/api/orders/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const testPickupPointID int64 = 7
+
+type grpcHandlerTestFixture struct {
+	handler   *GrpcHandler
+	cache     *mocks.MockOrdersCache
+	txManager *portsMocks.MockTransactionManager
+}
+
+func newGrpcHandlerTestFixture(t *testing.T) *grpcHandlerTestFixture {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+
+	cache := mocks.NewMockOrdersCache(ctrl)
+	txManager := portsMocks.NewMockTransactionManager(ctrl)
+	service := pvz_order_service.NewPvzService(
+		mocks.NewMockOrderStorage(ctrl),
+		mocks.NewMockCellStorage(ctrl),
+		mocks.NewMockPickupCodeStorage(ctrl),
+		pvz_domain.PickupCodePolicy{},
+		mocks.NewMockOutbox(ctrl),
+		cache,
+		mocks.NewMockStatusFeed(ctrl),
+		txManager,
+	)
+
+	return &grpcHandlerTestFixture{
+		handler:   New(service),
+		cache:     cache,
+		txManager: txManager,
+	}
+}
+
+func TestGrpcHandler_GetOrders(t *testing.T) {
+	t.Parallel()
+
+	ctx := pvz_domain.WithPickupPoint(context.Background(), testPickupPointID)
+	req := &orders_v1.GetOrdersRequest{Offset: 0, Limit: 10}
+
+	t.Run("returns the page", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		f := newGrpcHandlerTestFixture(t)
+		f.cache.EXPECT().GetOrderPage(gomock.Any(), testPickupPointID, &pvz_domain.Pagination{Offset: 0, Limit: 10}).
+			Return([]*pvz_domain.Order{{ID: 1, PickupPointID: testPickupPointID, Status: pvz_domain.OrderStatusReceived}}, int64(1), nil)
+
+		// act
+		resp, err := f.handler.GetOrders(ctx, req)
+
+		// assert
+		require.NoError(t, err)
+		require.Len(t, resp.GetOrders(), 1)
+		assert.Equal(t, int64(1), resp.GetOrders()[0].GetId())
+	})
+
+	t.Run("rejects a call without pickup point as invalid", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		f := newGrpcHandlerTestFixture(t)
+
+		// act
+		_, err := f.handler.GetOrders(context.Background(), req)
+
+		// assert
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("reports a failed read as internal", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		f := newGrpcHandlerTestFixture(t)
+		f.cache.EXPECT().GetOrderPage(gomock.Any(), testPickupPointID, gomock.Any()).Return(nil, int64(0), errors.New("cache is down"))
+		f.txManager.EXPECT().RunReadOnly(gomock.Any(), gomock.Any()).Return(errors.New("connection refused"))
+
+		// act
+		_, err := f.handler.GetOrders(ctx, req)
+
+		// assert
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+}
+
+func TestMapServiceError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		err      error
+		wantCode codes.Code
+	}{
+		{
+			name:     "order not expired",
+			err:      fmt.Errorf("%w: order 1", pvz_domain.ErrOrderNotExpired),
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:     "order not received",
+			err:      fmt.Errorf("%w: order 1", pvz_domain.ErrOrderNotReceived),
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:     "order not in transfer",
+			err:      fmt.Errorf("%w: order 1", pvz_domain.ErrOrderNotInTransfer),
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:     "item state",
+			err:      fmt.Errorf("%w: order 1 has no received items", pvz_domain.ErrOrderItemState),
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:     "no free cell",
+			err:      fmt.Errorf("cell 42: %w", pvz_domain.ErrNoFreeCell),
+			wantCode: codes.FailedPrecondition,
+		},
+		{
+			name:     "transfer target",
+			err:      fmt.Errorf("%w: order 1 to pickup point 7", pvz_domain.ErrInvalidTransferTarget),
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "invalid item",
+			err:      fmt.Errorf("%w: item 5 is not part of order 1", pvz_domain.ErrInvalidOrderItem),
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "failure",
+			err:      errors.New("connection refused"),
+			wantCode: codes.Internal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			err := mapServiceError(tt.err)
+
+			// assert
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
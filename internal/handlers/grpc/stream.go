@@ -0,0 +1,152 @@
+package pvz_grpc
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Staspol216/gh1/internal/domain/order"
+	"github.com/Staspol216/gh1/pkg/api/orders/v1"
+	"github.com/Staspol216/gh1/pkg/logger"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Resume tokens name the stream they came from, so a token of one stream is not
+// mistaken for a position in the other.
+const (
+	exportResumeTokenKind = "export"
+	watchResumeTokenKind  = "watch"
+)
+
+var errInvalidResumeToken = errors.New("invalid resume token")
+
+func (s *GrpcHandler) ExportOrders(req *orders_v1.ExportOrdersRequest, stream grpc.ServerStreamingServer[orders_v1.ExportOrdersResponse]) (err error) {
+	afterID, err := decodeResumeToken(req.GetResumeToken(), exportResumeTokenKind)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "Invalid request: %s", err)
+	}
+
+	filter, err := mapProtoToExportFilter(req)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "Invalid request: %s", err)
+	}
+
+	err = s.service.ExportOrders(stream.Context(), filter, afterID, func(order *pvz_domain.Order) error {
+		return stream.Send(&orders_v1.ExportOrdersResponse{
+			Order:       mapDomainOrderToProtoOrder(order),
+			ResumeToken: encodeResumeToken(exportResumeTokenKind, order.ID),
+		})
+	})
+
+	if err != nil {
+		app_logger.MyLogger.Error("gRPC ExportOrders failed",
+			zap.Int64("after_id", afterID),
+			zap.Error(err),
+		)
+		return mapStreamError(err)
+	}
+
+	return nil
+}
+
+func (s *GrpcHandler) WatchOrders(req *orders_v1.WatchOrdersRequest, stream grpc.ServerStreamingServer[orders_v1.OrderStatusEvent]) (err error) {
+	afterRecordID, err := decodeResumeToken(req.GetResumeToken(), watchResumeTokenKind)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "Invalid request: %s", err)
+	}
+
+	err = s.service.WatchOrders(stream.Context(), afterRecordID, func(change *pvz_domain.OrderStatusChange) error {
+		return stream.Send(mapStatusChangeToProto(change))
+	})
+
+	if err != nil {
+		app_logger.MyLogger.Warn("gRPC WatchOrders ended",
+			zap.Int64("after_record_id", afterRecordID),
+			zap.Error(err),
+		)
+		return mapStreamError(err)
+	}
+
+	return nil
+}
+
+// mapStreamError keeps the status of failed sends, which already tells why the
+// stream broke, and maps everything else like unary calls.
+func mapStreamError(err error) error {
+	if st, ok := status.FromError(err); ok {
+		return st.Err()
+	}
+	return mapServiceError(err)
+}
+
+func encodeResumeToken(kind string, id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(kind + ":" + strconv.FormatInt(id, 10)))
+}
+
+// decodeResumeToken returns the id a token of the given kind points at, or 0 for
+// an empty token.
+func decodeResumeToken(token string, kind string) (int64, error) {
+	if token == "" {
+		return 0, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, errInvalidResumeToken
+	}
+	tokenKind, value, ok := strings.Cut(string(raw), ":")
+	if !ok || tokenKind != kind {
+		return 0, errInvalidResumeToken
+	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, errInvalidResumeToken
+	}
+
+	return id, nil
+}
+
+func mapProtoToExportFilter(req *orders_v1.ExportOrdersRequest) (*pvz_domain.OrderExportFilter, error) {
+	statuses := make([]pvz_domain.OrderStatus, 0, len(req.GetStatuses()))
+	for _, s := range req.GetStatuses() {
+		domainStatus, ok := mapStatusFromProto(s)
+		if !ok {
+			return nil, fmt.Errorf("unknown order status %v", s)
+		}
+		statuses = append(statuses, domainStatus)
+	}
+
+	return &pvz_domain.OrderExportFilter{
+		Statuses:      statuses,
+		RecipientID:   req.GetRecipientId(),
+		ExpiresAfter:  timeFromProto(req.GetExpiresAfter()),
+		ExpiresBefore: timeFromProto(req.GetExpiresBefore()),
+	}, nil
+}
+
+func mapStatusChangeToProto(change *pvz_domain.OrderStatusChange) *orders_v1.OrderStatusEvent {
+	return &orders_v1.OrderStatusEvent{
+		OrderId:       change.OrderID,
+		PickupPointId: change.PickupPointID,
+		ItemId:        change.ItemID,
+		Status:        mapStatusToProto(change.Status),
+		Description:   change.Description,
+		Timestamp:     timestamppb.New(change.Timestamp),
+		ResumeToken:   encodeResumeToken(watchResumeTokenKind, change.RecordID),
+	}
+}
+
+func timeFromProto(t *timestamppb.Timestamp) *time.Time {
+	if t == nil {
+		return nil
+	}
+	value := t.AsTime()
+	return &value
+}
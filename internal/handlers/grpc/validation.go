@@ -0,0 +1,123 @@
+package pvz_grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Staspol216/gh1/internal/domain/order"
+	"github.com/Staspol216/gh1/internal/service/order"
+	"github.com/Staspol216/gh1/internal/validation"
+	"github.com/Staspol216/gh1/pkg/api/orders/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ValidationInterceptor rejects requests that break the rules of their message
+// with InvalidArgument, listing the offending fields in errdetails.BadRequest.
+func ValidationInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	if err := validateRequest(req); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// ValidationStreamInterceptor checks the request of server-streaming calls like
+// ValidationInterceptor does for unary ones.
+func ValidationStreamInterceptor(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	return handler(srv, &validatedServerStream{ServerStream: ss})
+}
+
+type validatedServerStream struct {
+	grpc.ServerStream
+}
+
+func (s *validatedServerStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return validateRequest(m)
+}
+
+// validateRequest checks orders.v1 requests; messages of other packages pass as is.
+func validateRequest(req interface{}) error {
+	var err error
+	switch req := req.(type) {
+	case *orders_v1.GetOrdersRequest:
+		err = pvz_validation.Check(pvz_validation.Pagination(&pvz_domain.Pagination{Offset: req.GetOffset(), Limit: req.GetLimit()})...)
+	case *orders_v1.ListRefundsRequest:
+		err = pvz_validation.Check(pvz_validation.Pagination(&pvz_domain.Pagination{Offset: req.GetOffset(), Limit: req.GetLimit()})...)
+	case *orders_v1.GetHistoryRequest:
+		err = pvz_validation.Check(pvz_validation.Pagination(&pvz_domain.Pagination{Offset: req.GetOffset(), Limit: req.GetLimit()})...)
+	case *orders_v1.GetOrderRequest:
+		err = pvz_validation.Check(pvz_validation.RecipientOrderRef(req.GetOrderId(), req.GetRecipientId())...)
+	case *orders_v1.CreateOrderRequest:
+		err = pvz_validation.Check(append(
+			pvz_validation.OrderParams("order", mapToDomainOrderParams(req.GetOrder()), time.Now()),
+			pvz_validation.PackagingType("packaging_type", req.GetPackagingType()),
+		)...)
+	case *orders_v1.CreateOrdersRequest:
+		err = pvz_validation.Check(pvz_validation.IntakeBatch("orders", len(req.GetOrders()))...)
+	case *orders_v1.UpdateOrdersRequest:
+		err = pvz_validation.Check(pvz_validation.ServeRecipient(&pvz_order_service.ServeRecipientParams{
+			OrderIDs:    req.GetOrderIds(),
+			RecipientID: req.GetRecipientId(),
+			Action:      req.GetAction(),
+		})...)
+	case *orders_v1.DeleteOrderRequest:
+		err = pvz_validation.Check(pvz_validation.OrderRef(req.GetOrderId())...)
+	case *orders_v1.TransferOrderRequest:
+		err = pvz_validation.Check(pvz_validation.Transfer(req.GetOrderId(), "target_pickup_point_id", req.GetTargetPickupPointId())...)
+	case *orders_v1.AcceptTransferRequest:
+		err = pvz_validation.Check(pvz_validation.OrderRef(req.GetOrderId())...)
+	case *orders_v1.RegeneratePickupCodeRequest:
+		err = pvz_validation.Check(pvz_validation.RecipientOrderRef(req.GetOrderId(), req.GetRecipientId())...)
+	case *orders_v1.ExportOrdersRequest:
+		err = pvz_validation.Check(pvz_validation.ExportFilter(&pvz_domain.OrderExportFilter{
+			RecipientID:   req.GetRecipientId(),
+			ExpiresAfter:  timeFromProto(req.GetExpiresAfter()),
+			ExpiresBefore: timeFromProto(req.GetExpiresBefore()),
+		})...)
+	}
+	if err != nil {
+		return invalidArgument(err)
+	}
+	return nil
+}
+
+// invalidArgument turns a validation error into InvalidArgument with field
+// violations attached.
+func invalidArgument(err error) error {
+	st := status.New(codes.InvalidArgument, err.Error())
+
+	var validationErr *pvz_validation.Error
+	if !errors.As(err, &validationErr) {
+		return st.Err()
+	}
+
+	badRequest := &errdetails.BadRequest{}
+	for _, v := range validationErr.Violations {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Description,
+		})
+	}
+
+	detailed, detailsErr := st.WithDetails(badRequest)
+	if detailsErr != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
@@ -0,0 +1,53 @@
+package pvz_http
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/Staspol216/gh1/pkg/api/orders/v1"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
+)
+
+// newGateway serves orders.v1 as JSON on the routes of its google.api.http options.
+// Requests are sent to the gRPC server at grpcAddr, so they pass the same
+// interceptors, validation and error mapping as gRPC calls.
+func newGateway(ctx context.Context, grpcAddr string) (http.Handler, error) {
+	mux := runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(gatewayHeaderMatcher),
+		runtime.WithForwardResponseOption(setOrderETag),
+	)
+
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}
+	if err := orders_v1.RegisterOrdersServiceHandlerFromEndpoint(ctx, mux, grpcAddr, opts); err != nil {
+		return nil, err
+	}
+
+	return mux, nil
+}
+
+// gatewayHeaderMatcher passes the pickup point and If-Match headers on as the
+// metadata the gRPC server reads them from.
+func gatewayHeaderMatcher(key string) (string, bool) {
+	switch {
+	case strings.EqualFold(key, pickupPointIDHeader):
+		return strings.ToLower(pickupPointIDHeader), true
+	case strings.EqualFold(key, ifMatchHeader):
+		return strings.ToLower(ifMatchHeader), true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// setOrderETag tags a GetOrder response with the order version, like
+// GET /orders/{orderID} does, to be sent back in If-Match.
+func setOrderETag(_ context.Context, w http.ResponseWriter, resp proto.Message) error {
+	if getOrder, ok := resp.(*orders_v1.GetOrderResponse); ok && getOrder.GetOrder() != nil {
+		w.Header().Set(etagHeader, orderETag(getOrder.GetOrder().GetVersion()))
+	}
+	return nil
+}
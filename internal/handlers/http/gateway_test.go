@@ -0,0 +1,64 @@
+package pvz_http
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Staspol216/gh1/pkg/api/orders/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGatewayHeaderMatcher(t *testing.T) {
+	tests := []struct {
+		header  string
+		wantKey string
+		wantOK  bool
+	}{
+		{header: "X-Pickup-Point-ID", wantKey: "x-pickup-point-id", wantOK: true},
+		{header: "If-Match", wantKey: "if-match", wantOK: true},
+		{header: "if-match", wantKey: "if-match", wantOK: true},
+		{header: "Authorization", wantKey: "grpcgateway-Authorization", wantOK: true},
+		{header: "X-Unknown", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			// act
+			key, ok := gatewayHeaderMatcher(tt.header)
+
+			// assert
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				assert.Equal(t, tt.wantKey, key)
+			}
+		})
+	}
+}
+
+func TestSetOrderETag(t *testing.T) {
+	t.Run("tags GetOrder with the order version", func(t *testing.T) {
+		// arrange
+		rec := httptest.NewRecorder()
+
+		// act
+		err := setOrderETag(context.Background(), rec, &orders_v1.GetOrderResponse{Order: &orders_v1.Order{Id: testOrderID, Version: 3}})
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, `"3"`, rec.Header().Get(etagHeader))
+	})
+
+	t.Run("leaves other responses untagged", func(t *testing.T) {
+		// arrange
+		rec := httptest.NewRecorder()
+
+		// act
+		err := setOrderETag(context.Background(), rec, &orders_v1.GetOrdersResponse{Orders: []*orders_v1.Order{{Id: testOrderID, Version: 3}}})
+
+		// assert
+		require.NoError(t, err)
+		assert.Empty(t, rec.Header().Get(etagHeader))
+	})
+}
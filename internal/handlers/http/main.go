@@ -3,6 +3,8 @@ package pvz_http
 import (
 	"bytes"
 	"context"
+	"crypto/subtle"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -11,12 +13,16 @@ import (
 	"strings"
 	"time"
 
+	"github.com/Staspol216/gh1/api/openapi"
 	"github.com/Staspol216/gh1/internal/config"
 	"github.com/Staspol216/gh1/internal/domain/order"
+	"github.com/Staspol216/gh1/internal/health"
 	"github.com/Staspol216/gh1/internal/service/order"
+	"github.com/Staspol216/gh1/internal/validation"
 	"github.com/Staspol216/gh1/pkg/logger"
 	"github.com/Staspol216/gh1/pkg/monitoring"
 	"github.com/Staspol216/gh1/pkg/tracing"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/render"
@@ -24,19 +30,74 @@ import (
 )
 
 type HTTPHandler struct {
-	pvz     *pvz_order_service.PvzService
-	context context.Context
+	pvz             *pvz_order_service.PvzService
+	context         context.Context
+	readinessChecks []pvz_health.Check
+	// validateResponses makes the OpenAPI validator check responses too; tests set it.
+	validateResponses bool
 }
 
-func New(context context.Context, p *pvz_order_service.PvzService) *HTTPHandler {
-	return &HTTPHandler{pvz: p, context: context}
+func New(context context.Context, p *pvz_order_service.PvzService, readinessChecks ...pvz_health.Check) *HTTPHandler {
+	return &HTTPHandler{pvz: p, context: context, readinessChecks: readinessChecks}
 }
 
 func (h *HTTPHandler) Serve(cfg *pvz_config.Config) error {
+	r, err := h.router(cfg)
+	if err != nil {
+		return err
+	}
+
+	srv := &http.Server{
+		Addr:    cfg.HTTPAddr(),
+		Handler: r,
+	}
+
+	app_logger.MyLogger.Info("HTTP server starting", zap.String("address", cfg.HTTPAddr()))
+
+	// Start server in goroutine so we can listen for shutdown signal
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			app_logger.MyLogger.Error("HTTP server error", zap.Error(err))
+		}
+	}()
+
+	// Wait for shutdown signal
+	<-h.context.Done()
+
+	app_logger.MyLogger.Info("shutdown signal received, gracefully shutting down HTTP server")
+
+	// Graceful shutdown with 5 second timeout
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		app_logger.MyLogger.Error("HTTP server shutdown error", zap.Error(err))
+	}
+
+	return nil
+}
+
+// router builds the routes of the API. Requests to the hand-written routes are
+// checked against the OpenAPI document served at /openapi.json.
+func (h *HTTPHandler) router(cfg *pvz_config.Config) (http.Handler, error) {
+	apiDoc, err := openapi.Load(h.context)
+	if err != nil {
+		return nil, fmt.Errorf("load OpenAPI document: %w", err)
+	}
+
+	apiRouter, err := gorillamux.NewRouter(apiDoc)
+	if err != nil {
+		return nil, fmt.Errorf("route OpenAPI document: %w", err)
+	}
+
+	apiDocJSON, err := json.Marshal(apiDoc)
+	if err != nil {
+		return nil, fmt.Errorf("encode OpenAPI document: %w", err)
+	}
+
 	r := chi.NewRouter()
 	r.Use(middleware.RequestID)
 	r.Use(middleware.Recoverer)
-	r.Use(middleware.URLFormat)
 	r.Use(render.SetContentType(render.ContentTypeJSON))
 	r.Use(tracingMiddleware)
 	r.Use(metricsMiddleware)
@@ -48,58 +109,78 @@ func (h *HTTPHandler) Serve(cfg *pvz_config.Config) error {
 		}
 	})
 
+	r.Get("/ready", h.Ready)
+
+	r.Get("/openapi.json", serveOpenAPI(apiDocJSON))
+
+	gateway, err := newGateway(h.context, cfg.GRPCLoopbackAddr())
+	if err != nil {
+		return nil, err
+	}
+
+	r.Mount("/v1", gateway)
+
+	if cfg.HTTPLegacyRoutesEnabled {
+		h.legacyRoutes(r.With(openAPIValidator(apiRouter, h.validateResponses)), cfg.DefaultPickupPointID, cfg.StaffToken)
+	}
+
+	return r, nil
+}
+
+// legacyRoutes are the hand-written routes served before orders.v1 had an HTTP
+// binding; they are kept for clients that have not moved to /v1 yet.
+func (h *HTTPHandler) legacyRoutes(r chi.Router, defaultPickupPointID int64, staffToken string) {
 	r.Route("/orders", func(r chi.Router) {
+		r.Use(pickupPointCtx(defaultPickupPointID))
+
 		r.With(paginate).Get("/", h.ListOrders)
 
 		r.With(requestLogger).Post("/", h.CreateOrder)
 
-		r.With(requestLogger).Patch("/", h.UpdateOrders)
+		r.With(requestLogger).Post("/batch", h.CreateOrders)
+
+		r.With(requestLogger, ifMatch).Patch("/", h.UpdateOrders)
 
 		r.Route("/{orderID}", func(r chi.Router) {
 			r.Use(OrderCtx)
 
 			r.With(requestLogger).Get("/", h.GetOrder)
 
-			r.With(requestLogger).Delete("/", h.DeleteOrder)
+			r.With(requestLogger, ifMatch).Delete("/", h.DeleteOrder)
+
+			r.With(requestLogger, staffOnly(staffToken), ifMatch).Post("/pickup-code", h.RegeneratePickupCode)
 		})
 
 		r.Route("/refunds", func(r chi.Router) {
-			r.Get("/", h.ListRefundedOrders)
+			r.With(paginate).Get("/", h.ListRefundedOrders)
 		})
-	})
 
-	r.Route("/orders-history", func(r chi.Router) {
-		r.Get("/", h.ListOrders)
-	})
+		r.Route("/transfers", func(r chi.Router) {
+			r.With(requestLogger, ifMatch).Post("/", h.TransferOrder)
 
-	srv := &http.Server{
-		Addr:    cfg.HTTPAddr(),
-		Handler: r,
-	}
+			r.With(requestLogger, ifMatch).Post("/accept", h.AcceptTransfer)
+		})
+	})
 
-	app_logger.MyLogger.Info("HTTP server starting", zap.String("address", cfg.HTTPAddr()))
+	r.Route("/cells", func(r chi.Router) {
+		r.Use(pickupPointCtx(defaultPickupPointID))
 
-	// Start server in goroutine so we can listen for shutdown signal
-	go func() {
-		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			app_logger.MyLogger.Error("HTTP server error", zap.Error(err))
-		}
-	}()
+		r.With(requestLogger).Post("/", h.CreateCell)
 
-	// Wait for shutdown signal
-	<-h.context.Done()
+		r.Route("/orders/{orderID}", func(r chi.Router) {
+			r.Use(orderIDCtx)
 
-	app_logger.MyLogger.Info("shutdown signal received, gracefully shutting down HTTP server")
+			r.Get("/", h.GetOrderCell)
 
-	// Graceful shutdown with 5 second timeout
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+			r.With(requestLogger, ifMatch).Put("/", h.ReshelveOrder)
+		})
+	})
 
-	if err := srv.Shutdown(shutdownCtx); err != nil {
-		app_logger.MyLogger.Error("HTTP server shutdown error", zap.Error(err))
-	}
+	r.Route("/orders-history", func(r chi.Router) {
+		r.Use(pickupPointCtx(defaultPickupPointID))
 
-	return nil
+		r.With(paginate).Get("/", h.ListOrdersHistory)
+	})
 }
 
 type ctxKey string
@@ -112,6 +193,43 @@ const (
 
 const recipientIDQueryKey = "recipientID"
 
+const pickupPointIDHeader = "X-Pickup-Point-ID"
+
+const (
+	etagHeader    = "ETag"
+	ifMatchHeader = "If-Match"
+)
+
+const authorizationHeader = "Authorization"
+
+func pickupPointCtx(defaultPickupPointID int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			pickupPointID := defaultPickupPointID
+
+			if header := strings.TrimSpace(r.Header.Get(pickupPointIDHeader)); header != "" {
+				parsed, err := strconv.ParseInt(header, 10, 64)
+				if err != nil || parsed <= 0 {
+					if rErr := render.Render(w, r, ErrInvalidRequest(fmt.Errorf("invalid %s header", pickupPointIDHeader))); rErr != nil {
+						return
+					}
+					return
+				}
+				pickupPointID = parsed
+			}
+
+			if pickupPointID <= 0 {
+				if rErr := render.Render(w, r, ErrInvalidRequest(pvz_domain.ErrPickupPointRequired)); rErr != nil {
+					return
+				}
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(pvz_domain.WithPickupPoint(r.Context(), pickupPointID)))
+		})
+	}
+}
+
 func OrderCtx(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
@@ -157,6 +275,25 @@ func OrderCtx(next http.Handler) http.Handler {
 	})
 }
 
+// orderIDCtx parses the order id path parameter for staff routes that are not scoped to a recipient.
+func orderIDCtx(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "orderID")
+
+		parsedOrderId, parseIntErr := strconv.ParseInt(strings.TrimSpace(id), 10, 64)
+		if parseIntErr != nil {
+			err := render.Render(w, r, ErrInvalidRequest(fmt.Errorf("invalid order id: %w", parseIntErr)))
+			if err != nil {
+				return
+			}
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), ctxKeyOrderID, parsedOrderId)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
 type responseStatusRecorder struct {
 	http.ResponseWriter
 	statusCode int
@@ -175,6 +312,14 @@ func (r *responseStatusRecorder) Write(body []byte) (int, error) {
 	return r.ResponseWriter.Write(body)
 }
 
+// Flush lets streamed responses, like those of the gateway, through as they are
+// written.
+func (r *responseStatusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
 func metricsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		startTime := time.Now()
@@ -245,12 +390,73 @@ func requestLogger(next http.Handler) http.Handler {
 	})
 }
 
+// orderETag renders an order version as a strong entity tag.
+func orderETag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// ifMatch makes the mutation conditional on the order version in the If-Match header.
+// Only a single strong ETag as returned by GET /orders/{orderID} or "*" is accepted.
+// staffOnly lets only requests carrying "Authorization: Bearer <staffToken>" through.
+// With an empty staffToken the route is refused to everyone.
+func staffOnly(staffToken string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := r.Header.Get(authorizationHeader)
+			if header == "" {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				if rErr := render.Render(w, r, ErrUnauthorized(errors.New("staff token is required"))); rErr != nil {
+					return
+				}
+				return
+			}
+
+			token, ok := strings.CutPrefix(header, "Bearer ")
+			if !ok || staffToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(staffToken)) != 1 {
+				if rErr := render.Render(w, r, ErrForbidden(errors.New("staff token is invalid"))); rErr != nil {
+					return
+				}
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func ifMatch(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := strings.TrimSpace(r.Header.Get(ifMatchHeader))
+		if header == "" || header == "*" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		unquoted, err := strconv.Unquote(header)
+		if err != nil {
+			if rErr := render.Render(w, r, ErrInvalidRequest(fmt.Errorf("invalid %s header: %q", ifMatchHeader, header))); rErr != nil {
+				return
+			}
+			return
+		}
+
+		version, err := strconv.ParseInt(unquoted, 10, 64)
+		if err != nil {
+			if rErr := render.Render(w, r, ErrInvalidRequest(fmt.Errorf("invalid %s header: %q", ifMatchHeader, header))); rErr != nil {
+				return
+			}
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(pvz_domain.WithExpectedVersion(r.Context(), version)))
+	})
+}
+
 func paginate(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		const (
 			defaultOffset int64 = 0
 			defaultLimit  int64 = 10
-			maxLimit      int64 = 100
 		)
 
 		q := r.URL.Query()
@@ -265,7 +471,7 @@ func paginate(next http.Handler) http.Handler {
 
 		if ls := strings.TrimSpace(q.Get("limit")); ls != "" {
 			if v, err := strconv.ParseInt(ls, 10, 64); err == nil && v > 0 {
-				limit = min(v, maxLimit)
+				limit = min(v, pvz_validation.MaxPageLimit)
 			}
 		}
 
@@ -382,13 +588,14 @@ func (h *HTTPHandler) GetOrder(w http.ResponseWriter, r *http.Request) {
 	fmt.Println(recipientID)
 	order, err := h.pvz.GetOrderByID(r.Context(), orderID, recipientID)
 	if err != nil {
-		eErr := render.Render(w, r, ErrInternal(err))
+		eErr := render.Render(w, r, ErrFromService(err))
 		if eErr != nil {
 			return
 		}
 		return
 	}
 
+	w.Header().Set(etagHeader, orderETag(order.Version))
 	renderErr := render.Render(w, r, NewOrderResponse(order))
 	if renderErr != nil {
 		eErr := render.Render(w, r, ErrRender(renderErr))
@@ -408,26 +615,58 @@ func (h *HTTPHandler) CreateOrder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if res := time.Now().Compare(data.Order.ExpirationDate); res == 1 {
-		err := render.Render(w, r, ErrInvalidRequest(errors.New("expiration date can't be in the past")))
-		if err != nil {
+	accepted, err := h.pvz.AcceptFromCourier(r.Context(), data.Order, data.PackagingType, data.MembranaIncluded)
+
+	if err != nil {
+		if eErr := render.Render(w, r, ErrFromService(err)); eErr != nil {
+			return
+		}
+		return
+	}
+
+	renderError := render.Render(w, r, NewOrderIDResponse(accepted.OrderID, accepted.PickupCode))
+	if renderError != nil {
+		if eErr := render.Render(w, r, ErrRender(renderError)); eErr != nil {
+			return
+		}
+	}
+}
+
+// CreateOrders accepts a whole courier delivery. Parcels are reported one by one, so
+// the response is 200 even if some of them were rejected.
+func (h *HTTPHandler) CreateOrders(w http.ResponseWriter, r *http.Request) {
+	data := &OrdersBatchCreateRequest{}
+	if err := render.Bind(r, data); err != nil {
+		if eErr := render.Render(w, r, ErrInvalidRequest(err)); eErr != nil {
 			return
 		}
 		return
 	}
 
-	orderId, err := h.pvz.AcceptFromCourier(r.Context(), data.Order, data.PackagingType, data.MembranaIncluded)
+	parcels := make([]*pvz_order_service.CourierParcel, 0, len(data.Orders))
+	for _, order := range data.Orders {
+		if order == nil {
+			parcels = append(parcels, nil)
+			continue
+		}
+		parcels = append(parcels, &pvz_order_service.CourierParcel{
+			Order:              order.Order,
+			PackagingType:      order.PackagingType,
+			AdditionalMembrana: order.MembranaIncluded,
+		})
+	}
 
+	results, err := h.pvz.AcceptBatchFromCourier(r.Context(), parcels)
 	if err != nil {
-		if eErr := render.Render(w, r, ErrInternal(err)); eErr != nil {
+		if rErr := render.Render(w, r, ErrFromService(err)); rErr != nil {
 			return
 		}
 		return
 	}
 
-	renderError := render.Render(w, r, NewOrderIDResponse(*orderId))
-	if renderError != nil {
-		if eErr := render.Render(w, r, ErrRender(renderError)); eErr != nil {
+	renderErr := render.Render(w, r, NewOrdersBatchCreateResponse(results))
+	if renderErr != nil {
+		if rErr := render.Render(w, r, ErrRender(renderErr)); rErr != nil {
 			return
 		}
 	}
@@ -442,9 +681,15 @@ func (h *HTTPHandler) UpdateOrders(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := h.pvz.ServeRecipient(r.Context(), data.OrderIDs, data.RecipientID, data.Action)
+	err := h.pvz.ServeRecipient(r.Context(), &pvz_order_service.ServeRecipientParams{
+		OrderIDs:    data.OrderIDs,
+		RecipientID: data.RecipientID,
+		Action:      data.Action,
+		PickupCodes: data.PickupCodes,
+		ItemIDs:     data.ItemIDs,
+	})
 	if err != nil {
-		if rErr := render.Render(w, r, ErrInternal(err)); rErr != nil {
+		if rErr := render.Render(w, r, ErrFromService(err)); rErr != nil {
 			return
 		}
 		return
@@ -459,6 +704,41 @@ func (h *HTTPHandler) UpdateOrders(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+func (h *HTTPHandler) RegeneratePickupCode(w http.ResponseWriter, r *http.Request) {
+	orderID, ok := r.Context().Value(ctxKeyOrderID).(int64)
+	if !ok {
+		err := render.Render(w, r, ErrInternal(errors.New("cannot get order id from request context")))
+		if err != nil {
+			return
+		}
+		return
+	}
+
+	recipientID, ok := r.Context().Value(recipientIDQueryKey).(int64)
+	if !ok {
+		err := render.Render(w, r, ErrInternal(errors.New("cannot get recipient id from request context")))
+		if err != nil {
+			return
+		}
+		return
+	}
+
+	code, err := h.pvz.RegeneratePickupCode(r.Context(), orderID, recipientID)
+	if err != nil {
+		if rErr := render.Render(w, r, ErrFromService(err)); rErr != nil {
+			return
+		}
+		return
+	}
+
+	renderErr := render.Render(w, r, NewPickupCodeResponse(orderID, code))
+	if renderErr != nil {
+		if rErr := render.Render(w, r, ErrRender(renderErr)); rErr != nil {
+			return
+		}
+	}
+}
+
 func (h *HTTPHandler) DeleteOrder(w http.ResponseWriter, r *http.Request) {
 	orderID, ok := r.Context().Value(ctxKeyOrderID).(int64)
 	if !ok {
@@ -471,7 +751,7 @@ func (h *HTTPHandler) DeleteOrder(w http.ResponseWriter, r *http.Request) {
 
 	returnErr := h.pvz.ReturnToCourier(r.Context(), orderID)
 	if returnErr != nil {
-		err := render.Render(w, r, ErrInternal(returnErr))
+		err := render.Render(w, r, ErrFromService(returnErr))
 		if err != nil {
 			return
 		}
@@ -486,3 +766,143 @@ func (h *HTTPHandler) DeleteOrder(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 }
+
+func (h *HTTPHandler) TransferOrder(w http.ResponseWriter, r *http.Request) {
+	data := &OrderTransferRequest{}
+	if err := render.Bind(r, data); err != nil {
+		if eErr := render.Render(w, r, ErrInvalidRequest(err)); eErr != nil {
+			return
+		}
+		return
+	}
+
+	err := h.pvz.TransferOrder(r.Context(), data.OrderID, data.PickupPointID)
+	if err != nil {
+		if rErr := render.Render(w, r, ErrFromService(err)); rErr != nil {
+			return
+		}
+		return
+	}
+
+	renderErr := render.Render(w, r, NewOrderTransferResponse())
+	if renderErr != nil {
+		rErr := render.Render(w, r, ErrRender(renderErr))
+		if rErr != nil {
+			return
+		}
+	}
+}
+
+func (h *HTTPHandler) AcceptTransfer(w http.ResponseWriter, r *http.Request) {
+	data := &OrderTransferAcceptRequest{}
+	if err := render.Bind(r, data); err != nil {
+		if eErr := render.Render(w, r, ErrInvalidRequest(err)); eErr != nil {
+			return
+		}
+		return
+	}
+
+	err := h.pvz.AcceptTransfer(r.Context(), data.OrderID)
+	if err != nil {
+		if rErr := render.Render(w, r, ErrFromService(err)); rErr != nil {
+			return
+		}
+		return
+	}
+
+	renderErr := render.Render(w, r, NewOrderTransferResponse())
+	if renderErr != nil {
+		rErr := render.Render(w, r, ErrRender(renderErr))
+		if rErr != nil {
+			return
+		}
+	}
+}
+
+func (h *HTTPHandler) CreateCell(w http.ResponseWriter, r *http.Request) {
+	data := &CellRequest{}
+	if err := render.Bind(r, data); err != nil {
+		if eErr := render.Render(w, r, ErrInvalidRequest(err)); eErr != nil {
+			return
+		}
+		return
+	}
+
+	cell, err := h.pvz.AddCell(r.Context(), data.ToCell())
+	if err != nil {
+		if rErr := render.Render(w, r, ErrFromService(err)); rErr != nil {
+			return
+		}
+		return
+	}
+
+	renderErr := render.Render(w, r, NewCellCreatedResponse(cell))
+	if renderErr != nil {
+		rErr := render.Render(w, r, ErrRender(renderErr))
+		if rErr != nil {
+			return
+		}
+	}
+}
+
+func (h *HTTPHandler) GetOrderCell(w http.ResponseWriter, r *http.Request) {
+	orderID, ok := r.Context().Value(ctxKeyOrderID).(int64)
+	if !ok {
+		err := render.Render(w, r, ErrInternal(errors.New("cannot get order id from request context")))
+		if err != nil {
+			return
+		}
+		return
+	}
+
+	cell, err := h.pvz.GetOrderCell(r.Context(), orderID)
+	if err != nil {
+		if rErr := render.Render(w, r, ErrFromService(err)); rErr != nil {
+			return
+		}
+		return
+	}
+
+	renderErr := render.Render(w, r, NewCellResponse(cell))
+	if renderErr != nil {
+		rErr := render.Render(w, r, ErrRender(renderErr))
+		if rErr != nil {
+			return
+		}
+	}
+}
+
+func (h *HTTPHandler) ReshelveOrder(w http.ResponseWriter, r *http.Request) {
+	orderID, ok := r.Context().Value(ctxKeyOrderID).(int64)
+	if !ok {
+		err := render.Render(w, r, ErrInternal(errors.New("cannot get order id from request context")))
+		if err != nil {
+			return
+		}
+		return
+	}
+
+	data := &OrderReshelveRequest{}
+	if err := render.Bind(r, data); err != nil {
+		if eErr := render.Render(w, r, ErrInvalidRequest(err)); eErr != nil {
+			return
+		}
+		return
+	}
+
+	cell, err := h.pvz.ReshelveOrder(r.Context(), orderID, data.CellID)
+	if err != nil {
+		if rErr := render.Render(w, r, ErrFromService(err)); rErr != nil {
+			return
+		}
+		return
+	}
+
+	renderErr := render.Render(w, r, NewCellResponse(cell))
+	if renderErr != nil {
+		rErr := render.Render(w, r, ErrRender(renderErr))
+		if rErr != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package pvz_http
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/Staspol216/gh1/internal/validation"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/go-chi/render"
+)
+
+// serveOpenAPI serves the OpenAPI document of the API.
+func serveOpenAPI(document []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(document); err != nil {
+			return
+		}
+	}
+}
+
+// openAPIValidator rejects requests that do not match the OpenAPI document with 400,
+// listing the offending parameters and body fields as violations. Routes the
+// document does not describe are passed on as is.
+//
+// With validateResponses, which tests turn on, responses are checked as well and
+// one that does not match the document, including an undocumented status, is
+// replaced with 500.
+func openAPIValidator(apiRouter routers.Router, validateResponses bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route, pathParams, err := apiRouter.FindRoute(r)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			input := &openapi3filter.RequestValidationInput{
+				Request:    r,
+				PathParams: pathParams,
+				Route:      route,
+				Options: &openapi3filter.Options{
+					MultiError:         true,
+					AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+				},
+			}
+			if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+				if rErr := render.Render(w, r, ErrInvalidRequest(openAPIViolations(err))); rErr != nil {
+					return
+				}
+				return
+			}
+
+			if !validateResponses {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			recorder := &bufferedResponse{header: make(http.Header), statusCode: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+
+			err = openapi3filter.ValidateResponse(r.Context(), &openapi3filter.ResponseValidationInput{
+				RequestValidationInput: input,
+				Status:                 recorder.statusCode,
+				Header:                 recorder.header,
+				Body:                   io.NopCloser(bytes.NewReader(recorder.body.Bytes())),
+				Options: &openapi3filter.Options{
+					MultiError:            true,
+					IncludeResponseStatus: true,
+				},
+			})
+			if err != nil {
+				if rErr := render.Render(w, r, ErrInternal(fmt.Errorf("response does not match the OpenAPI document: %w", err))); rErr != nil {
+					return
+				}
+				return
+			}
+
+			for key, values := range recorder.header {
+				w.Header()[key] = values
+			}
+			w.WriteHeader(recorder.statusCode)
+			if _, err := w.Write(recorder.body.Bytes()); err != nil {
+				return
+			}
+		})
+	}
+}
+
+// openAPIViolations turns the errors of request validation into a validation error
+// naming fields like the rest of the API does, e.g. order.items[0].sku.
+func openAPIViolations(err error) error {
+	var violations []pvz_validation.Violation
+	for _, err := range flattenOpenAPIErrors(err) {
+		var requestErr *openapi3filter.RequestError
+		if !errors.As(err, &requestErr) {
+			violations = append(violations, pvz_validation.Violation{Field: "request", Description: err.Error()})
+			continue
+		}
+
+		field := "body"
+		if requestErr.Parameter != nil {
+			field = requestErr.Parameter.Name
+		}
+
+		causes := flattenOpenAPIErrors(requestErr.Err)
+		if len(causes) == 0 {
+			violations = append(violations, pvz_validation.Violation{Field: field, Description: requestErr.Error()})
+			continue
+		}
+		for _, cause := range causes {
+			var schemaErr *openapi3.SchemaError
+			if !errors.As(cause, &schemaErr) {
+				violations = append(violations, pvz_validation.Violation{Field: field, Description: cause.Error()})
+				continue
+			}
+			violations = append(violations, pvz_validation.Violation{
+				Field:       schemaErrorField(field, requestErr.Parameter == nil, schemaErr.JSONPointer()),
+				Description: schemaErr.Reason,
+			})
+		}
+	}
+	return &pvz_validation.Error{Violations: violations}
+}
+
+func flattenOpenAPIErrors(err error) []error {
+	if err == nil {
+		return nil
+	}
+	// Not errors.As: a request error wrapping a multi error must be kept whole.
+	multiErr, ok := err.(openapi3.MultiError)
+	if !ok {
+		return []error{err}
+	}
+	var flat []error
+	for _, err := range multiErr {
+		flat = append(flat, flattenOpenAPIErrors(err)...)
+	}
+	return flat
+}
+
+// schemaErrorField names the field a schema error points at. Body fields are named
+// from the top of the body; the body itself is named body.
+func schemaErrorField(field string, inBody bool, pointer []string) string {
+	if !inBody || len(pointer) == 0 {
+		return field
+	}
+
+	name := ""
+	for _, segment := range pointer {
+		if i, err := strconv.Atoi(segment); err == nil && name != "" {
+			name = pvz_validation.Index(name, i)
+			continue
+		}
+		name = pvz_validation.Nested(name, segment)
+	}
+	return name
+}
+
+// bufferedResponse holds a response until it is checked against the document.
+type bufferedResponse struct {
+	header     http.Header
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header {
+	return b.header
+}
+
+func (b *bufferedResponse) WriteHeader(statusCode int) {
+	b.statusCode = statusCode
+}
+
+func (b *bufferedResponse) Write(body []byte) (int, error) {
+	return b.body.Write(body)
+}
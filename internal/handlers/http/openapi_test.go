@@ -0,0 +1,497 @@
+package pvz_http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Staspol216/gh1/api/openapi"
+	"github.com/Staspol216/gh1/internal/config"
+	"github.com/Staspol216/gh1/internal/domain/order"
+	portsMocks "github.com/Staspol216/gh1/internal/ports/mocks"
+	"github.com/Staspol216/gh1/internal/service/order"
+	"github.com/Staspol216/gh1/internal/service/order/mocks"
+	"github.com/Staspol216/gh1/internal/validation"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	testOrderID       int64 = 1
+	testRecipientID   int64 = 123
+	testPickupPointID int64 = 7
+	testStaffToken          = "staff-secret"
+)
+
+var testConfig = &pvz_config.Config{
+	BackendGRPCPort:         50051,
+	DefaultPickupPointID:    testPickupPointID,
+	HTTPLegacyRoutesEnabled: true,
+	StaffToken:              testStaffToken,
+}
+
+type httpHandlerTestFixture struct {
+	router    http.Handler
+	cache     *mocks.MockOrdersCache
+	storage   *mocks.MockOrderStorage
+	cells     *mocks.MockCellStorage
+	txManager *portsMocks.MockTransactionManager
+}
+
+// newHTTPHandlerTestFixture serves the API with responses checked against the
+// OpenAPI document, so a handler that breaks it answers 500.
+func newHTTPHandlerTestFixture(t *testing.T) *httpHandlerTestFixture {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+
+	cache := mocks.NewMockOrdersCache(ctrl)
+	storage := mocks.NewMockOrderStorage(ctrl)
+	cells := mocks.NewMockCellStorage(ctrl)
+	txManager := portsMocks.NewMockTransactionManager(ctrl)
+	service := pvz_order_service.NewPvzService(
+		storage,
+		cells,
+		mocks.NewMockPickupCodeStorage(ctrl),
+		pvz_domain.PickupCodePolicy{},
+		mocks.NewMockOutbox(ctrl),
+		cache,
+		mocks.NewMockStatusFeed(ctrl),
+		txManager,
+	)
+
+	h := New(context.Background(), service)
+	h.validateResponses = true
+
+	router, err := h.router(testConfig)
+	require.NoError(t, err)
+
+	return &httpHandlerTestFixture{
+		router:    router,
+		cache:     cache,
+		storage:   storage,
+		cells:     cells,
+		txManager: txManager,
+	}
+}
+
+func (f *httpHandlerTestFixture) serve(method string, target string, body string) *httptest.ResponseRecorder {
+	return f.serveWithHeader(method, target, body, http.Header{})
+}
+
+func (f *httpHandlerTestFixture) serveWithHeader(method string, target string, body string, header http.Header) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header = header
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	rec := httptest.NewRecorder()
+	f.router.ServeHTTP(rec, req)
+	return rec
+}
+
+func testOrder() *pvz_domain.Order {
+	delivered := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	itemID := int64(5)
+	return &pvz_domain.Order{
+		ID:             testOrderID,
+		PickupPointID:  testPickupPointID,
+		RecipientID:    testRecipientID,
+		ExpirationDate: time.Date(2026, 10, 25, 12, 0, 0, 0, time.UTC),
+		DeliveredDate:  &delivered,
+		Status:         pvz_domain.OrderStatusPartiallyDelivered,
+		History: []pvz_domain.OrderRecord{
+			{PickupPointID: testPickupPointID, Timestamp: delivered, Status: pvz_domain.OrderStatusReceived, Description: "received"},
+			{PickupPointID: testPickupPointID, ItemID: &itemID, Timestamp: delivered, Status: pvz_domain.OrderStatusDelivered, Description: "delivered"},
+		},
+		Weight: 1.5,
+		Worth:  pvz_domain.NewMoney(12050, pvz_domain.DefaultCurrency),
+		Items: []*pvz_domain.OrderItem{
+			{ID: itemID, SKU: "A-1", Quantity: 2, Price: pvz_domain.NewMoney(6025, pvz_domain.DefaultCurrency), Weight: 0.75, Status: pvz_domain.OrderStatusDelivered},
+		},
+		Version: 3,
+	}
+}
+
+func TestOpenAPI_DescribesEveryRoute(t *testing.T) {
+	// arrange
+	f := newHTTPHandlerTestFixture(t)
+	doc, err := openapi.Load(context.Background())
+	require.NoError(t, err)
+
+	// act
+	var undocumented []string
+	walkErr := chi.Walk(f.router.(chi.Routes), func(method string, route string, handler http.Handler, middlewares ...func(http.Handler) http.Handler) error {
+		// The gateway routes are described by the document generated from orders.v1.
+		if strings.HasPrefix(route, "/v1/") {
+			return nil
+		}
+		path := route
+		if path != "/" {
+			path = strings.TrimSuffix(route, "/")
+		}
+		item := doc.Paths.Value(path)
+		if item == nil || item.GetOperation(method) == nil {
+			undocumented = append(undocumented, method+" "+route)
+		}
+		return nil
+	})
+
+	// assert
+	require.NoError(t, walkErr)
+	assert.Empty(t, undocumented)
+	assert.NotNil(t, doc.Paths.Value("/v1/orders/{orderId}"), "orders.v1 routes are merged in")
+}
+
+func TestOpenAPI_ServesDocument(t *testing.T) {
+	// arrange
+	f := newHTTPHandlerTestFixture(t)
+
+	// act
+	rec := f.serve(http.MethodGet, "/openapi.json", "")
+
+	// assert
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var doc struct {
+		OpenAPI string                     `json:"openapi"`
+		Paths   map[string]json.RawMessage `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Equal(t, "3.0.3", doc.OpenAPI)
+	assert.Contains(t, doc.Paths, "/orders/{orderID}")
+	assert.Contains(t, doc.Paths, "/v1/orders")
+}
+
+func TestHTTPHandler_GetOrder(t *testing.T) {
+	t.Run("renders the order as documented", func(t *testing.T) {
+		// arrange
+		f := newHTTPHandlerTestFixture(t)
+		f.cache.EXPECT().GetOrder(gomock.Any(), testPickupPointID, testOrderID, testRecipientID).Return(testOrder(), nil)
+
+		// act
+		rec := f.serve(http.MethodGet, "/orders/1?recipientID=123", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Equal(t, `"3"`, rec.Header().Get(etagHeader))
+	})
+
+	t.Run("renders a missing order as documented", func(t *testing.T) {
+		// arrange
+		f := newHTTPHandlerTestFixture(t)
+		f.cache.EXPECT().GetOrder(gomock.Any(), testPickupPointID, testOrderID, testRecipientID).Return(nil, pvz_domain.ErrOrderNotFound)
+
+		// act
+		rec := f.serve(http.MethodGet, "/orders/1?recipientID=123", "")
+
+		// assert
+		assert.Equal(t, http.StatusNotFound, rec.Code, rec.Body.String())
+	})
+
+	t.Run("rejects a request without recipient", func(t *testing.T) {
+		// arrange
+		f := newHTTPHandlerTestFixture(t)
+
+		// act
+		rec := f.serve(http.MethodGet, "/orders/1", "")
+
+		// assert
+		require.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+		assert.Equal(t, []pvz_validation.Violation{{Field: "recipientID", Description: "value is required but missing"}}, decodeViolations(t, rec))
+	})
+}
+
+func TestHTTPHandler_ListOrders(t *testing.T) {
+	t.Run("renders the page as documented", func(t *testing.T) {
+		// arrange
+		f := newHTTPHandlerTestFixture(t)
+		pagination := &pvz_domain.Pagination{Offset: 0, Limit: 10}
+		f.cache.EXPECT().GetOrderPage(gomock.Any(), testPickupPointID, pagination).Return([]*pvz_domain.Order{testOrder()}, int64(1), nil)
+
+		// act
+		rec := f.serve(http.MethodGet, "/orders", "")
+
+		// assert
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	})
+
+	t.Run("rejects a malformed limit", func(t *testing.T) {
+		// arrange
+		f := newHTTPHandlerTestFixture(t)
+
+		// act
+		rec := f.serve(http.MethodGet, "/orders?limit=ten", "")
+
+		// assert
+		require.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+		violations := decodeViolations(t, rec)
+		require.Len(t, violations, 1)
+		assert.Equal(t, "limit", violations[0].Field)
+	})
+}
+
+func TestHTTPHandler_ListRefundedOrders(t *testing.T) {
+	t.Run("renders refunded orders of the requested page as documented", func(t *testing.T) {
+		// arrange
+		f := newHTTPHandlerTestFixture(t)
+		refunded := testOrder()
+		refunded.Status = pvz_domain.OrderStatusRefunded
+		delivered := testOrder()
+		delivered.ID = 2
+		delivered.Status = pvz_domain.OrderStatusDelivered
+		pagination := &pvz_domain.Pagination{Offset: 20, Limit: 5}
+		f.cache.EXPECT().GetOrderPage(gomock.Any(), testPickupPointID, pagination).Return([]*pvz_domain.Order{refunded, delivered}, int64(1), nil)
+
+		// act
+		rec := f.serve(http.MethodGet, "/orders/refunds?offset=20&limit=5", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Equal(t, []int64{testOrderID}, decodeOrderIDs(t, rec))
+	})
+
+	t.Run("uses the default page", func(t *testing.T) {
+		// arrange
+		f := newHTTPHandlerTestFixture(t)
+		pagination := &pvz_domain.Pagination{Offset: 0, Limit: 10}
+		f.cache.EXPECT().GetOrderPage(gomock.Any(), testPickupPointID, pagination).Return([]*pvz_domain.Order{testOrder()}, int64(1), nil)
+
+		// act
+		rec := f.serve(http.MethodGet, "/orders/refunds", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Empty(t, decodeOrderIDs(t, rec))
+	})
+}
+
+func TestHTTPHandler_ListOrdersHistory(t *testing.T) {
+	t.Run("renders the requested page most recently changed first as documented", func(t *testing.T) {
+		// arrange
+		f := newHTTPHandlerTestFixture(t)
+		older := testOrder()
+		newer := testOrder()
+		newer.ID = 2
+		newer.History[len(newer.History)-1].Timestamp = newer.History[len(newer.History)-1].Timestamp.Add(time.Hour)
+		pagination := &pvz_domain.Pagination{Offset: 5, Limit: 2}
+		f.cache.EXPECT().GetOrderPage(gomock.Any(), testPickupPointID, pagination).Return([]*pvz_domain.Order{older, newer}, int64(1), nil)
+
+		// act
+		rec := f.serve(http.MethodGet, "/orders-history?offset=5&limit=2", "")
+
+		// assert
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		assert.Equal(t, []int64{2, testOrderID}, decodeOrderIDs(t, rec))
+	})
+
+	t.Run("rejects a malformed offset", func(t *testing.T) {
+		// arrange
+		f := newHTTPHandlerTestFixture(t)
+
+		// act
+		rec := f.serve(http.MethodGet, "/orders-history?offset=-1", "")
+
+		// assert
+		require.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+		violations := decodeViolations(t, rec)
+		require.Len(t, violations, 1)
+		assert.Equal(t, "offset", violations[0].Field)
+	})
+}
+
+func TestHTTPHandler_CreateCell(t *testing.T) {
+	t.Run("provisions a cell at the pickup point", func(t *testing.T) {
+		// arrange
+		f := newHTTPHandlerTestFixture(t)
+		f.cells.EXPECT().Add(gomock.Any(), &pvz_domain.Cell{
+			PickupPointID: testPickupPointID,
+			Code:          "A-01",
+			Size:          pvz_domain.CellSizeMedium,
+			Capacity:      4,
+			MaxWeight:     30,
+		}).DoAndReturn(func(_ context.Context, cell *pvz_domain.Cell) (*pvz_domain.Cell, error) {
+			created := *cell
+			created.ID = 9
+			return &created, nil
+		})
+
+		// act
+		rec := f.serve(http.MethodPost, "/cells", `{"code": "A-01", "size": 2, "capacity": 4, "max_weight": 30}`)
+
+		// assert
+		require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+		var cell pvz_domain.Cell
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &cell))
+		assert.Equal(t, int64(9), cell.ID)
+	})
+
+	t.Run("rejects a taken code as a conflict", func(t *testing.T) {
+		// arrange
+		f := newHTTPHandlerTestFixture(t)
+		f.cells.EXPECT().Add(gomock.Any(), gomock.Any()).Return(nil, pvz_domain.ErrCellCodeTaken)
+
+		// act
+		rec := f.serve(http.MethodPost, "/cells", `{"code": "A-01", "size": 2, "capacity": 4, "max_weight": 30}`)
+
+		// assert
+		assert.Equal(t, http.StatusConflict, rec.Code, rec.Body.String())
+	})
+
+	t.Run("rejects an unknown size", func(t *testing.T) {
+		// arrange
+		f := newHTTPHandlerTestFixture(t)
+
+		// act
+		rec := f.serve(http.MethodPost, "/cells", `{"code": "A-01", "size": 4, "capacity": 4, "max_weight": 30}`)
+
+		// assert
+		require.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+		violations := decodeViolations(t, rec)
+		require.Len(t, violations, 1)
+		assert.Equal(t, "size", violations[0].Field)
+	})
+}
+
+func TestHTTPHandler_RegeneratePickupCode(t *testing.T) {
+	target := "/orders/1/pickup-code?recipientID=123"
+
+	t.Run("refuses a request without the staff token", func(t *testing.T) {
+		// arrange
+		f := newHTTPHandlerTestFixture(t)
+
+		// act
+		rec := f.serve(http.MethodPost, target, "")
+
+		// assert
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, rec.Body.String())
+		assert.Equal(t, "Bearer", rec.Header().Get("WWW-Authenticate"))
+	})
+
+	t.Run("refuses a wrong staff token", func(t *testing.T) {
+		// arrange
+		f := newHTTPHandlerTestFixture(t)
+
+		// act
+		rec := f.serveWithHeader(http.MethodPost, target, "", http.Header{"Authorization": {"Bearer guess"}})
+
+		// assert
+		assert.Equal(t, http.StatusForbidden, rec.Code, rec.Body.String())
+	})
+
+	t.Run("lets staff through", func(t *testing.T) {
+		// arrange
+		f := newHTTPHandlerTestFixture(t)
+		f.txManager.EXPECT().RunReadCommitted(gomock.Any(), gomock.Any()).Return(pvz_domain.ErrOrderNotFound)
+
+		// act
+		rec := f.serveWithHeader(http.MethodPost, target, "", http.Header{"Authorization": {"Bearer " + testStaffToken}})
+
+		// assert
+		assert.Equal(t, http.StatusNotFound, rec.Code, rec.Body.String())
+	})
+}
+
+func TestHTTPHandler_DeleteOrder(t *testing.T) {
+	t.Run("renders an order kept within its storage period as a conflict", func(t *testing.T) {
+		// arrange
+		f := newHTTPHandlerTestFixture(t)
+		f.txManager.EXPECT().RunRepeatableRead(gomock.Any(), gomock.Any()).
+			Return(fmt.Errorf("%w: order 1 can't be returned to courier", pvz_domain.ErrOrderNotExpired))
+
+		// act
+		rec := f.serve(http.MethodDelete, "/orders/1?recipientID=123", "")
+
+		// assert
+		assert.Equal(t, http.StatusConflict, rec.Code, rec.Body.String())
+	})
+}
+
+func TestHTTPHandler_TransferOrder(t *testing.T) {
+	t.Run("renders a transfer to the current pickup point as invalid", func(t *testing.T) {
+		// arrange
+		f := newHTTPHandlerTestFixture(t)
+		f.txManager.EXPECT().RunRepeatableRead(gomock.Any(), gomock.Any()).
+			Return(fmt.Errorf("%w: order 1 to pickup point 7", pvz_domain.ErrInvalidTransferTarget))
+
+		// act
+		rec := f.serve(http.MethodPost, "/orders/transfers", `{"order_id": 1, "pickup_point_id": 7}`)
+
+		// assert
+		assert.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+	})
+}
+
+func TestHTTPHandler_GetOrderCell(t *testing.T) {
+	t.Run("renders an order outside of a cell as not found", func(t *testing.T) {
+		// arrange
+		f := newHTTPHandlerTestFixture(t)
+		order := testOrder()
+		order.CellID = nil
+		f.storage.EXPECT().GetByID(gomock.Any(), testPickupPointID, testOrderID).Return(order, nil)
+
+		// act
+		rec := f.serve(http.MethodGet, "/cells/orders/1", "")
+
+		// assert
+		assert.Equal(t, http.StatusNotFound, rec.Code, rec.Body.String())
+	})
+
+	t.Run("renders a missing order as not found", func(t *testing.T) {
+		// arrange
+		f := newHTTPHandlerTestFixture(t)
+		f.storage.EXPECT().GetByID(gomock.Any(), testPickupPointID, testOrderID).Return(nil, pvz_domain.ErrOrderNotFound)
+
+		// act
+		rec := f.serve(http.MethodGet, "/cells/orders/1", "")
+
+		// assert
+		assert.Equal(t, http.StatusNotFound, rec.Code, rec.Body.String())
+	})
+}
+
+func TestHTTPHandler_UpdateOrders(t *testing.T) {
+	// arrange
+	f := newHTTPHandlerTestFixture(t)
+	body := `{"order_ids": [1, "2"], "recipient_id": 123, "action": "steal", "note": "x"}`
+
+	// act
+	rec := f.serve(http.MethodPatch, "/orders", body)
+
+	// assert
+	require.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+	fields := make([]string, 0)
+	for _, v := range decodeViolations(t, rec) {
+		fields = append(fields, v.Field)
+	}
+	assert.ElementsMatch(t, []string{"body", "order_ids[1]", "action"}, fields)
+}
+
+func decodeViolations(t *testing.T, rec *httptest.ResponseRecorder) []pvz_validation.Violation {
+	t.Helper()
+
+	var response ErrResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	return response.Violations
+}
+
+func decodeOrderIDs(t *testing.T, rec *httptest.ResponseRecorder) []int64 {
+	t.Helper()
+
+	var orders []struct {
+		ID int64 `json:"id"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &orders))
+
+	ids := make([]int64, 0, len(orders))
+	for _, order := range orders {
+		ids = append(ids, order.ID)
+	}
+	return ids
+}
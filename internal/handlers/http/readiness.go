@@ -0,0 +1,32 @@
+package pvz_http
+
+import (
+	"net/http"
+
+	"github.com/Staspol216/gh1/internal/health"
+	"github.com/go-chi/render"
+)
+
+type ReadinessResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+func (rd *ReadinessResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	if rd.Status == pvz_health.StatusDown {
+		render.Status(r, http.StatusServiceUnavailable)
+	} else {
+		render.Status(r, http.StatusOK)
+	}
+	return nil
+}
+
+// Ready reports the readiness checks; it fails with 503 only when a critical one is down.
+func (h *HTTPHandler) Ready(w http.ResponseWriter, r *http.Request) {
+	report := pvz_health.Run(r.Context(), h.readinessChecks)
+
+	_ = render.Render(w, r, &ReadinessResponse{
+		Status: report.Status,
+		Checks: report.Checks,
+	})
+}
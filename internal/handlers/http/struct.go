@@ -3,8 +3,11 @@ package pvz_http
 import (
 	"errors"
 	"net/http"
+	"time"
 
 	"github.com/Staspol216/gh1/internal/domain/order"
+	"github.com/Staspol216/gh1/internal/service/order"
+	"github.com/Staspol216/gh1/internal/validation"
 	"github.com/Staspol216/gh1/pkg/logger"
 	"github.com/go-chi/render"
 	"go.uber.org/zap"
@@ -19,11 +22,12 @@ type OrderRequestMetadata struct {
 }
 
 type ErrResponse struct {
-	Err            error  `json:"-"`               // low-level runtime error
-	HTTPStatusCode int    `json:"-"`               // http response status code
-	StatusText     string `json:"status"`          // user-level status message
-	AppCode        int64  `json:"code,omitempty"`  // application-specific error code
-	ErrorText      string `json:"error,omitempty"` // application-level error message, for debugging
+	Err            error                      `json:"-"`                    // low-level runtime error
+	HTTPStatusCode int                        `json:"-"`                    // http response status code
+	StatusText     string                     `json:"status"`               // user-level status message
+	AppCode        int64                      `json:"code,omitempty"`       // application-specific error code
+	ErrorText      string                     `json:"error,omitempty"`      // application-level error message, for debugging
+	Violations     []pvz_validation.Violation `json:"violations,omitempty"` // rejected fields of an invalid request
 }
 
 func (e *ErrResponse) Render(w http.ResponseWriter, r *http.Request) error {
@@ -50,12 +54,19 @@ func ErrRender(err error) render.Renderer {
 }
 
 func ErrInvalidRequest(err error) render.Renderer {
-	return &ErrResponse{
+	response := &ErrResponse{
 		Err:            err,
 		HTTPStatusCode: 400,
 		StatusText:     "Invalid request.",
 		ErrorText:      err.Error(),
 	}
+
+	var validationErr *pvz_validation.Error
+	if errors.As(err, &validationErr) {
+		response.Violations = validationErr.Violations
+	}
+
+	return response
 }
 
 func ErrInternal(err error) render.Renderer {
@@ -67,6 +78,66 @@ func ErrInternal(err error) render.Renderer {
 	}
 }
 
+func ErrUnauthorized(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 401,
+		StatusText:     "Unauthorized.",
+		ErrorText:      err.Error(),
+	}
+}
+
+func ErrForbidden(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 403,
+		StatusText:     "Forbidden.",
+		ErrorText:      err.Error(),
+	}
+}
+
+func ErrConflict(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 409,
+		StatusText:     "Conflict.",
+		ErrorText:      err.Error(),
+	}
+}
+
+func ErrPreconditionFailed(err error) render.Renderer {
+	return &ErrResponse{
+		Err:            err,
+		HTTPStatusCode: 412,
+		StatusText:     "Precondition failed.",
+		ErrorText:      err.Error(),
+	}
+}
+
+// ErrFromService maps errors of order operations to client errors where the client can act on them.
+func ErrFromService(err error) render.Renderer {
+	switch {
+	case errors.Is(err, pvz_domain.ErrOrderNotFound), errors.Is(err, pvz_domain.ErrOrderNotInCell):
+		return ErrNotFound
+	case pvz_domain.IsPickupCodeError(err):
+		return ErrForbidden(err)
+	case errors.Is(err, pvz_domain.ErrPickupCodeNotIssued):
+		return ErrConflict(err)
+	case errors.Is(err, pvz_domain.ErrOrderVersionMismatch):
+		return ErrPreconditionFailed(err)
+	case errors.Is(err, pvz_domain.ErrOrderVersionConflict), errors.Is(err, pvz_domain.ErrCellCodeTaken):
+		return ErrConflict(err)
+	case pvz_domain.IsOrderStateError(err), errors.Is(err, pvz_domain.ErrNoFreeCell):
+		return ErrConflict(err)
+	case errors.Is(err, pvz_domain.ErrIntakeBatchSize), errors.Is(err, pvz_domain.ErrInvalidOrderParams),
+		errors.Is(err, pvz_domain.ErrInvalidTransferTarget), errors.Is(err, pvz_domain.ErrInvalidOrderItem),
+		errors.Is(err, pvz_domain.ErrInvalidMoney), errors.Is(err, pvz_domain.ErrCurrencyMismatch):
+		return ErrInvalidRequest(err)
+	default:
+		return ErrInternal(err)
+	}
+}
+
 var ErrNotFound = &ErrResponse{HTTPStatusCode: 404, StatusText: "Resource not found."}
 
 // Order Response
@@ -102,23 +173,71 @@ type OrderCreateRequest struct {
 }
 
 func (a *OrderCreateRequest) Bind(r *http.Request) error {
-	if a.Order == nil {
-		return errors.New("missing required Order fields")
-	}
+	return pvz_validation.Check(append(
+		pvz_validation.OrderParams("order", a.Order, time.Now()),
+		pvz_validation.PackagingType("packagingType", a.PackagingType),
+	)...)
+}
 
+// OrdersBatchCreateRequest
+
+type OrdersBatchCreateRequest struct {
+	Orders []*OrderCreateRequest `json:"orders"`
+}
+
+func (a *OrdersBatchCreateRequest) Bind(r *http.Request) error {
+	return pvz_validation.Check(pvz_validation.IntakeBatch("orders", len(a.Orders))...)
+}
+
+// OrdersBatchCreateResponse
+
+// OrderIntakeResult holds either the accepted order or the reason its parcel was rejected.
+type OrderIntakeResult struct {
+	OrderID    int64  `json:"order_id,omitempty"`
+	PickupCode string `json:"pickup_code,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+type OrdersBatchCreateResponse struct {
+	Results []*OrderIntakeResult `json:"results"`
+}
+
+func (rd *OrdersBatchCreateResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	render.Status(r, http.StatusOK)
 	return nil
 }
 
+func NewOrdersBatchCreateResponse(results []*pvz_order_service.ParcelIntake) *OrdersBatchCreateResponse {
+	response := &OrdersBatchCreateResponse{Results: make([]*OrderIntakeResult, 0, len(results))}
+	for _, result := range results {
+		if result.Err != nil {
+			response.Results = append(response.Results, &OrderIntakeResult{Error: result.Err.Error()})
+			continue
+		}
+		response.Results = append(response.Results, &OrderIntakeResult{
+			OrderID:    result.Accepted.OrderID,
+			PickupCode: result.Accepted.PickupCode,
+		})
+	}
+	return response
+}
+
 // OrderCreateRequest
 
 type OrderUpdateRequest struct {
-	OrderIDs    []int64 `json:"order_ids"`
-	RecipientID int64   `json:"recipient_id"`
-	Action      string  `json:"action"`
+	OrderIDs    []int64           `json:"order_ids"`
+	RecipientID int64             `json:"recipient_id"`
+	Action      string            `json:"action"`
+	PickupCodes map[int64]string  `json:"pickup_codes"`
+	ItemIDs     map[int64][]int64 `json:"item_ids"`
 }
 
 func (a *OrderUpdateRequest) Bind(r *http.Request) error {
-	return nil
+	return pvz_validation.Check(pvz_validation.ServeRecipient(&pvz_order_service.ServeRecipientParams{
+		OrderIDs:    a.OrderIDs,
+		RecipientID: a.RecipientID,
+		Action:      a.Action,
+	})...)
 }
 
 type OrderUpdateResponse struct{}
@@ -148,7 +267,8 @@ func NewOrderDeletedResponse() *OrderDeletedResponse {
 // OrderIDResponse
 
 type OrderIDResponse struct {
-	OrderID int64 `json:"order_id"`
+	OrderID    int64  `json:"order_id"`
+	PickupCode string `json:"pickup_code"`
 }
 
 func (rd *OrderIDResponse) Render(w http.ResponseWriter, r *http.Request) error {
@@ -156,6 +276,114 @@ func (rd *OrderIDResponse) Render(w http.ResponseWriter, r *http.Request) error
 	return nil
 }
 
-func NewOrderIDResponse(id int64) *OrderIDResponse {
-	return &OrderIDResponse{OrderID: id}
+func NewOrderIDResponse(id int64, pickupCode string) *OrderIDResponse {
+	return &OrderIDResponse{OrderID: id, PickupCode: pickupCode}
+}
+
+// PickupCodeResponse
+
+type PickupCodeResponse struct {
+	OrderID    int64  `json:"order_id"`
+	PickupCode string `json:"pickup_code"`
+}
+
+func (rd *PickupCodeResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	render.Status(r, http.StatusCreated)
+	return nil
+}
+
+func NewPickupCodeResponse(id int64, pickupCode string) *PickupCodeResponse {
+	return &PickupCodeResponse{OrderID: id, PickupCode: pickupCode}
+}
+
+// OrderTransferRequest
+
+type OrderTransferRequest struct {
+	OrderID       int64 `json:"order_id"`
+	PickupPointID int64 `json:"pickup_point_id"`
+}
+
+func (a *OrderTransferRequest) Bind(r *http.Request) error {
+	return pvz_validation.Check(pvz_validation.Transfer(a.OrderID, "pickup_point_id", a.PickupPointID)...)
+}
+
+// OrderTransferAcceptRequest
+
+type OrderTransferAcceptRequest struct {
+	OrderID int64 `json:"order_id"`
+}
+
+func (a *OrderTransferAcceptRequest) Bind(r *http.Request) error {
+	return pvz_validation.Check(pvz_validation.OrderRef(a.OrderID)...)
+}
+
+type OrderTransferResponse struct{}
+
+func NewOrderTransferResponse() *OrderTransferResponse {
+	return &OrderTransferResponse{}
+}
+
+func (rd *OrderTransferResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	render.Status(r, http.StatusOK)
+	return nil
+}
+
+// CellResponse
+
+type CellResponse struct {
+	*pvz_domain.Cell
+}
+
+func (rd *CellResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	render.Status(r, http.StatusOK)
+	return nil
+}
+
+func NewCellResponse(c *pvz_domain.Cell) *CellResponse {
+	return &CellResponse{Cell: c}
+}
+
+type CellCreatedResponse struct {
+	*pvz_domain.Cell
+}
+
+func (rd *CellCreatedResponse) Render(w http.ResponseWriter, r *http.Request) error {
+	render.Status(r, http.StatusCreated)
+	return nil
+}
+
+func NewCellCreatedResponse(c *pvz_domain.Cell) *CellCreatedResponse {
+	return &CellCreatedResponse{Cell: c}
+}
+
+// CellRequest
+
+type CellRequest struct {
+	Code      string              `json:"code"`
+	Size      pvz_domain.CellSize `json:"size"`
+	Capacity  int64               `json:"capacity"`
+	MaxWeight float64             `json:"max_weight"`
+}
+
+func (a *CellRequest) Bind(r *http.Request) error {
+	return pvz_validation.Check(pvz_validation.Cell(a.Code, a.Size, a.Capacity, a.MaxWeight)...)
+}
+
+func (a *CellRequest) ToCell() *pvz_domain.Cell {
+	return &pvz_domain.Cell{
+		Code:      a.Code,
+		Size:      a.Size,
+		Capacity:  a.Capacity,
+		MaxWeight: a.MaxWeight,
+	}
+}
+
+// OrderReshelveRequest
+
+type OrderReshelveRequest struct {
+	CellID int64 `json:"cell_id"`
+}
+
+func (a *OrderReshelveRequest) Bind(r *http.Request) error {
+	return pvz_validation.Check(pvz_validation.Positive("cell_id", a.CellID))
 }
@@ -0,0 +1,58 @@
+package pvz_health
+
+import (
+	"context"
+	"time"
+)
+
+// checkTimeout bounds a whole round of checks.
+const checkTimeout = 2 * time.Second
+
+const (
+	StatusUp       = "up"
+	StatusDown     = "down"
+	StatusDegraded = "degraded"
+)
+
+// Check is a dependency the instance reports readiness of. A failing critical check
+// makes the instance not ready; any other failing check only marks it degraded.
+type Check struct {
+	Name     string
+	Critical bool
+	Check    func(ctx context.Context) error
+}
+
+// Report is the outcome of a round of checks: the overall status and the status of
+// every check by name.
+type Report struct {
+	Status string
+	Checks map[string]string
+}
+
+// Run runs the checks one after another within checkTimeout.
+func Run(ctx context.Context, checks []Check) Report {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	report := Report{
+		Status: StatusUp,
+		Checks: make(map[string]string, len(checks)),
+	}
+	for _, check := range checks {
+		if err := check.Check(ctx); err != nil {
+			if check.Critical {
+				report.Checks[check.Name] = StatusDown
+				report.Status = StatusDown
+				continue
+			}
+			report.Checks[check.Name] = StatusDegraded
+			if report.Status == StatusUp {
+				report.Status = StatusDegraded
+			}
+			continue
+		}
+		report.Checks[check.Name] = StatusUp
+	}
+
+	return report
+}
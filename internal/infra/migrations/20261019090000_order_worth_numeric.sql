@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE orders
+  ALTER COLUMN worth TYPE NUMERIC(14, 2) USING round(worth::numeric, 2);
+
+ALTER TABLE orders
+  ADD COLUMN currency VARCHAR(3) NOT NULL DEFAULT 'RUB';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE orders
+  DROP COLUMN currency;
+
+ALTER TABLE orders
+  ALTER COLUMN worth TYPE DOUBLE PRECISION USING worth::double precision;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Existing data belongs to the single pickup point we ran before multi-point support.
+ALTER TABLE orders
+  ADD COLUMN pickup_point_id BIGINT NOT NULL DEFAULT 1;
+
+ALTER TABLE order_records
+  ADD COLUMN pickup_point_id BIGINT NOT NULL DEFAULT 1;
+
+ALTER TABLE orders_statuses_outbox
+  ADD COLUMN pickup_point_id BIGINT NOT NULL DEFAULT 1;
+
+ALTER TABLE orders ALTER COLUMN pickup_point_id DROP DEFAULT;
+ALTER TABLE order_records ALTER COLUMN pickup_point_id DROP DEFAULT;
+ALTER TABLE orders_statuses_outbox ALTER COLUMN pickup_point_id DROP DEFAULT;
+
+CREATE INDEX orders_pickup_point_id_idx ON orders (pickup_point_id, id);
+CREATE INDEX order_records_order_id_idx ON order_records (order_id, timestamp);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS order_records_order_id_idx;
+DROP INDEX IF EXISTS orders_pickup_point_id_idx;
+
+ALTER TABLE orders_statuses_outbox DROP COLUMN pickup_point_id;
+ALTER TABLE order_records DROP COLUMN pickup_point_id;
+ALTER TABLE orders DROP COLUMN pickup_point_id;
+-- +goose StatementEnd
@@ -0,0 +1,49 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+-- +goose StatementBegin
+ALTER TYPE order_status ADD VALUE IF NOT EXISTS 'in_transfer';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TYPE order_status ADD VALUE IF NOT EXISTS 'transferred';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+BEGIN;
+
+ALTER TABLE orders
+  ALTER COLUMN status TYPE text USING status::text;
+
+ALTER TABLE order_records
+  ALTER COLUMN status TYPE text USING status::text;
+
+ALTER TABLE orders_statuses_outbox
+  ALTER COLUMN order_status TYPE text USING order_status::text;
+
+-- Orders still travelling between points are treated as received by the source point.
+UPDATE orders SET status = 'received' WHERE status IN ('in_transfer', 'transferred');
+UPDATE order_records SET status = 'received' WHERE status IN ('in_transfer', 'transferred');
+UPDATE orders_statuses_outbox SET order_status = 'received' WHERE order_status IN ('in_transfer', 'transferred');
+
+DROP TYPE order_status;
+
+CREATE TYPE order_status AS ENUM (
+  'received',
+  'returned',
+  'delivered',
+  'refunded',
+  'storage_ended'
+);
+
+ALTER TABLE orders
+  ALTER COLUMN status TYPE order_status USING status::order_status;
+
+ALTER TABLE order_records
+  ALTER COLUMN status TYPE order_status USING status::order_status;
+
+ALTER TABLE orders_statuses_outbox
+  ALTER COLUMN order_status TYPE order_status USING order_status::order_status;
+
+COMMIT;
+-- +goose StatementEnd
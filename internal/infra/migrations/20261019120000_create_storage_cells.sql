@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE storage_cells (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    pickup_point_id BIGINT NOT NULL,
+    code VARCHAR NOT NULL,
+    size SMALLINT NOT NULL,
+    capacity BIGINT NOT NULL CHECK (capacity > 0),
+    max_weight DOUBLE PRECISION NOT NULL CHECK (max_weight > 0),
+    orders_count BIGINT NOT NULL DEFAULT 0 CHECK (orders_count >= 0),
+    load_weight DOUBLE PRECISION NOT NULL DEFAULT 0 CHECK (load_weight >= 0),
+    UNIQUE (pickup_point_id, code)
+);
+
+CREATE INDEX storage_cells_allocation_idx ON storage_cells (pickup_point_id, size, id);
+
+ALTER TABLE orders
+  ADD COLUMN cell_id BIGINT NULL REFERENCES storage_cells(id) ON DELETE SET NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE orders DROP COLUMN cell_id;
+
+DROP TABLE storage_cells;
+-- +goose StatementEnd
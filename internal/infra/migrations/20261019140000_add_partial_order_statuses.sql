@@ -0,0 +1,54 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+-- +goose StatementBegin
+ALTER TYPE order_status ADD VALUE IF NOT EXISTS 'partially_delivered';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+ALTER TYPE order_status ADD VALUE IF NOT EXISTS 'partially_refunded';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+BEGIN;
+
+ALTER TABLE orders
+  ALTER COLUMN status TYPE text USING status::text;
+
+ALTER TABLE order_records
+  ALTER COLUMN status TYPE text USING status::text;
+
+ALTER TABLE orders_statuses_outbox
+  ALTER COLUMN order_status TYPE text USING order_status::text;
+
+-- Partially served orders keep the status of the items that left the pickup point.
+UPDATE orders SET status = 'delivered' WHERE status = 'partially_delivered';
+UPDATE orders SET status = 'refunded' WHERE status = 'partially_refunded';
+UPDATE order_records SET status = 'delivered' WHERE status = 'partially_delivered';
+UPDATE order_records SET status = 'refunded' WHERE status = 'partially_refunded';
+UPDATE orders_statuses_outbox SET order_status = 'delivered' WHERE order_status = 'partially_delivered';
+UPDATE orders_statuses_outbox SET order_status = 'refunded' WHERE order_status = 'partially_refunded';
+
+DROP TYPE order_status;
+
+CREATE TYPE order_status AS ENUM (
+  'received',
+  'returned',
+  'delivered',
+  'refunded',
+  'storage_ended',
+  'in_transfer',
+  'transferred'
+);
+
+ALTER TABLE orders
+  ALTER COLUMN status TYPE order_status USING status::order_status;
+
+ALTER TABLE order_records
+  ALTER COLUMN status TYPE order_status USING status::order_status;
+
+ALTER TABLE orders_statuses_outbox
+  ALTER COLUMN order_status TYPE order_status USING order_status::order_status;
+
+COMMIT;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE order_items (
+    id BIGSERIAL PRIMARY KEY NOT NULL,
+    order_id BIGINT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    sku VARCHAR NOT NULL,
+    quantity BIGINT NOT NULL CHECK (quantity > 0),
+    price NUMERIC(14, 2) NOT NULL CHECK (price >= 0),
+    currency VARCHAR(3) NOT NULL DEFAULT 'RUB',
+    weight DOUBLE PRECISION NOT NULL CHECK (weight > 0),
+    status order_status NOT NULL
+);
+
+CREATE INDEX order_items_order_id_idx ON order_items (order_id);
+
+ALTER TABLE order_records
+  ADD COLUMN item_id BIGINT NULL REFERENCES order_items(id) ON DELETE CASCADE;
+
+ALTER TABLE orders_statuses_outbox
+  ADD COLUMN item_id BIGINT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE orders_statuses_outbox DROP COLUMN item_id;
+
+ALTER TABLE order_records DROP COLUMN item_id;
+
+DROP TABLE order_items;
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE FUNCTION notify_order_record() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('order_records', json_build_object(
+        'id', NEW.id,
+        'order_id', NEW.order_id,
+        'pickup_point_id', NEW.pickup_point_id,
+        'item_id', NEW.item_id,
+        'status', NEW.status,
+        'description', NEW.description,
+        'timestamp', to_char(NEW.timestamp, 'YYYY-MM-DD"T"HH24:MI:SS.US"Z"')
+    )::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER order_records_notify
+    AFTER INSERT ON order_records
+    FOR EACH ROW EXECUTE FUNCTION notify_order_record();
+
+CREATE INDEX order_records_pickup_point_id_id_idx ON order_records (pickup_point_id, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX order_records_pickup_point_id_id_idx;
+
+DROP TRIGGER order_records_notify ON order_records;
+
+DROP FUNCTION notify_order_record();
+-- +goose StatementEnd
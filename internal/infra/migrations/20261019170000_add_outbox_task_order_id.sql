@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Tasks queued before the column was added are not tied to an order and keep 0.
+ALTER TABLE orders_statuses_outbox
+  ADD COLUMN order_id BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE orders_statuses_outbox ALTER COLUMN order_id DROP DEFAULT;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE orders_statuses_outbox DROP COLUMN order_id;
+-- +goose StatementEnd
@@ -0,0 +1,170 @@
+package order_events
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/Staspol216/gh1/internal/domain/order"
+	"github.com/Staspol216/gh1/pkg/logger"
+	"github.com/Staspol216/gh1/pkg/monitoring"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"go.uber.org/zap"
+)
+
+// statusChangesChannel is notified by a trigger on order_records, see the
+// notify_order_records migration.
+const statusChangesChannel = "order_records"
+
+const reconnectDelay = time.Second
+
+type statusChangeDTO struct {
+	ID            int64                  `json:"id"`
+	OrderID       int64                  `json:"order_id"`
+	PickupPointID int64                  `json:"pickup_point_id"`
+	ItemID        *int64                 `json:"item_id"`
+	Status        pvz_domain.OrderStatus `json:"status"`
+	Description   string                 `json:"description"`
+	Timestamp     time.Time              `json:"timestamp"`
+}
+
+// Feed fans committed order status changes out to the watchers of their pickup
+// point. Changes arrive over LISTEN/NOTIFY, so they are only seen once committed.
+// A watcher whose buffer is full, and every watcher when the connection drops, is
+// cut off by closing its channel and has to resume from the last change it got.
+type Feed struct {
+	pool   *pgxpool.Pool
+	buffer int
+
+	mu          sync.Mutex
+	subscribers map[int64]map[chan *pvz_domain.OrderStatusChange]struct{}
+	count       int
+}
+
+func NewFeed(pool *pgxpool.Pool, buffer int) *Feed {
+	return &Feed{
+		pool:        pool,
+		buffer:      buffer,
+		subscribers: make(map[int64]map[chan *pvz_domain.OrderStatusChange]struct{}),
+	}
+}
+
+// Subscribe returns changes of the pickup point committed from now on. The returned
+// func stops the subscription and must be called once the watcher is done.
+func (f *Feed) Subscribe(pickupPointID int64) (<-chan *pvz_domain.OrderStatusChange, func()) {
+	ch := make(chan *pvz_domain.OrderStatusChange, f.buffer)
+
+	f.mu.Lock()
+	if f.subscribers[pickupPointID] == nil {
+		f.subscribers[pickupPointID] = make(map[chan *pvz_domain.OrderStatusChange]struct{})
+	}
+	f.subscribers[pickupPointID][ch] = struct{}{}
+	f.count++
+	monitoring.SetOrderWatchers(f.count)
+	f.mu.Unlock()
+
+	return ch, func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		f.drop(pickupPointID, ch)
+	}
+}
+
+// drop closes the subscriber channel unless it was dropped already. f.mu must be held.
+func (f *Feed) drop(pickupPointID int64, ch chan *pvz_domain.OrderStatusChange) {
+	if _, ok := f.subscribers[pickupPointID][ch]; !ok {
+		return
+	}
+	delete(f.subscribers[pickupPointID], ch)
+	if len(f.subscribers[pickupPointID]) == 0 {
+		delete(f.subscribers, pickupPointID)
+	}
+	close(ch)
+	f.count--
+	monitoring.SetOrderWatchers(f.count)
+}
+
+func (f *Feed) publish(change *pvz_domain.OrderStatusChange) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for ch := range f.subscribers[change.PickupPointID] {
+		select {
+		case ch <- change:
+		default:
+			monitoring.ObserveOrderWatcherDropped("lagging")
+			f.drop(change.PickupPointID, ch)
+		}
+	}
+}
+
+// dropAll cuts off every watcher, since changes sent while the feed was not
+// listening are lost.
+func (f *Feed) dropAll() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for pickupPointID, subscribers := range f.subscribers {
+		for ch := range subscribers {
+			monitoring.ObserveOrderWatcherDropped("disconnected")
+			f.drop(pickupPointID, ch)
+		}
+	}
+}
+
+// Run listens for changes until ctx is done, reconnecting when the connection drops.
+func (f *Feed) Run(ctx context.Context) {
+	for {
+		err := f.listen(ctx)
+		f.dropAll()
+
+		if ctx.Err() != nil {
+			app_logger.MyLogger.Info("order status feed finished by context done")
+			return
+		}
+		app_logger.MyLogger.Error("order status feed lost connection", zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+func (f *Feed) listen(ctx context.Context) error {
+	pooled, err := f.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// A listening connection must not go back to the pool.
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+statusChangesChannel); err != nil {
+		return err
+	}
+
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+
+		var dto statusChangeDTO
+		if err := json.Unmarshal([]byte(notification.Payload), &dto); err != nil {
+			app_logger.MyLogger.Error("decode order status change", zap.String("payload", notification.Payload), zap.Error(err))
+			continue
+		}
+		f.publish(&pvz_domain.OrderStatusChange{
+			RecordID:      dto.ID,
+			OrderID:       dto.OrderID,
+			PickupPointID: dto.PickupPointID,
+			ItemID:        dto.ItemID,
+			Status:        dto.Status,
+			Description:   dto.Description,
+			Timestamp:     dto.Timestamp,
+		})
+	}
+}
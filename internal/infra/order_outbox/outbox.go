@@ -59,6 +59,8 @@ func (w *OrderOutbox) AddTask(ctx context.Context, task *OrderOutboxTask) (id in
 	startTime := time.Now()
 	span, _ := tracing.StartSpanFromContext(ctx, "Outbox.AddTask")
 	span.SetTag("order_status", string(task.OrderStatus))
+	span.SetTag("order_id", task.OrderID)
+	span.SetTag("pickup_point_id", task.PickupPointID)
 	defer func() {
 		if id != 0 {
 			span.SetTag("task_id", id)
@@ -68,14 +70,20 @@ func (w *OrderOutbox) AddTask(ctx context.Context, task *OrderOutboxTask) (id in
 
 	query := `
 	INSERT INTO orders_statuses_outbox (
+		order_id,
+		pickup_point_id,
+		item_id,
 		status,
 		created_at,
 		order_status,
 		description,
 		timestamp
-	) VALUES ($1, $2, $3, $4, $5) RETURNING id;`
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id;`
 
 	row := w.Db.ExecQueryRow(ctx, query,
+		task.OrderID,
+		task.PickupPointID,
+		task.ItemID,
 		task.Status,
 		task.CreatedAt,
 		task.OrderStatus,
@@ -91,6 +99,71 @@ func (w *OrderOutbox) AddTask(ctx context.Context, task *OrderOutboxTask) (id in
 	return id, err
 }
 
+// AddTasks inserts tasks in one statement.
+func (w *OrderOutbox) AddTasks(ctx context.Context, tasks []*OrderOutboxTask) (err error) {
+	startTime := time.Now()
+	span, _ := tracing.StartSpanFromContext(ctx, "Outbox.AddTasks")
+	span.SetTag("tasks_count", len(tasks))
+	defer func() {
+		tracing.FinishSpan(span, startTime, err)
+	}()
+
+	if len(tasks) == 0 {
+		return nil
+	}
+
+	orderIDs := make([]int64, 0, len(tasks))
+	pickupPointIDs := make([]int64, 0, len(tasks))
+	itemIDs := make([]*int64, 0, len(tasks))
+	statuses := make([]string, 0, len(tasks))
+	createdAts := make([]time.Time, 0, len(tasks))
+	orderStatuses := make([]string, 0, len(tasks))
+	descriptions := make([]string, 0, len(tasks))
+	timestamps := make([]time.Time, 0, len(tasks))
+	for _, task := range tasks {
+		orderIDs = append(orderIDs, task.OrderID)
+		pickupPointIDs = append(pickupPointIDs, task.PickupPointID)
+		itemIDs = append(itemIDs, task.ItemID)
+		statuses = append(statuses, task.Status)
+		createdAts = append(createdAts, task.CreatedAt)
+		orderStatuses = append(orderStatuses, string(task.OrderStatus))
+		descriptions = append(descriptions, task.Description)
+		timestamps = append(timestamps, task.Timestamp)
+	}
+
+	query := `
+	INSERT INTO orders_statuses_outbox (
+		order_id,
+		pickup_point_id,
+		item_id,
+		status,
+		created_at,
+		order_status,
+		description,
+		timestamp
+	)
+	SELECT u.order_id, u.pickup_point_id, u.item_id, u.status::outbox_task_status, u.created_at,
+		u.order_status::order_status, u.description, u.timestamp
+	FROM unnest($1::bigint[], $2::bigint[], $3::bigint[], $4::text[], $5::timestamp[], $6::text[], $7::text[], $8::timestamp[])
+		AS u(order_id, pickup_point_id, item_id, status, created_at, order_status, description, timestamp);`
+
+	_, err = w.Db.Exec(ctx, query,
+		orderIDs,
+		pickupPointIDs,
+		itemIDs,
+		statuses,
+		createdAts,
+		orderStatuses,
+		descriptions,
+		timestamps,
+	)
+	if err != nil {
+		app_logger.MyLogger.Error("add outbox tasks", zap.Error(err))
+	}
+	monitoring.ObserveOutboxTask("add_batch", err)
+	return err
+}
+
 func (w *OrderOutbox) LockPending(ctx context.Context) ([]OrderOutboxTask, error) {
 	var tasks []OrderOutboxTask
 
@@ -107,11 +180,15 @@ func (w *OrderOutbox) LockPending(ctx context.Context) ([]OrderOutboxTask, error
 	WHERE o.id = picked.id
 	RETURNING
 		o.id,
+		o.order_id,
+		o.pickup_point_id,
+		o.item_id,
 		o.status,
 		o.created_at;
 	`
 
-	err := w.Db.Select(ctx, &tasks, query)
+	// The statement updates the picked rows, so it must not be routed to the replica.
+	err := w.Db.Select(pvz_ports.WithPrimary(ctx), &tasks, query)
 
 	if err != nil {
 		return nil, err
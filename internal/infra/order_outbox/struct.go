@@ -19,12 +19,19 @@ type OrderOutboxTask struct {
 	Status    OrderOutboxTaskStatus `json:"status"`
 	CreatedAt time.Time             `json:"created_at"`
 
-	OrderStatus pvz_domain.OrderStatus `json:"order_status"`
-	Description string                 `json:"description"`
-	Timestamp   time.Time              `json:"timestamp"`
+	OrderID       int64                  `json:"order_id"`
+	PickupPointID int64                  `json:"pickup_point_id"`
+	ItemID        *int64                 `json:"item_id,omitempty"`
+	OrderStatus   pvz_domain.OrderStatus `json:"order_status"`
+	Description   string                 `json:"description"`
+	Timestamp     time.Time              `json:"timestamp"`
 }
 
-func (t *OrderOutboxTask) SetOrderStatusDetails(orderRecord *pvz_domain.OrderRecord) {
+// SetOrderStatusDetails fills the event of the task from a history record of the order.
+func (t *OrderOutboxTask) SetOrderStatusDetails(orderID int64, orderRecord *pvz_domain.OrderRecord) {
+	t.OrderID = orderID
+	t.PickupPointID = orderRecord.PickupPointID
+	t.ItemID = orderRecord.ItemID
 	t.OrderStatus = orderRecord.Status
 	t.Description = orderRecord.Description
 	t.Timestamp = orderRecord.Timestamp
@@ -19,12 +19,15 @@ func NewDatabase(tx pvz_ports.TransactionManager) *Database {
 	}
 }
 
+// Get and Select are reads: outside a transaction they may be served by the replica.
+// Statements that write and return rows must go through ExecQueryRow or run in a
+// transaction.
 func (db Database) Get(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
-	return pgxscan.Get(ctx, db.tx.GetQueryEngine(ctx), dest, query, args...)
+	return pgxscan.Get(ctx, db.tx.GetReadQueryEngine(ctx), dest, query, args...)
 }
 
 func (db Database) Select(ctx context.Context, dest interface{}, query string, args ...interface{}) error {
-	return pgxscan.Select(ctx, db.tx.GetQueryEngine(ctx), dest, query, args...)
+	return pgxscan.Select(ctx, db.tx.GetReadQueryEngine(ctx), dest, query, args...)
 }
 
 func (db Database) Exec(ctx context.Context, query string, args ...interface{}) (pgconn.CommandTag, error) {
@@ -0,0 +1,119 @@
+package cell
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Staspol216/gh1/internal/domain/order"
+	"github.com/Staspol216/gh1/internal/ports"
+	"github.com/jackc/pgx/v4"
+)
+
+type CellRepo struct {
+	db pvz_ports.DB
+}
+
+func NewCellRepo(database pvz_ports.DB) (*CellRepo, error) {
+	return &CellRepo{
+		db: database,
+	}, nil
+}
+
+// Add provisions an empty cell at the pickup point of the given one.
+func (r *CellRepo) Add(ctx context.Context, cell *pvz_domain.Cell) (*pvz_domain.Cell, error) {
+	c, err := scanCell(r.db.ExecQueryRow(ctx, `
+		INSERT INTO storage_cells (pickup_point_id, code, size, capacity, max_weight)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (pickup_point_id, code) DO NOTHING
+		RETURNING `+cellColumns+`;
+	`, cell.PickupPointID, cell.Code, cell.Size, cell.Capacity, cell.MaxWeight))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("cell %q: %w", cell.Code, pvz_domain.ErrCellCodeTaken)
+		}
+		return nil, err
+	}
+	return transformCellDtoToModel(c), nil
+}
+
+// Allocate picks the best fitting free cell and occupies it in a single statement.
+// Cells locked by concurrent transactions are skipped instead of waited for.
+func (r *CellRepo) Allocate(ctx context.Context, pickupPointID int64, size pvz_domain.CellSize, weight float64) (*pvz_domain.Cell, error) {
+	c, err := scanCell(r.db.ExecQueryRow(ctx, `
+		WITH candidate AS (
+			SELECT id
+			FROM storage_cells
+			WHERE pickup_point_id = $1
+				AND size >= $2
+				AND orders_count < capacity
+				AND load_weight + $3 <= max_weight
+			ORDER BY size ASC, orders_count DESC, id ASC
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE storage_cells AS c
+		SET orders_count = c.orders_count + 1,
+			load_weight = c.load_weight + $3
+		FROM candidate
+		WHERE c.id = candidate.id
+		RETURNING c.id, c.pickup_point_id, c.code, c.size, c.capacity, c.max_weight, c.orders_count, c.load_weight;
+	`, pickupPointID, size, weight))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, pvz_domain.ErrNoFreeCell
+		}
+		return nil, err
+	}
+	return transformCellDtoToModel(c), nil
+}
+
+// Occupy places an order of the given weight into a specific cell, waiting for
+// concurrent allocations of the same cell to finish.
+func (r *CellRepo) Occupy(ctx context.Context, pickupPointID int64, cellID int64, weight float64) (*pvz_domain.Cell, error) {
+	c, err := scanCell(r.db.ExecQueryRow(ctx, `
+		UPDATE storage_cells
+		SET orders_count = orders_count + 1,
+			load_weight = load_weight + $3
+		WHERE id = $1
+			AND pickup_point_id = $2
+			AND orders_count < capacity
+			AND load_weight + $3 <= max_weight
+		RETURNING `+cellColumns+`;
+	`, cellID, pickupPointID, weight))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("cell %d: %w", cellID, pvz_domain.ErrNoFreeCell)
+		}
+		return nil, err
+	}
+	return transformCellDtoToModel(c), nil
+}
+
+func (r *CellRepo) Release(ctx context.Context, pickupPointID int64, cellID int64, weight float64) error {
+	commandTag, err := r.db.Exec(ctx, `
+		UPDATE storage_cells
+		SET orders_count = GREATEST(orders_count - 1, 0),
+			load_weight = GREATEST(load_weight - $3, 0)
+		WHERE id = $1 AND pickup_point_id = $2;
+	`, cellID, pickupPointID, weight)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() != 1 {
+		return fmt.Errorf("cell %d not found", cellID)
+	}
+	return nil
+}
+
+func (r *CellRepo) GetByID(ctx context.Context, pickupPointID int64, cellID int64) (*pvz_domain.Cell, error) {
+	var c cellDTO
+	err := r.db.Get(ctx, &c, "SELECT * FROM storage_cells WHERE id=$1 AND pickup_point_id=$2", cellID, pickupPointID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("cell %d not found", cellID)
+		}
+		return nil, err
+	}
+	return transformCellDtoToModel(&c), nil
+}
@@ -0,0 +1,43 @@
+package cell
+
+import (
+	"github.com/Staspol216/gh1/internal/domain/order"
+	"github.com/jackc/pgx/v4"
+)
+
+// cellColumns are the columns scanCell reads, in its order. Statements that change
+// a cell return them through ExecQueryRow, which runs on the primary, unlike Get.
+const cellColumns = "id, pickup_point_id, code, size, capacity, max_weight, orders_count, load_weight"
+
+type cellDTO struct {
+	ID            int64               `db:"id"`
+	PickupPointID int64               `db:"pickup_point_id"`
+	Code          string              `db:"code"`
+	Size          pvz_domain.CellSize `db:"size"`
+	Capacity      int64               `db:"capacity"`
+	MaxWeight     float64             `db:"max_weight"`
+	OrdersCount   int64               `db:"orders_count"`
+	LoadWeight    float64             `db:"load_weight"`
+}
+
+func transformCellDtoToModel(c *cellDTO) *pvz_domain.Cell {
+	return &pvz_domain.Cell{
+		ID:            c.ID,
+		PickupPointID: c.PickupPointID,
+		Code:          c.Code,
+		Size:          c.Size,
+		Capacity:      c.Capacity,
+		MaxWeight:     c.MaxWeight,
+		OrdersCount:   c.OrdersCount,
+		LoadWeight:    c.LoadWeight,
+	}
+}
+
+func scanCell(row pgx.Row) (*cellDTO, error) {
+	var c cellDTO
+	err := row.Scan(&c.ID, &c.PickupPointID, &c.Code, &c.Size, &c.Capacity, &c.MaxWeight, &c.OrdersCount, &c.LoadWeight)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
@@ -0,0 +1,222 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/Staspol216/gh1/internal/ports"
+	"github.com/Staspol216/gh1/pkg/logger"
+	"github.com/Staspol216/gh1/pkg/monitoring"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	cacheProbeTimeout    = time.Second
+	cacheRecoveryTimeout = 10 * time.Second
+)
+
+// RecoveryHook runs against the backend once a probe succeeds, before the breaker
+// closes. An error keeps the breaker open until the next probe.
+type RecoveryHook func(ctx context.Context, backend pvz_ports.Cache) error
+
+// CacheBreaker is a circuit breaker in front of the cache backend. After threshold
+// failures in a row it opens and fails every call right away with
+// pvz_ports.ErrCacheUnavailable, so that requests skip the cache instead of waiting
+// on it. While open, Run probes the backend and closes the breaker once it answers
+// and the recovery hooks, which replay invalidations skipped meanwhile, succeed.
+type CacheBreaker struct {
+	next          pvz_ports.Cache
+	threshold     int64
+	probeInterval time.Duration
+	recoveryHooks []RecoveryHook
+
+	failures atomic.Int64
+	open     atomic.Bool
+}
+
+func NewCacheBreaker(next pvz_ports.Cache, threshold int64, probeInterval time.Duration) *CacheBreaker {
+	return &CacheBreaker{
+		next:          next,
+		threshold:     threshold,
+		probeInterval: probeInterval,
+	}
+}
+
+// OnRecovery registers a hook to run before the breaker closes. Hooks must be
+// registered before Run.
+func (b *CacheBreaker) OnRecovery(hook RecoveryHook) {
+	b.recoveryHooks = append(b.recoveryHooks, hook)
+}
+
+func (b *CacheBreaker) IsOpen() bool {
+	return b.open.Load()
+}
+
+// Trip opens the breaker regardless of the failure count.
+func (b *CacheBreaker) Trip() {
+	if b.open.CompareAndSwap(false, true) {
+		app_logger.MyLogger.Warn("cache circuit opened, requests bypass the cache")
+		monitoring.SetCacheCircuitOpen(true)
+	}
+}
+
+func (b *CacheBreaker) reset() {
+	b.failures.Store(0)
+	if b.open.CompareAndSwap(true, false) {
+		app_logger.MyLogger.Info("cache circuit closed, cache is used again")
+		monitoring.SetCacheCircuitOpen(false)
+	}
+}
+
+// Run probes the backend every probe interval while the breaker is open.
+func (b *CacheBreaker) Run(ctx context.Context) {
+	ticker := time.NewTicker(b.probeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			app_logger.MyLogger.Info("cache breaker probes finished by context done")
+			return
+		case <-ticker.C:
+			if !b.IsOpen() {
+				continue
+			}
+			probeCtx, cancel := context.WithTimeout(ctx, cacheProbeTimeout)
+			err := b.next.Ping(probeCtx).Err()
+			cancel()
+			monitoring.ObserveCacheOperation("breaker_probe", err)
+			if err != nil {
+				continue
+			}
+			if err := b.recover(ctx); err != nil {
+				app_logger.MyLogger.Warn("cache recovery failed, circuit stays open", zap.Error(err))
+				continue
+			}
+			b.reset()
+		}
+	}
+}
+
+func (b *CacheBreaker) recover(ctx context.Context) error {
+	recoveryCtx, cancel := context.WithTimeout(ctx, cacheRecoveryTimeout)
+	defer cancel()
+
+	var errs []error
+	for _, hook := range b.recoveryHooks {
+		if err := hook(recoveryCtx, b.next); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (b *CacheBreaker) allow() bool {
+	if b.IsOpen() {
+		monitoring.ObserveCacheOperation("breaker_rejected", pvz_ports.ErrCacheUnavailable)
+		return false
+	}
+	return true
+}
+
+// record counts failures of the backend. Misses and callers giving up are not failures.
+func (b *CacheBreaker) record(err error) {
+	if err == nil || errors.Is(err, redis.Nil) {
+		b.failures.Store(0)
+		return
+	}
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	if b.failures.Add(1) >= b.threshold {
+		b.Trip()
+	}
+}
+
+func (b *CacheBreaker) Get(ctx context.Context, key string) *redis.StringCmd {
+	if !b.allow() {
+		cmd := redis.NewStringCmd(ctx)
+		cmd.SetErr(pvz_ports.ErrCacheUnavailable)
+		return cmd
+	}
+	cmd := b.next.Get(ctx, key)
+	b.record(cmd.Err())
+	return cmd
+}
+
+func (b *CacheBreaker) MGet(ctx context.Context, keys ...string) *redis.SliceCmd {
+	if !b.allow() {
+		cmd := redis.NewSliceCmd(ctx)
+		cmd.SetErr(pvz_ports.ErrCacheUnavailable)
+		return cmd
+	}
+	cmd := b.next.MGet(ctx, keys...)
+	b.record(cmd.Err())
+	return cmd
+}
+
+func (b *CacheBreaker) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) *redis.StatusCmd {
+	if !b.allow() {
+		cmd := redis.NewStatusCmd(ctx)
+		cmd.SetErr(pvz_ports.ErrCacheUnavailable)
+		return cmd
+	}
+	cmd := b.next.Set(ctx, key, value, expiration)
+	b.record(cmd.Err())
+	return cmd
+}
+
+func (b *CacheBreaker) Del(ctx context.Context, keys ...string) *redis.IntCmd {
+	if !b.allow() {
+		cmd := redis.NewIntCmd(ctx)
+		cmd.SetErr(pvz_ports.ErrCacheUnavailable)
+		return cmd
+	}
+	cmd := b.next.Del(ctx, keys...)
+	b.record(cmd.Err())
+	return cmd
+}
+
+func (b *CacheBreaker) Incr(ctx context.Context, key string) *redis.IntCmd {
+	if !b.allow() {
+		cmd := redis.NewIntCmd(ctx)
+		cmd.SetErr(pvz_ports.ErrCacheUnavailable)
+		return cmd
+	}
+	cmd := b.next.Incr(ctx, key)
+	b.record(cmd.Err())
+	return cmd
+}
+
+func (b *CacheBreaker) Pipelined(ctx context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error) {
+	if !b.allow() {
+		return nil, pvz_ports.ErrCacheUnavailable
+	}
+	cmds, err := b.next.Pipelined(ctx, fn)
+	b.record(err)
+	return cmds, err
+}
+
+// Ping always reaches the backend, so that health checks see its real state.
+func (b *CacheBreaker) Ping(ctx context.Context) *redis.StatusCmd {
+	return b.next.Ping(ctx)
+}
+
+func (b *CacheBreaker) Publish(ctx context.Context, channel string, message interface{}) *redis.IntCmd {
+	if !b.allow() {
+		cmd := redis.NewIntCmd(ctx)
+		cmd.SetErr(pvz_ports.ErrCacheUnavailable)
+		return cmd
+	}
+	cmd := b.next.Publish(ctx, channel, message)
+	b.record(cmd.Err())
+	return cmd
+}
+
+// Subscribe bypasses the breaker: the subscription reconnects on its own.
+func (b *CacheBreaker) Subscribe(ctx context.Context, channels ...string) *redis.PubSub {
+	return b.next.Subscribe(ctx, channels...)
+}
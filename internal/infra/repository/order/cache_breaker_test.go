@@ -0,0 +1,318 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Staspol216/gh1/internal/ports"
+	"github.com/Staspol216/gh1/internal/ports/mocks"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	testBreakerThreshold     = 3
+	testBreakerProbeInterval = 5 * time.Millisecond
+)
+
+var errTestBackend = errors.New("connection refused")
+
+func stringCmd(err error) *redis.StringCmd {
+	cmd := redis.NewStringCmd(context.Background())
+	cmd.SetErr(err)
+	return cmd
+}
+
+func statusCmd(err error) *redis.StatusCmd {
+	cmd := redis.NewStatusCmd(context.Background())
+	cmd.SetErr(err)
+	return cmd
+}
+
+// runBreaker probes in the background until the test ends.
+func runBreaker(t *testing.T, breaker *CacheBreaker) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		breaker.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+func TestCacheBreaker_Opens(t *testing.T) {
+	t.Parallel()
+
+	t.Run("opens after threshold failures in a row and rejects calls", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		backend := mocks.NewMockCache(gomock.NewController(t))
+		breaker := NewCacheBreaker(backend, testBreakerThreshold, time.Hour)
+		backend.EXPECT().Get(gomock.Any(), "key").Return(stringCmd(errTestBackend)).Times(testBreakerThreshold)
+
+		// act
+		for i := 0; i < testBreakerThreshold; i++ {
+			require.False(t, breaker.IsOpen(), "open after %d failures", i)
+			require.ErrorIs(t, breaker.Get(context.Background(), "key").Err(), errTestBackend)
+		}
+		err := breaker.Get(context.Background(), "key").Err()
+
+		// assert
+		assert.True(t, breaker.IsOpen())
+		assert.ErrorIs(t, err, pvz_ports.ErrCacheUnavailable)
+	})
+
+	t.Run("does not count misses and cancelled calls", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		backend := mocks.NewMockCache(gomock.NewController(t))
+		breaker := NewCacheBreaker(backend, testBreakerThreshold, time.Hour)
+		backend.EXPECT().Get(gomock.Any(), "key").Return(stringCmd(redis.Nil)).Times(testBreakerThreshold)
+		backend.EXPECT().Get(gomock.Any(), "key").Return(stringCmd(context.Canceled)).Times(testBreakerThreshold)
+
+		// act
+		for i := 0; i < 2*testBreakerThreshold; i++ {
+			breaker.Get(context.Background(), "key")
+		}
+
+		// assert
+		assert.False(t, breaker.IsOpen())
+	})
+
+	t.Run("starts counting again after a success", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		backend := mocks.NewMockCache(gomock.NewController(t))
+		breaker := NewCacheBreaker(backend, testBreakerThreshold, time.Hour)
+		gomock.InOrder(
+			backend.EXPECT().Get(gomock.Any(), "key").Return(stringCmd(errTestBackend)).Times(testBreakerThreshold-1),
+			backend.EXPECT().Get(gomock.Any(), "key").Return(stringCmd(nil)),
+			backend.EXPECT().Get(gomock.Any(), "key").Return(stringCmd(errTestBackend)).Times(testBreakerThreshold-1),
+		)
+
+		// act
+		for i := 0; i < 2*testBreakerThreshold-1; i++ {
+			breaker.Get(context.Background(), "key")
+		}
+
+		// assert
+		assert.False(t, breaker.IsOpen())
+	})
+}
+
+func TestCacheBreaker_Run(t *testing.T) {
+	t.Parallel()
+
+	t.Run("stays open while probes fail", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		backend := mocks.NewMockCache(gomock.NewController(t))
+		breaker := NewCacheBreaker(backend, testBreakerThreshold, testBreakerProbeInterval)
+		probed := make(chan struct{}, 1)
+		backend.EXPECT().Ping(gomock.Any()).DoAndReturn(func(context.Context) *redis.StatusCmd {
+			select {
+			case probed <- struct{}{}:
+			default:
+			}
+			return statusCmd(errTestBackend)
+		}).MinTimes(1)
+		breaker.Trip()
+
+		// act
+		runBreaker(t, breaker)
+		<-probed
+
+		// assert
+		assert.True(t, breaker.IsOpen())
+	})
+
+	t.Run("closes once a probe succeeds and recovery hooks ran", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		backend := mocks.NewMockCache(gomock.NewController(t))
+		breaker := NewCacheBreaker(backend, testBreakerThreshold, testBreakerProbeInterval)
+		backend.EXPECT().Ping(gomock.Any()).Return(statusCmd(nil)).MinTimes(1)
+		var hookBackend pvz_ports.Cache
+		breaker.OnRecovery(func(_ context.Context, backend pvz_ports.Cache) error {
+			assert.True(t, breaker.IsOpen(), "hooks run before the breaker closes")
+			hookBackend = backend
+			return nil
+		})
+		breaker.Trip()
+
+		// act
+		runBreaker(t, breaker)
+
+		// assert
+		require.Eventually(t, func() bool { return !breaker.IsOpen() }, time.Second, testBreakerProbeInterval)
+		assert.Same(t, backend, hookBackend)
+	})
+
+	t.Run("stays open while a recovery hook fails", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		backend := mocks.NewMockCache(gomock.NewController(t))
+		breaker := NewCacheBreaker(backend, testBreakerThreshold, testBreakerProbeInterval)
+		backend.EXPECT().Ping(gomock.Any()).Return(statusCmd(nil)).MinTimes(1)
+		var mu sync.Mutex
+		attempts := 0
+		breaker.OnRecovery(func(context.Context, pvz_ports.Cache) error {
+			mu.Lock()
+			defer mu.Unlock()
+			attempts++
+			if attempts < 3 {
+				return errTestBackend
+			}
+			return nil
+		})
+		breaker.Trip()
+
+		// act
+		runBreaker(t, breaker)
+
+		// assert
+		require.Eventually(t, func() bool { return !breaker.IsOpen() }, time.Second, testBreakerProbeInterval)
+		mu.Lock()
+		defer mu.Unlock()
+		assert.Equal(t, 3, attempts)
+	})
+}
+
+// pipelinedCommands records the commands of Pipelined calls without sending them.
+type pipelinedCommands struct {
+	mu   sync.Mutex
+	cmds []string
+}
+
+func (p *pipelinedCommands) run(ctx context.Context, fn func(redis.Pipeliner) error) ([]redis.Cmder, error) {
+	pipe := redis.NewClient(&redis.Options{}).Pipeline()
+	if err := fn(pipe); err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, cmd := range pipe.Cmds() {
+		args := make([]string, 0, len(cmd.Args()))
+		for _, arg := range cmd.Args() {
+			args = append(args, arg.(string))
+		}
+		p.cmds = append(p.cmds, strings.Join(args, " "))
+	}
+	return pipe.Cmds(), nil
+}
+
+func (p *pipelinedCommands) sorted() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Sorted(slices.Values(p.cmds))
+}
+
+func TestCache_ReplaySkippedInvalidations(t *testing.T) {
+	t.Parallel()
+
+	t.Run("replays invalidations skipped while the breaker was open", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		backend := mocks.NewMockCache(gomock.NewController(t))
+		breaker := NewCacheBreaker(backend, testBreakerThreshold, time.Hour)
+		cache := NewOrderCache(breaker, nil, time.Minute, time.Minute, time.Minute)
+		breaker.Trip()
+		require.ErrorIs(t, cache.DeleteOrder(context.Background(), 7, 1), pvz_ports.ErrCacheUnavailable)
+		require.ErrorIs(t, cache.InvalidateOrderPages(context.Background(), 8), pvz_ports.ErrCacheUnavailable)
+
+		replayed := &pipelinedCommands{}
+		backend.EXPECT().Pipelined(gomock.Any(), gomock.Any()).DoAndReturn(replayed.run)
+
+		// act
+		err := cache.ReplaySkippedInvalidations(context.Background(), backend)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"del order:7:1 order:7:1:missing",
+			"incr orders:pages:7:generation",
+			"incr orders:pages:8:generation",
+		}, replayed.sorted())
+	})
+
+	t.Run("replays missed lookups forgotten while the breaker was open", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		backend := mocks.NewMockCache(gomock.NewController(t))
+		breaker := NewCacheBreaker(backend, testBreakerThreshold, time.Hour)
+		cache := NewOrderCache(breaker, nil, time.Minute, time.Minute, time.Minute)
+		breaker.Trip()
+		require.ErrorIs(t, cache.ForgetMissingOrders(context.Background(), 7, []int64{1, 2}), pvz_ports.ErrCacheUnavailable)
+
+		replayed := &pipelinedCommands{}
+		backend.EXPECT().Pipelined(gomock.Any(), gomock.Any()).DoAndReturn(replayed.run)
+
+		// act
+		err := cache.ReplaySkippedInvalidations(context.Background(), backend)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"del order:7:1 order:7:1:missing",
+			"del order:7:2 order:7:2:missing",
+			"incr orders:pages:7:generation",
+		}, replayed.sorted())
+	})
+
+	t.Run("replays each invalidation once", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		backend := mocks.NewMockCache(gomock.NewController(t))
+		breaker := NewCacheBreaker(backend, testBreakerThreshold, time.Hour)
+		cache := NewOrderCache(breaker, nil, time.Minute, time.Minute, time.Minute)
+		breaker.Trip()
+		require.Error(t, cache.InvalidateOrderPages(context.Background(), 7))
+		backend.EXPECT().Pipelined(gomock.Any(), gomock.Any()).DoAndReturn((&pipelinedCommands{}).run)
+
+		// act
+		firstErr := cache.ReplaySkippedInvalidations(context.Background(), backend)
+		secondErr := cache.ReplaySkippedInvalidations(context.Background(), backend)
+
+		// assert
+		require.NoError(t, firstErr)
+		require.NoError(t, secondErr)
+	})
+
+	t.Run("keeps invalidations when the replay fails", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		backend := mocks.NewMockCache(gomock.NewController(t))
+		breaker := NewCacheBreaker(backend, testBreakerThreshold, time.Hour)
+		cache := NewOrderCache(breaker, nil, time.Minute, time.Minute, time.Minute)
+		breaker.Trip()
+		require.Error(t, cache.DeleteOrder(context.Background(), 7, 1))
+
+		replayed := &pipelinedCommands{}
+		gomock.InOrder(
+			backend.EXPECT().Pipelined(gomock.Any(), gomock.Any()).Return(nil, errTestBackend),
+			backend.EXPECT().Pipelined(gomock.Any(), gomock.Any()).DoAndReturn(replayed.run),
+		)
+
+		// act
+		firstErr := cache.ReplaySkippedInvalidations(context.Background(), backend)
+		secondErr := cache.ReplaySkippedInvalidations(context.Background(), backend)
+
+		// assert
+		require.ErrorIs(t, firstErr, errTestBackend)
+		require.NoError(t, secondErr)
+		assert.Equal(t, []string{"del order:7:1 order:7:1:missing", "incr orders:pages:7:generation"}, replayed.sorted())
+	})
+}
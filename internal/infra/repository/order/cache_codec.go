@@ -0,0 +1,211 @@
+package order
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/Staspol216/gh1/internal/domain/order"
+	"github.com/klauspost/compress/snappy"
+	"github.com/klauspost/compress/zstd"
+)
+
+// cacheFormatVersion prefixes every cached value. Bump it whenever the stored shape
+// of an order changes, so that entries written by the previous release read as
+// misses instead of being decoded into the wrong shape. Version 2 encodes statuses
+// with the orders.v1 numbering.
+const cacheFormatVersion byte = 2
+
+// A cached value is laid out as [version][encoding][compression][payload].
+const cacheHeaderSize = 3
+
+var errCacheEntryOutdated = errors.New("cache entry has an outdated format")
+
+const (
+	CacheEncodingJSON     = "json"
+	CacheEncodingProtobuf = "protobuf"
+
+	CacheCompressionNone   = "none"
+	CacheCompressionZstd   = "zstd"
+	CacheCompressionSnappy = "snappy"
+)
+
+const (
+	encodingJSON byte = iota + 1
+	encodingProtobuf
+)
+
+const (
+	compressionNone byte = iota
+	compressionZstd
+	compressionSnappy
+)
+
+// cacheEncoding turns cached values into bytes and back.
+type cacheEncoding interface {
+	marshalOrder(cached *cachedOrder) ([]byte, error)
+	unmarshalOrder(b []byte) (*cachedOrder, error)
+	marshalPage(orders []*pvz_domain.Order) ([]byte, error)
+	unmarshalPage(b []byte) ([]*pvz_domain.Order, error)
+}
+
+// CacheCodec encodes cached orders and pages with the configured encoding and
+// compresses payloads of at least threshold bytes. Entries record how they were
+// written, so switching the encoding or compression keeps existing entries readable.
+type CacheCodec struct {
+	encoding    byte
+	compression byte
+	threshold   int
+
+	encodings map[byte]cacheEncoding
+	zstdEnc   *zstd.Encoder
+	zstdDec   *zstd.Decoder
+}
+
+func NewCacheCodec(encoding string, compression string, threshold int) (*CacheCodec, error) {
+	zstdEnc, err := zstd.NewWriter(nil)
+	if err != nil {
+		return nil, err
+	}
+	zstdDec, err := zstd.NewReader(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	codec := &CacheCodec{
+		threshold: threshold,
+		encodings: map[byte]cacheEncoding{
+			encodingJSON:     jsonCacheEncoding{},
+			encodingProtobuf: protobufCacheEncoding{},
+		},
+		zstdEnc: zstdEnc,
+		zstdDec: zstdDec,
+	}
+
+	switch encoding {
+	case CacheEncodingJSON:
+		codec.encoding = encodingJSON
+	case CacheEncodingProtobuf:
+		codec.encoding = encodingProtobuf
+	default:
+		return nil, fmt.Errorf("unknown cache encoding %q", encoding)
+	}
+
+	switch compression {
+	case CacheCompressionNone:
+		codec.compression = compressionNone
+	case CacheCompressionZstd:
+		codec.compression = compressionZstd
+	case CacheCompressionSnappy:
+		codec.compression = compressionSnappy
+	default:
+		return nil, fmt.Errorf("unknown cache compression %q", compression)
+	}
+
+	return codec, nil
+}
+
+func (c *CacheCodec) marshalOrder(cached *cachedOrder) ([]byte, error) {
+	payload, err := c.encodings[c.encoding].marshalOrder(cached)
+	if err != nil {
+		return nil, err
+	}
+	return c.seal(payload), nil
+}
+
+func (c *CacheCodec) unmarshalOrder(b []byte) (*cachedOrder, error) {
+	encoding, payload, err := c.open(b)
+	if err != nil {
+		return nil, err
+	}
+	return encoding.unmarshalOrder(payload)
+}
+
+func (c *CacheCodec) marshalPage(orders []*pvz_domain.Order) ([]byte, error) {
+	payload, err := c.encodings[c.encoding].marshalPage(orders)
+	if err != nil {
+		return nil, err
+	}
+	return c.seal(payload), nil
+}
+
+func (c *CacheCodec) unmarshalPage(b []byte) ([]*pvz_domain.Order, error) {
+	encoding, payload, err := c.open(b)
+	if err != nil {
+		return nil, err
+	}
+	return encoding.unmarshalPage(payload)
+}
+
+// seal compresses the payload if it is large enough and prepends the header. A
+// payload that does not shrink is stored as is.
+func (c *CacheCodec) seal(payload []byte) []byte {
+	var sealed []byte
+	if len(payload) >= c.threshold {
+		header := []byte{cacheFormatVersion, c.encoding, c.compression}
+		switch c.compression {
+		case compressionZstd:
+			sealed = c.zstdEnc.EncodeAll(payload, header)
+		case compressionSnappy:
+			sealed = append(header, snappy.Encode(nil, payload)...)
+		}
+	}
+	if sealed != nil && len(sealed) < cacheHeaderSize+len(payload) {
+		return sealed
+	}
+
+	return append([]byte{cacheFormatVersion, c.encoding, compressionNone}, payload...)
+}
+
+// open checks the header and returns the decompressed payload with its encoding.
+// Entries of another format version, including ones written before versioning,
+// yield errCacheEntryOutdated.
+func (c *CacheCodec) open(b []byte) (cacheEncoding, []byte, error) {
+	if len(b) < cacheHeaderSize || b[0] != cacheFormatVersion {
+		return nil, nil, errCacheEntryOutdated
+	}
+	encoding, ok := c.encodings[b[1]]
+	if !ok {
+		return nil, nil, errCacheEntryOutdated
+	}
+
+	payload := b[cacheHeaderSize:]
+	switch b[2] {
+	case compressionNone:
+		return encoding, payload, nil
+	case compressionZstd:
+		payload, err := c.zstdDec.DecodeAll(payload, nil)
+		return encoding, payload, err
+	case compressionSnappy:
+		payload, err := snappy.Decode(nil, payload)
+		return encoding, payload, err
+	default:
+		return nil, nil, errCacheEntryOutdated
+	}
+}
+
+type jsonCacheEncoding struct{}
+
+func (jsonCacheEncoding) marshalOrder(cached *cachedOrder) ([]byte, error) {
+	return json.Marshal(cached)
+}
+
+func (jsonCacheEncoding) unmarshalOrder(b []byte) (*cachedOrder, error) {
+	var cached cachedOrder
+	if err := json.Unmarshal(b, &cached); err != nil {
+		return nil, err
+	}
+	return &cached, nil
+}
+
+func (jsonCacheEncoding) marshalPage(orders []*pvz_domain.Order) ([]byte, error) {
+	return json.Marshal(orders)
+}
+
+func (jsonCacheEncoding) unmarshalPage(b []byte) ([]*pvz_domain.Order, error) {
+	var orders []*pvz_domain.Order
+	if err := json.Unmarshal(b, &orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
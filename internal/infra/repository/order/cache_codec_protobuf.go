@@ -0,0 +1,287 @@
+package order
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Staspol216/gh1/internal/domain/order"
+	"github.com/Staspol216/gh1/pkg/api/orders/v1"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Field numbers of the cached order envelope. The envelope is written with
+// protowire directly, so that the API proto does not carry cache-only messages.
+const (
+	cachedOrderOrderField     protowire.Number = 1
+	cachedOrderExpiresAtField protowire.Number = 2
+	cachedOrderLoadTimeField  protowire.Number = 3
+)
+
+var protoStatuses = map[pvz_domain.OrderStatus]orders_v1.OrderStatus{
+	pvz_domain.OrderStatusReceived:           orders_v1.OrderStatus_ORDER_STATUS_RECEIVED,
+	pvz_domain.OrderStatusReturned:           orders_v1.OrderStatus_ORDER_STATUS_RETURNED,
+	pvz_domain.OrderStatusDelivered:          orders_v1.OrderStatus_ORDER_STATUS_DELIVERED,
+	pvz_domain.OrderStatusRefunded:           orders_v1.OrderStatus_ORDER_STATUS_REFUNDED,
+	pvz_domain.OrderStatusExpired:            orders_v1.OrderStatus_ORDER_STATUS_STORAGE_ENDED,
+	pvz_domain.OrderStatusNone:               orders_v1.OrderStatus_ORDER_STATUS_NONE,
+	pvz_domain.OrderStatusInTransfer:         orders_v1.OrderStatus_ORDER_STATUS_IN_TRANSFER,
+	pvz_domain.OrderStatusTransferred:        orders_v1.OrderStatus_ORDER_STATUS_TRANSFERRED,
+	pvz_domain.OrderStatusPartiallyDelivered: orders_v1.OrderStatus_ORDER_STATUS_PARTIALLY_DELIVERED,
+	pvz_domain.OrderStatusPartiallyRefunded:  orders_v1.OrderStatus_ORDER_STATUS_PARTIALLY_REFUNDED,
+}
+
+var domainStatuses = func() map[orders_v1.OrderStatus]pvz_domain.OrderStatus {
+	statuses := make(map[orders_v1.OrderStatus]pvz_domain.OrderStatus, len(protoStatuses))
+	for domainStatus, protoStatus := range protoStatuses {
+		statuses[protoStatus] = domainStatus
+	}
+	return statuses
+}()
+
+// protobufCacheEncoding stores orders as the API Order message and pages as
+// GetOrdersResponse.
+type protobufCacheEncoding struct{}
+
+func (protobufCacheEncoding) marshalOrder(cached *cachedOrder) ([]byte, error) {
+	order, err := orderToProto(cached.Order)
+	if err != nil {
+		return nil, err
+	}
+	orderBytes, err := proto.Marshal(order)
+	if err != nil {
+		return nil, err
+	}
+
+	var b []byte
+	b = protowire.AppendTag(b, cachedOrderOrderField, protowire.BytesType)
+	b = protowire.AppendBytes(b, orderBytes)
+	b = protowire.AppendTag(b, cachedOrderExpiresAtField, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(cached.ExpiresAt.UnixNano()))
+	b = protowire.AppendTag(b, cachedOrderLoadTimeField, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(cached.LoadTime))
+	return b, nil
+}
+
+func (protobufCacheEncoding) unmarshalOrder(b []byte) (*cachedOrder, error) {
+	var cached cachedOrder
+	for len(b) > 0 {
+		number, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		switch {
+		case number == cachedOrderOrderField && typ == protowire.BytesType:
+			orderBytes, n := protowire.ConsumeBytes(b)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			var order orders_v1.Order
+			if err := proto.Unmarshal(orderBytes, &order); err != nil {
+				return nil, err
+			}
+			domainOrder, err := orderFromProto(&order)
+			if err != nil {
+				return nil, err
+			}
+			cached.Order = domainOrder
+			b = b[n:]
+		case number == cachedOrderExpiresAtField && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			cached.ExpiresAt = time.Unix(0, int64(v))
+			b = b[n:]
+		case number == cachedOrderLoadTimeField && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(b)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			cached.LoadTime = time.Duration(v)
+			b = b[n:]
+		default:
+			n := protowire.ConsumeFieldValue(number, typ, b)
+			if n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			b = b[n:]
+		}
+	}
+	return &cached, nil
+}
+
+func (protobufCacheEncoding) marshalPage(orders []*pvz_domain.Order) ([]byte, error) {
+	page := &orders_v1.GetOrdersResponse{Orders: make([]*orders_v1.Order, 0, len(orders))}
+	for _, order := range orders {
+		protoOrder, err := orderToProto(order)
+		if err != nil {
+			return nil, err
+		}
+		page.Orders = append(page.Orders, protoOrder)
+	}
+	return proto.Marshal(page)
+}
+
+func (protobufCacheEncoding) unmarshalPage(b []byte) ([]*pvz_domain.Order, error) {
+	var page orders_v1.GetOrdersResponse
+	if err := proto.Unmarshal(b, &page); err != nil {
+		return nil, err
+	}
+
+	orders := make([]*pvz_domain.Order, 0, len(page.GetOrders()))
+	for _, protoOrder := range page.GetOrders() {
+		order, err := orderFromProto(protoOrder)
+		if err != nil {
+			return nil, err
+		}
+		orders = append(orders, order)
+	}
+	return orders, nil
+}
+
+func statusToProto(status pvz_domain.OrderStatus) (orders_v1.OrderStatus, error) {
+	protoStatus, ok := protoStatuses[status]
+	if !ok {
+		return 0, fmt.Errorf("unknown order status %q", status)
+	}
+	return protoStatus, nil
+}
+
+func statusFromProto(status orders_v1.OrderStatus) (pvz_domain.OrderStatus, error) {
+	domainStatus, ok := domainStatuses[status]
+	if !ok {
+		return "", fmt.Errorf("unknown order status %v", status)
+	}
+	return domainStatus, nil
+}
+
+func timeToProto(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
+
+func timeFromProto(t *timestamppb.Timestamp) *time.Time {
+	if t == nil {
+		return nil
+	}
+	value := t.AsTime()
+	return &value
+}
+
+func orderToProto(o *pvz_domain.Order) (*orders_v1.Order, error) {
+	status, err := statusToProto(o.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]*orders_v1.OrderRecord, 0, len(o.History))
+	for _, record := range o.History {
+		recordStatus, err := statusToProto(record.Status)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, &orders_v1.OrderRecord{
+			Timestamp:     timestamppb.New(record.Timestamp),
+			Status:        recordStatus,
+			Description:   record.Description,
+			ItemId:        record.ItemID,
+			PickupPointId: record.PickupPointID,
+		})
+	}
+
+	items := make([]*orders_v1.OrderItem, 0, len(o.Items))
+	for _, item := range o.Items {
+		itemStatus, err := statusToProto(item.Status)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, &orders_v1.OrderItem{
+			Id:       item.ID,
+			Sku:      item.SKU,
+			Quantity: item.Quantity,
+			Price:    &orders_v1.Money{MinorUnits: item.Price.Amount, CurrencyCode: item.Price.Currency},
+			Weight:   item.Weight,
+			Status:   itemStatus,
+		})
+	}
+
+	return &orders_v1.Order{
+		Id:             o.ID,
+		PickupPointId:  o.PickupPointID,
+		RecipientId:    o.RecipientID,
+		ExpirationDate: timestamppb.New(o.ExpirationDate),
+		DeliveredDate:  timeToProto(o.DeliveredDate),
+		RefundedDate:   timeToProto(o.RefundedDate),
+		ReturnedDate:   timeToProto(o.ReturnedDate),
+		Status:         status,
+		CellId:         o.CellID,
+		History:        history,
+		Weight:         o.Weight,
+		WorthMoney:     &orders_v1.Money{MinorUnits: o.Worth.Amount, CurrencyCode: o.Worth.Currency},
+		Items:          items,
+		Version:        o.Version,
+	}, nil
+}
+
+// orderFromProto is the inverse of orderToProto. Empty history and items come back
+// as nil slices, since protobuf does not tell them apart.
+func orderFromProto(o *orders_v1.Order) (*pvz_domain.Order, error) {
+	status, err := statusFromProto(o.GetStatus())
+	if err != nil {
+		return nil, err
+	}
+
+	var history []pvz_domain.OrderRecord
+	for _, record := range o.GetHistory() {
+		recordStatus, err := statusFromProto(record.GetStatus())
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, pvz_domain.OrderRecord{
+			PickupPointID: record.GetPickupPointId(),
+			ItemID:        record.ItemId,
+			Timestamp:     record.GetTimestamp().AsTime(),
+			Status:        recordStatus,
+			Description:   record.GetDescription(),
+		})
+	}
+
+	var items []*pvz_domain.OrderItem
+	for _, item := range o.GetItems() {
+		itemStatus, err := statusFromProto(item.GetStatus())
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, &pvz_domain.OrderItem{
+			ID:       item.GetId(),
+			SKU:      item.GetSku(),
+			Quantity: item.GetQuantity(),
+			Price:    pvz_domain.Money{Amount: item.GetPrice().GetMinorUnits(), Currency: item.GetPrice().GetCurrencyCode()},
+			Weight:   item.GetWeight(),
+			Status:   itemStatus,
+		})
+	}
+
+	return &pvz_domain.Order{
+		ID:             o.GetId(),
+		PickupPointID:  o.GetPickupPointId(),
+		RecipientID:    o.GetRecipientId(),
+		ExpirationDate: o.GetExpirationDate().AsTime(),
+		DeliveredDate:  timeFromProto(o.GetDeliveredDate()),
+		RefundedDate:   timeFromProto(o.GetRefundedDate()),
+		ReturnedDate:   timeFromProto(o.GetReturnedDate()),
+		Status:         status,
+		CellID:         o.CellId,
+		History:        history,
+		Weight:         o.GetWeight(),
+		Worth:          pvz_domain.Money{Amount: o.GetWorthMoney().GetMinorUnits(), Currency: o.GetWorthMoney().GetCurrencyCode()},
+		Items:          items,
+		Version:        o.GetVersion(),
+	}, nil
+}
@@ -0,0 +1,245 @@
+package order
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Staspol216/gh1/internal/domain/order"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCachedOrder() *cachedOrder {
+	received := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	delivered := received.Add(time.Hour)
+	cellID := int64(42)
+	itemID := int64(5)
+
+	return &cachedOrder{
+		Order: &pvz_domain.Order{
+			ID:             1,
+			PickupPointID:  7,
+			RecipientID:    123,
+			ExpirationDate: received.Add(7 * 24 * time.Hour),
+			DeliveredDate:  &delivered,
+			Status:         pvz_domain.OrderStatusPartiallyDelivered,
+			CellID:         &cellID,
+			History: []pvz_domain.OrderRecord{
+				{PickupPointID: 7, Timestamp: received, Status: pvz_domain.OrderStatusReceived, Description: "order received from courier"},
+				{PickupPointID: 7, ItemID: &itemID, Timestamp: delivered, Status: pvz_domain.OrderStatusDelivered, Description: "order item delivered to recipient"},
+			},
+			Weight: 1.5,
+			Worth:  pvz_domain.NewMoney(12050, pvz_domain.DefaultCurrency),
+			Items: []*pvz_domain.OrderItem{
+				{ID: itemID, SKU: "A-1", Quantity: 2, Price: pvz_domain.NewMoney(6025, pvz_domain.DefaultCurrency), Weight: 0.75, Status: pvz_domain.OrderStatusDelivered},
+			},
+			Version: 3,
+		},
+		ExpiresAt: received.Add(time.Minute),
+		LoadTime:  15 * time.Millisecond,
+	}
+}
+
+// assertCachedOrderEqual compares ExpiresAt as an instant: the protobuf envelope
+// stores it as Unix nanoseconds and reads it back in the local zone.
+func assertCachedOrderEqual(t *testing.T, expected *cachedOrder, actual *cachedOrder) {
+	t.Helper()
+
+	require.NotNil(t, actual)
+	assert.True(t, expected.ExpiresAt.Equal(actual.ExpiresAt), "expires at %s, not %s", actual.ExpiresAt, expected.ExpiresAt)
+	assert.Equal(t, expected.Order, actual.Order)
+	assert.Equal(t, expected.LoadTime, actual.LoadTime)
+}
+
+func TestCacheCodec_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	encodings := []string{CacheEncodingJSON, CacheEncodingProtobuf}
+	compressions := map[string]byte{
+		CacheCompressionNone:   compressionNone,
+		CacheCompressionZstd:   compressionZstd,
+		CacheCompressionSnappy: compressionSnappy,
+	}
+
+	for _, encoding := range encodings {
+		for compression, compressionByte := range compressions {
+			t.Run(fmt.Sprintf("%s/%s", encoding, compression), func(t *testing.T) {
+				t.Parallel()
+				// arrange
+				codec, err := NewCacheCodec(encoding, compression, 0)
+				require.NoError(t, err)
+				cached := newTestCachedOrder()
+				page := []*pvz_domain.Order{cached.Order, cached.Order}
+
+				// act
+				orderBytes, orderErr := codec.marshalOrder(cached)
+				pageBytes, pageErr := codec.marshalPage(page)
+
+				// assert
+				require.NoError(t, orderErr)
+				require.NoError(t, pageErr)
+				assert.Equal(t, compressionByte, pageBytes[2], "a page of repeated orders is compressible")
+
+				decoded, err := codec.unmarshalOrder(orderBytes)
+				require.NoError(t, err)
+				assertCachedOrderEqual(t, cached, decoded)
+
+				decodedPage, err := codec.unmarshalPage(pageBytes)
+				require.NoError(t, err)
+				assert.Equal(t, page, decodedPage)
+			})
+		}
+	}
+}
+
+func TestCacheCodec_ReadsEntriesOfAnotherConfiguration(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	writer, err := NewCacheCodec(CacheEncodingProtobuf, CacheCompressionZstd, 0)
+	require.NoError(t, err)
+	reader, err := NewCacheCodec(CacheEncodingJSON, CacheCompressionSnappy, 0)
+	require.NoError(t, err)
+	cached := newTestCachedOrder()
+
+	// act
+	b, err := writer.marshalOrder(cached)
+	require.NoError(t, err)
+	decoded, err := reader.unmarshalOrder(b)
+
+	// assert
+	require.NoError(t, err)
+	assertCachedOrderEqual(t, cached, decoded)
+}
+
+func TestCacheCodec_Seal(t *testing.T) {
+	t.Parallel()
+
+	const threshold = 64
+
+	incompressible := make([]byte, 4*threshold)
+	_, err := rand.Read(incompressible)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name            string
+		compression     string
+		payload         []byte
+		wantCompression byte
+	}{
+		{
+			name:            "zstd leaves payload below threshold as is",
+			compression:     CacheCompressionZstd,
+			payload:         bytes.Repeat([]byte("a"), threshold-1),
+			wantCompression: compressionNone,
+		},
+		{
+			name:            "zstd compresses payload at threshold",
+			compression:     CacheCompressionZstd,
+			payload:         bytes.Repeat([]byte("a"), threshold),
+			wantCompression: compressionZstd,
+		},
+		{
+			name:            "snappy leaves payload below threshold as is",
+			compression:     CacheCompressionSnappy,
+			payload:         bytes.Repeat([]byte("a"), threshold-1),
+			wantCompression: compressionNone,
+		},
+		{
+			name:            "snappy compresses payload above threshold",
+			compression:     CacheCompressionSnappy,
+			payload:         bytes.Repeat([]byte("a"), threshold+1),
+			wantCompression: compressionSnappy,
+		},
+		{
+			name:            "zstd stores payload that grows when compressed as is",
+			compression:     CacheCompressionZstd,
+			payload:         incompressible,
+			wantCompression: compressionNone,
+		},
+		{
+			name:            "snappy stores payload that grows when compressed as is",
+			compression:     CacheCompressionSnappy,
+			payload:         incompressible,
+			wantCompression: compressionNone,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			// arrange
+			codec, err := NewCacheCodec(CacheEncodingJSON, tt.compression, threshold)
+			require.NoError(t, err)
+
+			// act
+			sealed := codec.seal(tt.payload)
+
+			// assert
+			require.GreaterOrEqual(t, len(sealed), cacheHeaderSize)
+			assert.Equal(t, []byte{cacheFormatVersion, encodingJSON, tt.wantCompression}, sealed[:cacheHeaderSize])
+			if tt.wantCompression == compressionNone {
+				assert.Equal(t, tt.payload, sealed[cacheHeaderSize:])
+			} else {
+				assert.Less(t, len(sealed), cacheHeaderSize+len(tt.payload))
+			}
+
+			_, payload, err := codec.open(sealed)
+			require.NoError(t, err)
+			assert.Equal(t, tt.payload, payload)
+		})
+	}
+}
+
+func TestCacheCodec_OpenOutdated(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		entry []byte
+	}{
+		{
+			name:  "bare JSON entry written before versioning",
+			entry: []byte(`{"order":{"id":1,"status":"received"},"expires_at":"2026-10-18T12:00:00Z"}`),
+		},
+		{
+			name:  "previous format version",
+			entry: []byte{cacheFormatVersion - 1, encodingJSON, compressionNone, '{', '}'},
+		},
+		{
+			name:  "next format version",
+			entry: []byte{cacheFormatVersion + 1, encodingJSON, compressionNone, '{', '}'},
+		},
+		{
+			name:  "unknown encoding",
+			entry: []byte{cacheFormatVersion, 0xff, compressionNone, '{', '}'},
+		},
+		{
+			name:  "unknown compression",
+			entry: []byte{cacheFormatVersion, encodingJSON, 0xff, '{', '}'},
+		},
+		{
+			name:  "truncated header",
+			entry: []byte{cacheFormatVersion, encodingJSON},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			// arrange
+			codec, err := NewCacheCodec(CacheEncodingJSON, CacheCompressionNone, 0)
+			require.NoError(t, err)
+
+			// act
+			cached, err := codec.unmarshalOrder(tt.entry)
+
+			// assert
+			require.ErrorIs(t, err, errCacheEntryOutdated)
+			assert.Nil(t, cached)
+		})
+	}
+}
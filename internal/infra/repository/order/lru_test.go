@@ -0,0 +1,106 @@
+package order
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRU(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	t.Run("evicts the least recently used entry", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		cache := newLRU[string, int](2, time.Minute)
+		require.False(t, cache.Add("a", 1, now))
+		require.False(t, cache.Add("b", 2, now))
+		_, ok := cache.Get("a", now)
+		require.True(t, ok)
+
+		// act
+		evicted := cache.Add("c", 3, now)
+
+		// assert
+		assert.True(t, evicted)
+		assert.Equal(t, 2, cache.Len())
+		_, ok = cache.Get("b", now)
+		assert.False(t, ok, "b was used least recently")
+		value, ok := cache.Get("a", now)
+		assert.True(t, ok)
+		assert.Equal(t, 1, value)
+	})
+
+	t.Run("replaces an entry without evicting", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		cache := newLRU[string, int](2, time.Minute)
+		cache.Add("a", 1, now)
+		cache.Add("b", 2, now)
+
+		// act
+		evicted := cache.Add("a", 10, now)
+
+		// assert
+		assert.False(t, evicted)
+		value, _ := cache.Get("a", now)
+		assert.Equal(t, 10, value)
+		assert.Equal(t, 2, cache.Len())
+	})
+
+	t.Run("expires entries after ttl", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		cache := newLRU[string, int](2, time.Minute)
+		cache.Add("a", 1, now)
+
+		// act
+		_, beforeTTL := cache.Get("a", now.Add(time.Minute-time.Nanosecond))
+		_, atTTL := cache.Get("a", now.Add(time.Minute))
+
+		// assert
+		assert.True(t, beforeTTL)
+		assert.False(t, atTTL)
+		assert.Equal(t, 0, cache.Len(), "an expired entry is dropped when found")
+	})
+
+	t.Run("replacing an entry restarts its ttl", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		cache := newLRU[string, int](2, time.Minute)
+		cache.Add("a", 1, now)
+		cache.Add("a", 2, now.Add(30*time.Second))
+
+		// act
+		value, ok := cache.Get("a", now.Add(time.Minute))
+
+		// assert
+		assert.True(t, ok)
+		assert.Equal(t, 2, value)
+	})
+
+	t.Run("removes and purges entries", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		cache := newLRU[string, int](3, time.Minute)
+		cache.Add("a", 1, now)
+		cache.Add("b", 2, now)
+		cache.Add("c", 3, now)
+
+		// act
+		cache.Remove("a")
+		afterRemove := cache.Len()
+		cache.Purge()
+
+		// assert
+		assert.Equal(t, 2, afterRemove)
+		assert.Equal(t, 0, cache.Len())
+		_, ok := cache.Get("b", now)
+		assert.False(t, ok)
+		assert.False(t, cache.Add("d", 4, now), "a purged cache has room again")
+	})
+}
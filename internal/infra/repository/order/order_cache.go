@@ -2,59 +2,389 @@ package order
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/Staspol216/gh1/internal/domain/order"
 	"github.com/Staspol216/gh1/internal/ports"
+	"github.com/Staspol216/gh1/pkg/logger"
+	"github.com/Staspol216/gh1/pkg/monitoring"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
 )
 
+// maxSkippedInvalidations bounds the orders remembered while their invalidation
+// cannot reach the backend. Orders beyond it stay cached until their TTL.
+const maxSkippedInvalidations = 100_000
+
+// earlyRefreshBeta weighs probabilistic early refresh: above 1 favours refreshing
+// earlier, below 1 later.
+const earlyRefreshBeta = 1.0
+
 type Cache struct {
-	db pvz_ports.Cache
+	db          pvz_ports.Cache
+	codec       *CacheCodec
+	ttl         time.Duration
+	pageTTL     time.Duration
+	negativeTTL time.Duration
+
+	skipped skippedInvalidations
 }
 
-func NewOrderCache(db pvz_ports.Cache) *Cache {
+// NewOrderCache creates a cache whose entries expire after ttl, so that a missed
+// invalidation can only serve a stale order for a bounded time. Cached list pages
+// expire after pageTTL, remembered lookups of missing orders after negativeTTL.
+// Values are stored in the format of codec.
+func NewOrderCache(db pvz_ports.Cache, codec *CacheCodec, ttl time.Duration, pageTTL time.Duration, negativeTTL time.Duration) *Cache {
 	return &Cache{
-		db: db,
+		db:          db,
+		codec:       codec,
+		ttl:         ttl,
+		pageTTL:     pageTTL,
+		negativeTTL: negativeTTL,
 	}
 }
 
+// cachedOrder is the stored form of an order. ExpiresAt and LoadTime drive
+// probabilistic early refresh: the slower the order is to load, the earlier before
+// expiry a lookup may report a miss, so that one caller reloads it while the others
+// are still served from the cache.
+type cachedOrder struct {
+	Order     *pvz_domain.Order `json:"order"`
+	ExpiresAt time.Time         `json:"expires_at"`
+	LoadTime  time.Duration     `json:"load_time"`
+}
+
+func (c *cachedOrder) refreshDue(now time.Time) bool {
+	gap := -float64(c.LoadTime) * earlyRefreshBeta * math.Log(rand.Float64())
+	return !now.Add(time.Duration(gap)).Before(c.ExpiresAt)
+}
+
 func (c *Cache) Healthcheck(ctx context.Context) error {
 	_, err := c.db.Ping(ctx).Result()
 	return err
 }
 
-func keyForOrder(id interface{}) string {
-	return fmt.Sprintf("order:%v", id)
+func keyForOrder(pickupPointID int64, id int64) string {
+	return fmt.Sprintf("order:%d:%d", pickupPointID, id)
+}
+
+// keyForMissingOrder holds, per recipient, lookups that found no order. It is a
+// hash, so that dropping the order key set drops them all at once.
+func keyForMissingOrder(pickupPointID int64, id int64) string {
+	return fmt.Sprintf("order:%d:%d:missing", pickupPointID, id)
+}
+
+// keyForPagesGeneration holds a counter that is bumped whenever an order of the
+// pickup point changes. Pages are keyed by it, so bumping it orphans all of them.
+func keyForPagesGeneration(pickupPointID int64) string {
+	return fmt.Sprintf("orders:pages:%d:generation", pickupPointID)
+}
+
+func keyForPage(pickupPointID int64, generation int64, pagination *pvz_domain.Pagination) string {
+	return fmt.Sprintf("orders:pages:%d:%d:%d:%d", pickupPointID, generation, pagination.Offset, pagination.Limit)
 }
 
 // GetOrder tries to get an order from redis and unmarshal it.
-// Returns (*order.Order, nil) on hit, (nil, redis.Nil) on miss, or (nil, err) on error.
-func (c *Cache) GetOrder(ctx context.Context, id interface{}) (*pvz_domain.Order, error) {
-	key := keyForOrder(id)
-	b, err := c.db.Get(ctx, key).Bytes()
+// Returns (*order.Order, nil) on hit, (nil, ErrOrderNotFound) if the recipient
+// recently looked the order up in vain, or (nil, redis.Nil) on miss. A hit close to
+// expiry or in an outdated format is reported as a miss, see cachedOrder.
+func (c *Cache) GetOrder(ctx context.Context, pickupPointID int64, id int64, recipientId int64) (*pvz_domain.Order, error) {
+	var orderCmd *redis.StringCmd
+	var missingCmd *redis.BoolCmd
+	_, err := c.db.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		orderCmd = pipe.Get(ctx, keyForOrder(pickupPointID, id))
+		missingCmd = pipe.HExists(ctx, keyForMissingOrder(pickupPointID, id), strconv.FormatInt(recipientId, 10))
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, err
+	}
+
+	b, err := orderCmd.Bytes()
+	if errors.Is(err, redis.Nil) {
+		if missingCmd.Val() {
+			return nil, fmt.Errorf("%w: order %d", pvz_domain.ErrOrderNotFound, id)
+		}
+		return nil, err
+	}
 	if err != nil {
 		return nil, err
 	}
-	var order pvz_domain.Order
-	if err := json.Unmarshal(b, &order); err != nil {
+
+	cached, err := c.codec.unmarshalOrder(b)
+	if errors.Is(err, errCacheEntryOutdated) {
+		monitoring.ObserveCacheOperation("get_order_outdated", nil)
+		return nil, redis.Nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if cached.refreshDue(time.Now()) {
+		monitoring.ObserveCacheOperation("get_order_early_refresh", nil)
+		return nil, redis.Nil
+	}
+	return cached.Order, nil
+}
+
+// GetOrders looks up orders with a single MGET. Missing and unreadable entries are
+// left out of the result, so the caller loads them from storage.
+func (c *Cache) GetOrders(ctx context.Context, pickupPointID int64, ids []int64) (map[int64]*pvz_domain.Order, error) {
+	orders := make(map[int64]*pvz_domain.Order, len(ids))
+	if len(ids) == 0 {
+		return orders, nil
+	}
+
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, keyForOrder(pickupPointID, id))
+	}
+
+	values, err := c.db.MGet(ctx, keys...).Result()
+	if err != nil {
 		return nil, err
 	}
-	return &order, nil
+
+	for _, value := range values {
+		raw, ok := value.(string)
+		if !ok {
+			continue
+		}
+		cached, err := c.codec.unmarshalOrder([]byte(raw))
+		if err != nil || cached.Order == nil {
+			continue
+		}
+		orders[cached.Order.ID] = cached.Order
+	}
+	return orders, nil
 }
 
-// SetOrder stores an order in redis. ttl==0 means no expiration.
-func (c *Cache) SetOrder(ctx context.Context, order *pvz_domain.Order, ttl time.Duration) error {
-	key := keyForOrder(order.ID)
-	b, err := json.Marshal(order)
+// SetOrder stores an order in redis for the cache TTL. loadTime is how long the
+// order took to load from storage.
+func (c *Cache) SetOrder(ctx context.Context, order *pvz_domain.Order, loadTime time.Duration) error {
+	b, err := c.marshalOrder(order, loadTime)
 	if err != nil {
 		return err
 	}
-	return c.db.Set(ctx, key, b, ttl).Err()
+	return c.db.Set(ctx, keyForOrder(order.PickupPointID, order.ID), b, c.ttl).Err()
+}
+
+// SetOrders stores orders in one pipeline for the cache TTL.
+func (c *Cache) SetOrders(ctx context.Context, orders []*pvz_domain.Order, loadTime time.Duration) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	_, err := c.db.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, order := range orders {
+			b, err := c.marshalOrder(order, loadTime)
+			if err != nil {
+				return err
+			}
+			pipe.Set(ctx, keyForOrder(order.PickupPointID, order.ID), b, c.ttl)
+		}
+		return nil
+	})
+	return err
+}
+
+func (c *Cache) marshalOrder(order *pvz_domain.Order, loadTime time.Duration) ([]byte, error) {
+	return c.codec.marshalOrder(&cachedOrder{
+		Order:     order,
+		ExpiresAt: time.Now().Add(c.ttl),
+		LoadTime:  loadTime,
+	})
+}
+
+// SetOrderMissing remembers for the negative TTL that the recipient has no such order.
+func (c *Cache) SetOrderMissing(ctx context.Context, pickupPointID int64, id int64, recipientId int64) error {
+	key := keyForMissingOrder(pickupPointID, id)
+	_, err := c.db.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, strconv.FormatInt(recipientId, 10), 1)
+		pipe.Expire(ctx, key, c.negativeTTL)
+		return nil
+	})
+	return err
+}
+
+// DeleteOrder drops the cached order, lookups that missed it and every cached page
+// of its pickup point.
+func (c *Cache) DeleteOrder(ctx context.Context, pickupPointID int64, orderId int64) error {
+	_, err := c.db.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, keyForOrder(pickupPointID, orderId), keyForMissingOrder(pickupPointID, orderId))
+		pipe.Incr(ctx, keyForPagesGeneration(pickupPointID))
+		return nil
+	})
+	if err != nil {
+		c.skipped.addOrder(pickupPointID, orderId)
+	}
+	return err
+}
+
+// ForgetMissingOrders drops remembered lookups that missed the given orders, which
+// were looked up before they were added.
+func (c *Cache) ForgetMissingOrders(ctx context.Context, pickupPointID int64, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, keyForMissingOrder(pickupPointID, id))
+	}
+
+	err := c.db.Del(ctx, keys...).Err()
+	if err != nil {
+		for _, id := range ids {
+			c.skipped.addOrder(pickupPointID, id)
+		}
+	}
+	return err
 }
 
-func (c *Cache) DeleteOrder(ctx context.Context, orderId int64) error {
-	_, err := c.db.Del(ctx, keyForOrder(orderId)).Result()
+// InvalidateOrderPages drops every cached page of the pickup point.
+func (c *Cache) InvalidateOrderPages(ctx context.Context, pickupPointID int64) error {
+	err := c.db.Incr(ctx, keyForPagesGeneration(pickupPointID)).Err()
+	if err != nil {
+		c.skipped.addPickupPoint(pickupPointID)
+	}
 	return err
 }
+
+// ReplaySkippedInvalidations applies on backend the invalidations that failed, e.g.
+// while the circuit breaker was open, so that orders changed meanwhile are not
+// served stale once the cache is used again. CacheBreaker runs it before closing.
+func (c *Cache) ReplaySkippedInvalidations(ctx context.Context, backend pvz_ports.Cache) error {
+	orders, pickupPoints, dropped := c.skipped.snapshot()
+	if len(orders) == 0 && len(pickupPoints) == 0 {
+		return nil
+	}
+
+	_, err := backend.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range orders {
+			pipe.Del(ctx, keyForOrder(key.pickupPointID, key.id), keyForMissingOrder(key.pickupPointID, key.id))
+		}
+		for _, pickupPointID := range pickupPoints {
+			pipe.Incr(ctx, keyForPagesGeneration(pickupPointID))
+		}
+		return nil
+	})
+	monitoring.ObserveCacheOperation("replay_invalidations", err)
+	if err != nil {
+		return fmt.Errorf("replay skipped cache invalidations: %w", err)
+	}
+
+	c.skipped.forget(orders, pickupPoints, dropped)
+	if dropped > 0 {
+		app_logger.MyLogger.Warn("cache invalidations dropped while the cache was down, those orders stay cached until their TTL",
+			zap.Int64("dropped", dropped),
+		)
+	}
+	return nil
+}
+
+// GetOrderPage returns a cached page of orders and the pages generation it was
+// looked up in. On a miss the error is redis.Nil and the generation must be passed
+// to SetOrderPage, so that a page loaded while orders were changing is never served.
+func (c *Cache) GetOrderPage(ctx context.Context, pickupPointID int64, pagination *pvz_domain.Pagination) ([]*pvz_domain.Order, int64, error) {
+	generation, err := c.db.Get(ctx, keyForPagesGeneration(pickupPointID)).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return nil, 0, err
+	}
+
+	b, err := c.db.Get(ctx, keyForPage(pickupPointID, generation, pagination)).Bytes()
+	if err != nil {
+		return nil, generation, err
+	}
+	orders, err := c.codec.unmarshalPage(b)
+	if errors.Is(err, errCacheEntryOutdated) {
+		monitoring.ObserveCacheOperation("get_page_outdated", nil)
+		return nil, generation, redis.Nil
+	}
+	if err != nil {
+		return nil, generation, err
+	}
+	return orders, generation, nil
+}
+
+// SetOrderPage stores a page of orders in the given pages generation for the page TTL.
+func (c *Cache) SetOrderPage(ctx context.Context, pickupPointID int64, generation int64, pagination *pvz_domain.Pagination, orders []*pvz_domain.Order) error {
+	b, err := c.codec.marshalPage(orders)
+	if err != nil {
+		return err
+	}
+	return c.db.Set(ctx, keyForPage(pickupPointID, generation, pagination), b, c.pageTTL).Err()
+}
+
+// skippedInvalidations remembers invalidations the backend did not take until they
+// are replayed. Pages are invalidated per pickup point, so those are remembered
+// without limit.
+type skippedInvalidations struct {
+	mu           sync.Mutex
+	orders       map[orderKey]struct{}
+	pickupPoints map[int64]struct{}
+	dropped      int64
+}
+
+func (s *skippedInvalidations) addOrder(pickupPointID int64, id int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.addPickupPointLocked(pickupPointID)
+	if len(s.orders) >= maxSkippedInvalidations {
+		s.dropped++
+		return
+	}
+	if s.orders == nil {
+		s.orders = make(map[orderKey]struct{})
+	}
+	s.orders[orderKey{pickupPointID: pickupPointID, id: id}] = struct{}{}
+}
+
+func (s *skippedInvalidations) addPickupPoint(pickupPointID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.addPickupPointLocked(pickupPointID)
+}
+
+func (s *skippedInvalidations) addPickupPointLocked(pickupPointID int64) {
+	if s.pickupPoints == nil {
+		s.pickupPoints = make(map[int64]struct{})
+	}
+	s.pickupPoints[pickupPointID] = struct{}{}
+}
+
+func (s *skippedInvalidations) snapshot() ([]orderKey, []int64, int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	orders := make([]orderKey, 0, len(s.orders))
+	for key := range s.orders {
+		orders = append(orders, key)
+	}
+	pickupPoints := make([]int64, 0, len(s.pickupPoints))
+	for pickupPointID := range s.pickupPoints {
+		pickupPoints = append(pickupPoints, pickupPointID)
+	}
+	return orders, pickupPoints, s.dropped
+}
+
+// forget drops replayed invalidations. Ones skipped during the replay are kept.
+func (s *skippedInvalidations) forget(orders []orderKey, pickupPoints []int64, dropped int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range orders {
+		delete(s.orders, key)
+	}
+	for _, pickupPointID := range pickupPoints {
+		delete(s.pickupPoints, pickupPointID)
+	}
+	s.dropped -= dropped
+}
@@ -0,0 +1,194 @@
+package order
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Staspol216/gh1/internal/domain/order"
+	"github.com/Staspol216/gh1/pkg/logger"
+	"github.com/Staspol216/gh1/pkg/monitoring"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// orderInvalidationsChannel carries "<instance>:<pickup point>:<order id>" whenever
+// an instance writes or drops a cached order.
+const orderInvalidationsChannel = "orders:invalidations"
+
+type orderKey struct {
+	pickupPointID int64
+	id            int64
+}
+
+// L1Cache keeps recently used orders in process memory in front of Cache. Instances
+// tell each other about changed orders over Redis pub/sub. Invalidations published
+// while the subscription is down are lost, so the memory is dropped whenever it
+// reconnects. Orders are shared between callers and must not be modified.
+type L1Cache struct {
+	*Cache
+	orders     *lru[orderKey, *pvz_domain.Order]
+	instanceID string
+}
+
+func NewL1Cache(next *Cache, size int, ttl time.Duration) (*L1Cache, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	return &L1Cache{
+		Cache:      next,
+		orders:     newLRU[orderKey, *pvz_domain.Order](size, ttl),
+		instanceID: hex.EncodeToString(id),
+	}, nil
+}
+
+// Run applies invalidations published by other instances until ctx is done.
+func (c *L1Cache) Run(ctx context.Context) {
+	subscription := c.db.Subscribe(ctx, orderInvalidationsChannel)
+	defer subscription.Close()
+
+	messages := subscription.ChannelWithSubscriptions()
+	for {
+		select {
+		case <-ctx.Done():
+			app_logger.MyLogger.Info("order l1 cache invalidations finished by context done")
+			return
+		case message, ok := <-messages:
+			if !ok {
+				return
+			}
+			c.handleMessage(message)
+		}
+	}
+}
+
+// handleMessage applies an invalidation, or drops every order when the subscription
+// is (re)established, as invalidations may have been missed while it was down.
+func (c *L1Cache) handleMessage(message interface{}) {
+	switch message := message.(type) {
+	case *redis.Subscription:
+		c.orders.Purge()
+		monitoring.ObserveCacheOperation("l1_purge", nil)
+		monitoring.SetL1CacheEntries(c.orders.Len())
+	case *redis.Message:
+		c.applyInvalidation(message.Payload)
+	}
+}
+
+func (c *L1Cache) applyInvalidation(payload string) {
+	parts := strings.Split(payload, ":")
+	if len(parts) != 3 {
+		app_logger.MyLogger.Warn("malformed order cache invalidation", zap.String("payload", payload))
+		return
+	}
+	if parts[0] == c.instanceID {
+		return
+	}
+
+	pickupPointID, ppErr := strconv.ParseInt(parts[1], 10, 64)
+	id, idErr := strconv.ParseInt(parts[2], 10, 64)
+	if err := errors.Join(ppErr, idErr); err != nil {
+		app_logger.MyLogger.Warn("malformed order cache invalidation", zap.String("payload", payload), zap.Error(err))
+		return
+	}
+
+	c.orders.Remove(orderKey{pickupPointID: pickupPointID, id: id})
+	monitoring.ObserveCacheOperation("l1_invalidation", nil)
+	monitoring.SetL1CacheEntries(c.orders.Len())
+}
+
+func (c *L1Cache) GetOrder(ctx context.Context, pickupPointID int64, id int64, recipientId int64) (*pvz_domain.Order, error) {
+	if order, ok := c.orders.Get(orderKey{pickupPointID: pickupPointID, id: id}, time.Now()); ok {
+		monitoring.ObserveCacheOperation("l1_get_order_hit", nil)
+		return order, nil
+	}
+	monitoring.ObserveCacheOperation("l1_get_order_miss", nil)
+
+	order, err := c.Cache.GetOrder(ctx, pickupPointID, id, recipientId)
+	if err != nil {
+		return nil, err
+	}
+	c.remember(order)
+	return order, nil
+}
+
+func (c *L1Cache) GetOrders(ctx context.Context, pickupPointID int64, ids []int64) (map[int64]*pvz_domain.Order, error) {
+	now := time.Now()
+	orders := make(map[int64]*pvz_domain.Order, len(ids))
+	missing := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if order, ok := c.orders.Get(orderKey{pickupPointID: pickupPointID, id: id}, now); ok {
+			orders[id] = order
+			monitoring.ObserveCacheOperation("l1_get_order_hit", nil)
+			continue
+		}
+		missing = append(missing, id)
+		monitoring.ObserveCacheOperation("l1_get_order_miss", nil)
+	}
+	if len(missing) == 0 {
+		return orders, nil
+	}
+
+	loaded, err := c.Cache.GetOrders(ctx, pickupPointID, missing)
+	if err != nil {
+		return nil, err
+	}
+	for id, order := range loaded {
+		orders[id] = order
+		c.remember(order)
+	}
+	return orders, nil
+}
+
+func (c *L1Cache) SetOrder(ctx context.Context, order *pvz_domain.Order, loadTime time.Duration) error {
+	if err := c.Cache.SetOrder(ctx, order, loadTime); err != nil {
+		return err
+	}
+	c.remember(order)
+	return c.publish(ctx, order)
+}
+
+func (c *L1Cache) SetOrders(ctx context.Context, orders []*pvz_domain.Order, loadTime time.Duration) error {
+	if err := c.Cache.SetOrders(ctx, orders, loadTime); err != nil {
+		return err
+	}
+	for _, order := range orders {
+		c.remember(order)
+	}
+	return c.publish(ctx, orders...)
+}
+
+func (c *L1Cache) DeleteOrder(ctx context.Context, pickupPointID int64, orderId int64) error {
+	c.orders.Remove(orderKey{pickupPointID: pickupPointID, id: orderId})
+	monitoring.SetL1CacheEntries(c.orders.Len())
+
+	deleteErr := c.Cache.DeleteOrder(ctx, pickupPointID, orderId)
+	publishErr := c.publish(ctx, &pvz_domain.Order{ID: orderId, PickupPointID: pickupPointID})
+	return errors.Join(deleteErr, publishErr)
+}
+
+func (c *L1Cache) remember(order *pvz_domain.Order) {
+	if evicted := c.orders.Add(orderKey{pickupPointID: order.PickupPointID, id: order.ID}, order, time.Now()); evicted {
+		monitoring.ObserveCacheOperation("l1_evict", nil)
+	}
+	monitoring.SetL1CacheEntries(c.orders.Len())
+}
+
+// publish tells other instances to drop their copies of orders.
+func (c *L1Cache) publish(ctx context.Context, orders ...*pvz_domain.Order) error {
+	_, err := c.db.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, order := range orders {
+			pipe.Publish(ctx, orderInvalidationsChannel, fmt.Sprintf("%s:%d:%d", c.instanceID, order.PickupPointID, order.ID))
+		}
+		return nil
+	})
+	monitoring.ObserveCacheOperation("l1_publish_invalidation", err)
+	return err
+}
@@ -0,0 +1,120 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Staspol216/gh1/internal/domain/order"
+	"github.com/Staspol216/gh1/internal/ports/mocks"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func newTestL1Cache(t *testing.T) (*L1Cache, *mocks.MockCache) {
+	t.Helper()
+
+	backend := mocks.NewMockCache(gomock.NewController(t))
+	l1, err := NewL1Cache(NewOrderCache(backend, nil, time.Minute, time.Minute, time.Minute), 10, time.Minute)
+	require.NoError(t, err)
+	return l1, backend
+}
+
+func TestL1Cache_ServesRememberedOrders(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	l1, _ := newTestL1Cache(t)
+	order := &pvz_domain.Order{ID: 1, PickupPointID: 7}
+	l1.remember(order)
+
+	// act
+	cached, err := l1.GetOrder(context.Background(), 7, 1, 123)
+
+	// assert
+	require.NoError(t, err)
+	assert.Same(t, order, cached, "a remembered order is served without reaching redis")
+}
+
+func TestL1Cache_HandleMessage(t *testing.T) {
+	t.Parallel()
+
+	t.Run("drops an order invalidated by another instance", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		l1, _ := newTestL1Cache(t)
+		l1.remember(&pvz_domain.Order{ID: 1, PickupPointID: 7})
+		l1.remember(&pvz_domain.Order{ID: 2, PickupPointID: 7})
+
+		// act
+		l1.handleMessage(&redis.Message{Channel: orderInvalidationsChannel, Payload: "other:7:1"})
+
+		// assert
+		_, dropped := l1.orders.Get(orderKey{pickupPointID: 7, id: 1}, time.Now())
+		_, kept := l1.orders.Get(orderKey{pickupPointID: 7, id: 2}, time.Now())
+		assert.False(t, dropped)
+		assert.True(t, kept)
+	})
+
+	t.Run("ignores its own invalidations", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		l1, _ := newTestL1Cache(t)
+		l1.remember(&pvz_domain.Order{ID: 1, PickupPointID: 7})
+
+		// act
+		l1.handleMessage(&redis.Message{Channel: orderInvalidationsChannel, Payload: fmt.Sprintf("%s:7:1", l1.instanceID)})
+
+		// assert
+		assert.Equal(t, 1, l1.orders.Len())
+	})
+
+	t.Run("ignores malformed invalidations", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		l1, _ := newTestL1Cache(t)
+		l1.remember(&pvz_domain.Order{ID: 1, PickupPointID: 7})
+
+		// act
+		l1.handleMessage(&redis.Message{Channel: orderInvalidationsChannel, Payload: "other:7"})
+		l1.handleMessage(&redis.Message{Channel: orderInvalidationsChannel, Payload: "other:seven:1"})
+
+		// assert
+		assert.Equal(t, 1, l1.orders.Len())
+	})
+
+	t.Run("drops every order when the subscription reconnects", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		l1, _ := newTestL1Cache(t)
+		l1.remember(&pvz_domain.Order{ID: 1, PickupPointID: 7})
+		l1.remember(&pvz_domain.Order{ID: 2, PickupPointID: 8})
+
+		// act
+		l1.handleMessage(&redis.Subscription{Kind: "subscribe", Channel: orderInvalidationsChannel, Count: 1})
+
+		// assert
+		assert.Equal(t, 0, l1.orders.Len())
+	})
+}
+
+func TestL1Cache_DeleteOrder(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	l1, backend := newTestL1Cache(t)
+	l1.remember(&pvz_domain.Order{ID: 1, PickupPointID: 7})
+	published := &pipelinedCommands{}
+	backend.EXPECT().Pipelined(gomock.Any(), gomock.Any()).DoAndReturn(published.run).Times(2)
+
+	// act
+	err := l1.DeleteOrder(context.Background(), 7, 1)
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, 0, l1.orders.Len())
+	assert.Contains(t, published.sorted(), fmt.Sprintf("publish %s %s:7:1", orderInvalidationsChannel, l1.instanceID))
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/Staspol216/gh1/internal/domain/order"
 	"github.com/Staspol216/gh1/internal/ports"
 	"github.com/Staspol216/gh1/pkg/logger"
+	"github.com/jackc/pgx/v4"
 	"go.uber.org/zap"
 )
 
@@ -25,41 +26,222 @@ func NewOrderRepo(database pvz_ports.DB) (*OrderRepo, error) {
 
 func (r *OrderRepo) Add(ctx context.Context, order *pvz_domain.Order) (int64, error) {
 	query := `INSERT INTO orders (
+		pickup_point_id,
 		recipient_id,
 		expiration_date,
 		status,
+		cell_id,
 		weight,
-		worth
-	) VALUES ($1, $2, $3, $4, $5) RETURNING id;`
+		worth,
+		currency
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, version;`
 
 	row := r.db.ExecQueryRow(ctx, query,
+		order.PickupPointID,
 		order.RecipientID,
 		order.ExpirationDate,
 		order.Status,
+		order.CellID,
 		order.Weight,
-		order.Worth,
+		order.Worth.Decimal(),
+		order.Worth.Currency,
 	)
 
 	var id int64
-	err := row.Scan(&id)
+	err := row.Scan(&id, &order.Version)
 	if err != nil {
 		app_logger.MyLogger.Error("add order", zap.Error(err))
+		return id, err
 	}
-	return id, err
+
+	for _, item := range order.Items {
+		err := r.db.ExecQueryRow(ctx, `INSERT INTO order_items (
+			order_id,
+			sku,
+			quantity,
+			price,
+			currency,
+			weight,
+			status
+		) VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id;`,
+			id,
+			item.SKU,
+			item.Quantity,
+			item.Price.Decimal(),
+			item.Price.Currency,
+			item.Weight,
+			item.Status,
+		).Scan(&item.ID)
+		if err != nil {
+			app_logger.MyLogger.Error("add order item", zap.Error(err))
+			return id, err
+		}
+	}
+
+	return id, nil
+}
+
+// AddBatch inserts new orders with their items using one statement per table and
+// sets the ids and versions they were stored with. Ids are reserved upfront, so that
+// each row is matched to its order regardless of the order rows are inserted in.
+func (r *OrderRepo) AddBatch(ctx context.Context, orders []*pvz_domain.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	ids, err := r.reserveIDs(ctx, "orders_id_seq", len(orders))
+	if err != nil {
+		app_logger.MyLogger.Error("reserve order ids", zap.Error(err))
+		return err
+	}
+
+	pickupPointIDs := make([]int64, 0, len(orders))
+	recipientIDs := make([]int64, 0, len(orders))
+	expirationDates := make([]time.Time, 0, len(orders))
+	statuses := make([]string, 0, len(orders))
+	cellIDs := make([]*int64, 0, len(orders))
+	weights := make([]float64, 0, len(orders))
+	worths := make([]string, 0, len(orders))
+	currencies := make([]string, 0, len(orders))
+	for _, order := range orders {
+		pickupPointIDs = append(pickupPointIDs, order.PickupPointID)
+		recipientIDs = append(recipientIDs, order.RecipientID)
+		expirationDates = append(expirationDates, order.ExpirationDate)
+		statuses = append(statuses, string(order.Status))
+		cellIDs = append(cellIDs, order.CellID)
+		weights = append(weights, order.Weight)
+		worths = append(worths, order.Worth.Decimal())
+		currencies = append(currencies, order.Worth.Currency)
+	}
+
+	var insertedIDs, versions []int64
+	err = r.db.ExecQueryRow(ctx, `
+		WITH inserted AS (
+			INSERT INTO orders (
+				id,
+				pickup_point_id,
+				recipient_id,
+				expiration_date,
+				status,
+				cell_id,
+				weight,
+				worth,
+				currency
+			)
+			SELECT u.id, u.pickup_point_id, u.recipient_id, u.expiration_date, u.status::order_status,
+				u.cell_id, u.weight, u.worth::numeric, u.currency
+			FROM unnest($1::bigint[], $2::bigint[], $3::bigint[], $4::timestamp[], $5::text[],
+				$6::bigint[], $7::float8[], $8::text[], $9::text[])
+				AS u(id, pickup_point_id, recipient_id, expiration_date, status, cell_id, weight, worth, currency)
+			RETURNING id, version
+		)
+		SELECT array_agg(id), array_agg(version) FROM inserted;`,
+		ids, pickupPointIDs, recipientIDs, expirationDates, statuses, cellIDs, weights, worths, currencies,
+	).Scan(&insertedIDs, &versions)
+	if err != nil {
+		app_logger.MyLogger.Error("add orders", zap.Error(err))
+		return err
+	}
+
+	versionByID := make(map[int64]int64, len(insertedIDs))
+	for i, id := range insertedIDs {
+		versionByID[id] = versions[i]
+	}
+	for i, order := range orders {
+		order.ID = ids[i]
+		order.Version = versionByID[order.ID]
+	}
+
+	return r.addItemsBatch(ctx, orders)
+}
+
+func (r *OrderRepo) addItemsBatch(ctx context.Context, orders []*pvz_domain.Order) error {
+	var items []*pvz_domain.OrderItem
+	var orderIDs []int64
+	for _, order := range orders {
+		for _, item := range order.Items {
+			items = append(items, item)
+			orderIDs = append(orderIDs, order.ID)
+		}
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	ids, err := r.reserveIDs(ctx, "order_items_id_seq", len(items))
+	if err != nil {
+		app_logger.MyLogger.Error("reserve order item ids", zap.Error(err))
+		return err
+	}
+
+	skus := make([]string, 0, len(items))
+	quantities := make([]int64, 0, len(items))
+	prices := make([]string, 0, len(items))
+	currencies := make([]string, 0, len(items))
+	weights := make([]float64, 0, len(items))
+	statuses := make([]string, 0, len(items))
+	for _, item := range items {
+		skus = append(skus, item.SKU)
+		quantities = append(quantities, item.Quantity)
+		prices = append(prices, item.Price.Decimal())
+		currencies = append(currencies, item.Price.Currency)
+		weights = append(weights, item.Weight)
+		statuses = append(statuses, string(item.Status))
+	}
+
+	_, err = r.db.Exec(ctx, `
+		INSERT INTO order_items (
+			id,
+			order_id,
+			sku,
+			quantity,
+			price,
+			currency,
+			weight,
+			status
+		)
+		SELECT u.id, u.order_id, u.sku, u.quantity, u.price::numeric, u.currency, u.weight, u.status::order_status
+		FROM unnest($1::bigint[], $2::bigint[], $3::text[], $4::bigint[], $5::text[], $6::text[], $7::float8[], $8::text[])
+			AS u(id, order_id, sku, quantity, price, currency, weight, status);`,
+		ids, orderIDs, skus, quantities, prices, currencies, weights, statuses,
+	)
+	if err != nil {
+		app_logger.MyLogger.Error("add order items", zap.Error(err))
+		return err
+	}
+
+	for i, item := range items {
+		item.ID = ids[i]
+	}
+	return nil
+}
+
+// reserveIDs takes count values of the sequence. It always runs on the primary.
+func (r *OrderRepo) reserveIDs(ctx context.Context, sequence string, count int) ([]int64, error) {
+	var ids []int64
+	err := r.db.ExecQueryRow(ctx,
+		`SELECT array_agg(nextval($1::regclass)) FROM generate_series(1, $2);`,
+		sequence, count,
+	).Scan(&ids)
+	return ids, err
 }
 
 func (r *OrderRepo) AddHistoryRecord(ctx context.Context, record *pvz_domain.OrderRecord, orderId int64) (int64, error) {
 	query := `INSERT INTO order_records (
+		pickup_point_id,
 		order_id,
+		item_id,
 		description,
 		timestamp,
 		status
-	) VALUES ($1, $2, $3, $4) 
+	) VALUES ($1, $2, $3, $4, $5, $6) 
 	RETURNING id;
 	`
 
 	row := r.db.ExecQueryRow(ctx, query,
+		record.PickupPointID,
 		orderId,
+		record.ItemID,
 		record.Description,
 		record.Timestamp,
 		record.Status,
@@ -73,8 +255,47 @@ func (r *OrderRepo) AddHistoryRecord(ctx context.Context, record *pvz_domain.Ord
 	return id, err
 }
 
-func (r *OrderRepo) Delete(ctx context.Context, orderId int64) error {
-	commandTag, err := r.db.Exec(ctx, `DELETE FROM orders WHERE ID = $1;`, orderId)
+// AddHistoryRecords inserts records in one statement; orderIds[i] is the order of records[i].
+func (r *OrderRepo) AddHistoryRecords(ctx context.Context, records []*pvz_domain.OrderRecord, orderIds []int64) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	pickupPointIDs := make([]int64, 0, len(records))
+	itemIDs := make([]*int64, 0, len(records))
+	descriptions := make([]string, 0, len(records))
+	timestamps := make([]time.Time, 0, len(records))
+	statuses := make([]string, 0, len(records))
+	for _, record := range records {
+		pickupPointIDs = append(pickupPointIDs, record.PickupPointID)
+		itemIDs = append(itemIDs, record.ItemID)
+		descriptions = append(descriptions, record.Description)
+		timestamps = append(timestamps, record.Timestamp)
+		statuses = append(statuses, string(record.Status))
+	}
+
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO order_records (
+			pickup_point_id,
+			order_id,
+			item_id,
+			description,
+			timestamp,
+			status
+		)
+		SELECT u.pickup_point_id, u.order_id, u.item_id, u.description, u.timestamp, u.status::order_status
+		FROM unnest($1::bigint[], $2::bigint[], $3::bigint[], $4::text[], $5::timestamp[], $6::text[])
+			AS u(pickup_point_id, order_id, item_id, description, timestamp, status);`,
+		pickupPointIDs, orderIds, itemIDs, descriptions, timestamps, statuses,
+	)
+	if err != nil {
+		app_logger.MyLogger.Error("add order history records", zap.Error(err))
+	}
+	return err
+}
+
+func (r *OrderRepo) Delete(ctx context.Context, pickupPointID int64, orderId int64) error {
+	commandTag, err := r.db.Exec(ctx, `DELETE FROM orders WHERE ID = $1 AND pickup_point_id = $2;`, orderId, pickupPointID)
 	if err != nil {
 		return err
 	}
@@ -84,9 +305,11 @@ func (r *OrderRepo) Delete(ctx context.Context, orderId int64) error {
 	return nil
 }
 
+// Update writes the order if it is still at updatedOrder.Version and bumps the
+// version. Otherwise it fails with ErrOrderVersionConflict.
 func (r *OrderRepo) Update(ctx context.Context, updatedOrder *pvz_domain.Order) error {
 
-	var updatedID int64
+	var version int64
 
 	query := `	
 	UPDATE orders
@@ -96,9 +319,12 @@ func (r *OrderRepo) Update(ctx context.Context, updatedOrder *pvz_domain.Order)
 		refunded_date=$4,
 		returned_date=$5,
 		status=$6,
-		weight=$7,
-		worth=$8
-	WHERE id = $9 RETURNING id;
+		cell_id=$7,
+		weight=$8,
+		worth=$9,
+		currency=$10,
+		version=version + 1
+	WHERE id = $11 AND pickup_point_id = $12 AND version = $13 RETURNING version;
 	`
 
 	err := r.db.ExecQueryRow(ctx, query,
@@ -108,73 +334,149 @@ func (r *OrderRepo) Update(ctx context.Context, updatedOrder *pvz_domain.Order)
 		updatedOrder.RefundedDate,
 		updatedOrder.ReturnedDate,
 		updatedOrder.Status,
+		updatedOrder.CellID,
 		updatedOrder.Weight,
-		updatedOrder.Worth,
+		updatedOrder.Worth.Decimal(),
+		updatedOrder.Worth.Currency,
 		updatedOrder.ID,
-	).Scan(&updatedID)
+		updatedOrder.PickupPointID,
+		updatedOrder.Version,
+	).Scan(&version)
 
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("%w: order %d at version %d", pvz_domain.ErrOrderVersionConflict, updatedOrder.ID, updatedOrder.Version)
+	}
 	if err != nil {
 		return err
 	}
+	updatedOrder.Version = version
+
+	for _, item := range updatedOrder.Items {
+		commandTag, err := r.db.Exec(ctx, `
+			UPDATE order_items
+			SET status = $1
+			WHERE id = $2 AND order_id = $3;
+		`, item.Status, item.ID, updatedOrder.ID)
+		if err != nil {
+			return err
+		}
+		if commandTag.RowsAffected() != 1 {
+			return fmt.Errorf("item %d of order %d not found", item.ID, updatedOrder.ID)
+		}
+	}
 
 	return nil
 }
 
-func (r *OrderRepo) GetAll(ctx context.Context) ([]*pvz_domain.Order, error) {
+// MoveToPickupPoint leaves the version as is: callers update the moved order in the
+// same transaction, which bumps it.
+func (r *OrderRepo) MoveToPickupPoint(ctx context.Context, orderId int64, fromPickupPointID int64, toPickupPointID int64) error {
+	commandTag, err := r.db.Exec(ctx, `
+		UPDATE orders
+		SET pickup_point_id = $3
+		WHERE id = $1 AND pickup_point_id = $2;
+	`, orderId, fromPickupPointID, toPickupPointID)
+	if err != nil {
+		return err
+	}
+	if commandTag.RowsAffected() != 1 {
+		return fmt.Errorf("order %d not found", orderId)
+	}
+	return nil
+}
+
+func (r *OrderRepo) GetAll(ctx context.Context, pickupPointID int64) ([]*pvz_domain.Order, error) {
 	var orderDTOs []orderDTO
-	err := r.db.Select(ctx, &orderDTOs, `SELECT * FROM orders ORDER BY id ASC`)
+	err := r.db.Select(ctx, &orderDTOs, `SELECT * FROM orders WHERE pickup_point_id = $1 ORDER BY id ASC`, pickupPointID)
 	if err != nil {
 		return nil, err
 	}
 
-	var recordDTOs []orderRecordDTO
-	orderRecordsErr := r.db.Select(ctx, &recordDTOs, `
-        SELECT id, order_id, TIMESTAMP, status, description
-        FROM order_records
-        ORDER BY order_id, TIMESTAMP
-    `)
-	if orderRecordsErr != nil {
-		return nil, orderRecordsErr
-	}
+	return r.withHistory(ctx, orderDTOs)
+}
 
-	m := make(map[int64][]pvz_domain.OrderRecord)
-	for _, recordDTO := range recordDTOs {
-		orderRecordModel := transformOrderRecordDtoToModel(&recordDTO)
-		m[recordDTO.OrderID] = append(m[recordDTO.OrderID], *orderRecordModel)
-	}
+func (r *OrderRepo) GetList(ctx context.Context, pickupPointID int64, pagination *pvz_domain.Pagination) ([]*pvz_domain.Order, error) {
 
-	var orders []*pvz_domain.Order
+	var orderDTOs []orderDTO
+	err := r.db.Select(ctx, &orderDTOs, `
+		SELECT * 
+		FROM orders
+		WHERE pickup_point_id = $1
+		ORDER BY id ASC
+		LIMIT $2
+		OFFSET $3
+	`, pickupPointID, pagination.Limit, pagination.Offset)
 
-	for _, dto := range orderDTOs {
-		orderModel := transformOrderDtoToModel(&dto)
-		orderModel.History = m[orderModel.ID]
-		orders = append(orders, orderModel)
+	if err != nil {
+		return nil, err
 	}
 
-	return orders, nil
+	return r.withHistory(ctx, orderDTOs)
 }
 
-func (r *OrderRepo) GetList(ctx context.Context, pagination *pvz_domain.Pagination) ([]*pvz_domain.Order, error) {
+// Export returns up to limit orders of the pickup point after afterID that match
+// the filter, in id order, so that callers can page through all of them by the last id.
+func (r *OrderRepo) Export(ctx context.Context, pickupPointID int64, filter *pvz_domain.OrderExportFilter, afterID int64, limit int64) ([]*pvz_domain.Order, error) {
+	statuses := make([]string, 0, len(filter.Statuses))
+	for _, status := range filter.Statuses {
+		statuses = append(statuses, string(status))
+	}
 
 	var orderDTOs []orderDTO
 	err := r.db.Select(ctx, &orderDTOs, `
-		SELECT * 
+		SELECT *
 		FROM orders
+		WHERE pickup_point_id = $1
+			AND id > $2
+			AND (cardinality($3::text[]) = 0 OR status::text = ANY($3))
+			AND ($4::bigint = 0 OR recipient_id = $4)
+			AND ($5::timestamp IS NULL OR expiration_date > $5)
+			AND ($6::timestamp IS NULL OR expiration_date < $6)
 		ORDER BY id ASC
-		LIMIT $1
-		OFFSET $2
-	`, pagination.Limit, pagination.Offset)
+		LIMIT $7
+	`, pickupPointID, afterID, statuses, filter.RecipientID, filter.ExpiresAfter, filter.ExpiresBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.withHistory(ctx, orderDTOs)
+}
 
+// GetStatusChanges returns up to limit history records of the pickup point after
+// afterRecordID, in id order.
+func (r *OrderRepo) GetStatusChanges(ctx context.Context, pickupPointID int64, afterRecordID int64, limit int64) ([]*pvz_domain.OrderStatusChange, error) {
+	var recordDTOs []orderRecordDTO
+	err := r.db.Select(ctx, &recordDTOs, `
+		SELECT id, pickup_point_id, order_id, item_id, timestamp, status, description
+		FROM order_records
+		WHERE pickup_point_id = $1 AND id > $2
+		ORDER BY id ASC
+		LIMIT $3
+	`, pickupPointID, afterRecordID, limit)
 	if err != nil {
 		return nil, err
 	}
 
+	changes := make([]*pvz_domain.OrderStatusChange, 0, len(recordDTOs))
+	for _, recordDTO := range recordDTOs {
+		changes = append(changes, transformOrderRecordDtoToStatusChange(&recordDTO))
+	}
+	return changes, nil
+}
+
+func (r *OrderRepo) withHistory(ctx context.Context, orderDTOs []orderDTO) ([]*pvz_domain.Order, error) {
+	ids := make([]int64, 0, len(orderDTOs))
+	for _, dto := range orderDTOs {
+		ids = append(ids, dto.ID)
+	}
+
 	var recordDTOs []orderRecordDTO
 	orderRecordsErr := r.db.Select(ctx, &recordDTOs, `
-        SELECT id, order_id, TIMESTAMP, status, description
+        SELECT id, pickup_point_id, order_id, item_id, TIMESTAMP, status, description
         FROM order_records
+        WHERE order_id = ANY($1)
         ORDER BY order_id, TIMESTAMP
-    `)
+    `, ids)
 
 	if orderRecordsErr != nil {
 		return nil, orderRecordsErr
@@ -194,16 +496,53 @@ func (r *OrderRepo) GetList(ctx context.Context, pagination *pvz_domain.Paginati
 		orders = append(orders, orderModel)
 	}
 
+	if err := r.withItems(ctx, orders...); err != nil {
+		return nil, err
+	}
+
 	return orders, nil
 }
 
-func (r *OrderRepo) GetByIDs(ctx context.Context, ids []int64) ([]*pvz_domain.Order, error) {
+func (r *OrderRepo) withItems(ctx context.Context, orders ...*pvz_domain.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(orders))
+	for _, order := range orders {
+		ids = append(ids, order.ID)
+	}
+
+	var itemDTOs []orderItemDTO
+	err := r.db.Select(ctx, &itemDTOs, `
+		SELECT *
+		FROM order_items
+		WHERE order_id = ANY($1)
+		ORDER BY order_id, id
+	`, ids)
+	if err != nil {
+		return err
+	}
+
+	m := make(map[int64][]*pvz_domain.OrderItem)
+	for _, itemDTO := range itemDTOs {
+		m[itemDTO.OrderID] = append(m[itemDTO.OrderID], transformOrderItemDtoToModel(&itemDTO))
+	}
+
+	for _, order := range orders {
+		order.Items = m[order.ID]
+	}
+
+	return nil
+}
+
+func (r *OrderRepo) GetByIDs(ctx context.Context, pickupPointID int64, ids []int64) ([]*pvz_domain.Order, error) {
 	if len(ids) == 0 {
 		return []*pvz_domain.Order{}, nil
 	}
 
 	var orderDTOs []orderDTO
-	err := r.db.Select(ctx, &orderDTOs, `SELECT * FROM orders WHERE id = ANY($1) ORDER BY id ASC`, ids)
+	err := r.db.Select(ctx, &orderDTOs, `SELECT * FROM orders WHERE id = ANY($1) AND pickup_point_id = $2 ORDER BY id ASC`, ids, pickupPointID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return []*pvz_domain.Order{}, nil
@@ -217,41 +556,56 @@ func (r *OrderRepo) GetByIDs(ctx context.Context, ids []int64) ([]*pvz_domain.Or
 		orders = append(orders, transformOrderDtoToModel(&dto))
 	}
 
+	if err := r.withItems(ctx, orders...); err != nil {
+		return nil, err
+	}
+
 	return orders, nil
 }
 
-func (r *OrderRepo) GetRecipientOrderByID(ctx context.Context, id int64, recipientId int64) (*pvz_domain.Order, error) {
+func (r *OrderRepo) GetRecipientOrderByID(ctx context.Context, pickupPointID int64, id int64, recipientId int64) (*pvz_domain.Order, error) {
 	var a orderDTO
-	err := r.db.Get(ctx, &a, "SELECT * FROM orders WHERE id=$1 AND recipient_id=$2", id, recipientId)
+	err := r.db.Get(ctx, &a, "SELECT * FROM orders WHERE id=$1 AND recipient_id=$2 AND pickup_point_id=$3", id, recipientId, pickupPointID)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("order %d not found", id)
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: order %d", pvz_domain.ErrOrderNotFound, id)
 		}
 		return nil, err
 	}
-	return transformOrderDtoToModel(&a), nil
+
+	order := transformOrderDtoToModel(&a)
+	if err := r.withItems(ctx, order); err != nil {
+		return nil, err
+	}
+	return order, nil
 }
 
-func (r *OrderRepo) GetByID(ctx context.Context, id int64) (*pvz_domain.Order, error) {
+func (r *OrderRepo) GetByID(ctx context.Context, pickupPointID int64, id int64) (*pvz_domain.Order, error) {
 	var a orderDTO
-	err := r.db.Get(ctx, &a, "SELECT * FROM orders WHERE id=$1", id)
+	err := r.db.Get(ctx, &a, "SELECT * FROM orders WHERE id=$1 AND pickup_point_id=$2", id, pickupPointID)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("order %d not found", id)
+		if errors.Is(err, sql.ErrNoRows) || errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("%w: order %d", pvz_domain.ErrOrderNotFound, id)
 		}
 		return nil, err
 	}
-	return transformOrderDtoToModel(&a), nil
+
+	order := transformOrderDtoToModel(&a)
+	if err := r.withItems(ctx, order); err != nil {
+		return nil, err
+	}
+	return order, nil
 }
 
-func (r *OrderRepo) SeedOrders(ctx context.Context) {
+func (r *OrderRepo) SeedOrders(ctx context.Context, pickupPointID int64) {
 	now := time.Now()
 
 	history := []pvz_domain.OrderRecord{
 		{
-			Timestamp:   now.Add(-2 * time.Hour),
-			Status:      pvz_domain.OrderStatusReceived,
-			Description: "Получено от курьера",
+			PickupPointID: pickupPointID,
+			Timestamp:     now.Add(-2 * time.Hour),
+			Status:        pvz_domain.OrderStatusReceived,
+			Description:   "Получено от курьера",
 		},
 	}
 
@@ -260,9 +614,10 @@ func (r *OrderRepo) SeedOrders(ctx context.Context) {
 
 		err := r.db.ExecQueryRow(ctx, `
 			INSERT INTO orders
-			(recipient_id, expiration_date, delivered_date, refunded_date, returned_date, status, weight, worth)
-			VALUES ($1,$2,$3,$4,$5,$6,$7,$8)
+			(pickup_point_id, recipient_id, expiration_date, delivered_date, refunded_date, returned_date, status, weight, worth, currency)
+			VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10)
 			RETURNING id`,
+			pickupPointID,                   // pickup point
 			58,                              // recipient
 			now.Add(48*time.Hour),           // expiration
 			now.Add(-1*time.Hour),           // delivered_date
@@ -270,7 +625,8 @@ func (r *OrderRepo) SeedOrders(ctx context.Context) {
 			nil,                             // returned_date
 			pvz_domain.OrderStatusDelivered, // status
 			1.5,                             // weight
-			2500.0,                          // worth
+			"2500.00",                       // worth
+			pvz_domain.DefaultCurrency,      // currency
 		).Scan(&orderID)
 
 		if err != nil {
@@ -280,8 +636,9 @@ func (r *OrderRepo) SeedOrders(ctx context.Context) {
 
 		for _, rec := range history {
 			_, err := r.db.Exec(ctx, `
-				INSERT INTO order_records (order_id, timestamp, status, description)
-				VALUES ($1, $2, $3, $4)`,
+				INSERT INTO order_records (pickup_point_id, order_id, timestamp, status, description)
+				VALUES ($1, $2, $3, $4, $5)`,
+				rec.PickupPointID,
 				orderID,
 				rec.Timestamp,
 				rec.Status,
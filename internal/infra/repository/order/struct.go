@@ -2,32 +2,40 @@ package order
 
 import (
 	"database/sql"
+	"math/big"
 	"time"
 
 	"github.com/Staspol216/gh1/internal/domain/order"
+	"github.com/jackc/pgtype"
 )
 
 type orderDTO struct {
 	ID             int64                  `db:"id"`
+	PickupPointID  int64                  `db:"pickup_point_id"`
 	RecipientID    int64                  `db:"recipient_id"`
 	ExpirationDate time.Time              `db:"expiration_date"`
 	DeliveredDate  sql.NullTime           `db:"delivered_date"`
 	RefundedDate   sql.NullTime           `db:"refunded_date"`
 	ReturnedDate   sql.NullTime           `db:"returned_date"`
 	Status         pvz_domain.OrderStatus `db:"status"`
+	CellID         sql.NullInt64          `db:"cell_id"`
 	Weight         float64                `db:"weight"`
-	Worth          float64                `db:"worth"`
+	Worth          pgtype.Numeric         `db:"worth"`
+	Currency       string                 `db:"currency"`
+	Version        int64                  `db:"version"`
 }
 
 func transformOrderDtoToModel(o *orderDTO) *pvz_domain.Order {
 	orderModel := &pvz_domain.Order{
 		ID:             o.ID,
+		PickupPointID:  o.PickupPointID,
 		RecipientID:    o.RecipientID,
 		ExpirationDate: o.ExpirationDate,
 		Status:         o.Status,
 		History:        make([]pvz_domain.OrderRecord, 0),
 		Weight:         o.Weight,
-		Worth:          o.Worth,
+		Worth:          numericToMoney(o.Worth, o.Currency),
+		Version:        o.Version,
 	}
 	if o.DeliveredDate.Valid {
 		orderModel.DeliveredDate = &o.DeliveredDate.Time
@@ -38,23 +46,99 @@ func transformOrderDtoToModel(o *orderDTO) *pvz_domain.Order {
 	if o.ReturnedDate.Valid {
 		orderModel.ReturnedDate = &o.ReturnedDate.Time
 	}
+	if o.CellID.Valid {
+		orderModel.PlaceInCell(o.CellID.Int64)
+	}
 	return orderModel
 }
 
 type orderRecordDTO struct {
-	ID          int64                  `db:"id"`
-	OrderID     int64                  `db:"order_id"`
-	Timestamp   time.Time              `db:"timestamp"`
-	Status      pvz_domain.OrderStatus `db:"status"`
-	Description string                 `db:"description"`
+	ID            int64                  `db:"id"`
+	PickupPointID int64                  `db:"pickup_point_id"`
+	OrderID       int64                  `db:"order_id"`
+	ItemID        sql.NullInt64          `db:"item_id"`
+	Timestamp     time.Time              `db:"timestamp"`
+	Status        pvz_domain.OrderStatus `db:"status"`
+	Description   string                 `db:"description"`
 }
 
 func transformOrderRecordDtoToModel(record *orderRecordDTO) *pvz_domain.OrderRecord {
 	orderRecordModel := &pvz_domain.OrderRecord{
-		Timestamp:   record.Timestamp,
-		Status:      record.Status,
-		Description: record.Description,
+		PickupPointID: record.PickupPointID,
+		Timestamp:     record.Timestamp,
+		Status:        record.Status,
+		Description:   record.Description,
+	}
+	if record.ItemID.Valid {
+		itemID := record.ItemID.Int64
+		orderRecordModel.ItemID = &itemID
 	}
 
 	return orderRecordModel
 }
+
+func transformOrderRecordDtoToStatusChange(record *orderRecordDTO) *pvz_domain.OrderStatusChange {
+	change := &pvz_domain.OrderStatusChange{
+		RecordID:      record.ID,
+		OrderID:       record.OrderID,
+		PickupPointID: record.PickupPointID,
+		Status:        record.Status,
+		Description:   record.Description,
+		Timestamp:     record.Timestamp,
+	}
+	if record.ItemID.Valid {
+		itemID := record.ItemID.Int64
+		change.ItemID = &itemID
+	}
+
+	return change
+}
+
+type orderItemDTO struct {
+	ID       int64                  `db:"id"`
+	OrderID  int64                  `db:"order_id"`
+	SKU      string                 `db:"sku"`
+	Quantity int64                  `db:"quantity"`
+	Price    pgtype.Numeric         `db:"price"`
+	Currency string                 `db:"currency"`
+	Weight   float64                `db:"weight"`
+	Status   pvz_domain.OrderStatus `db:"status"`
+}
+
+func transformOrderItemDtoToModel(item *orderItemDTO) *pvz_domain.OrderItem {
+	return &pvz_domain.OrderItem{
+		ID:       item.ID,
+		SKU:      item.SKU,
+		Quantity: item.Quantity,
+		Price:    numericToMoney(item.Price, item.Currency),
+		Weight:   item.Weight,
+		Status:   item.Status,
+	}
+}
+
+// numericToMoney converts a NUMERIC(14, 2) column into minor units without
+// passing through float64.
+func numericToMoney(n pgtype.Numeric, currency string) pvz_domain.Money {
+	if n.Status != pgtype.Present || n.NaN || n.Int == nil {
+		return pvz_domain.NewMoney(0, currency)
+	}
+
+	const minorUnitExp = 2
+	amount := new(big.Int).Set(n.Int)
+	exp := int64(n.Exp) + minorUnitExp
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(absInt64(exp)), nil)
+	if exp >= 0 {
+		amount.Mul(amount, scale)
+	} else {
+		amount.Quo(amount, scale)
+	}
+
+	return pvz_domain.NewMoney(amount.Int64(), currency)
+}
+
+func absInt64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,87 @@
+package pickupcode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Staspol216/gh1/internal/domain/order"
+	"github.com/Staspol216/gh1/internal/ports"
+	"github.com/jackc/pgx/v4"
+)
+
+type PickupCodeRepo struct {
+	db pvz_ports.DB
+}
+
+func NewPickupCodeRepo(database pvz_ports.DB) (*PickupCodeRepo, error) {
+	return &PickupCodeRepo{
+		db: database,
+	}, nil
+}
+
+// Save stores a freshly issued code, replacing the previous one. Failed attempts and
+// a lock-out are kept, so that regenerating a code does not reset guessing limits.
+func (r *PickupCodeRepo) Save(ctx context.Context, code *pvz_domain.PickupCode) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO order_pickup_codes (order_id, code_hash, expires_at, attempts, locked_until)
+		VALUES ($1, $2, $3, 0, NULL)
+		ON CONFLICT (order_id) DO UPDATE
+		SET code_hash = EXCLUDED.code_hash,
+			expires_at = EXCLUDED.expires_at,
+			created_at = now();
+	`, code.OrderID, code.CodeHash, code.ExpiresAt)
+	return err
+}
+
+// SaveBatch stores codes of new orders in one statement.
+func (r *PickupCodeRepo) SaveBatch(ctx context.Context, codes []*pvz_domain.PickupCode) error {
+	if len(codes) == 0 {
+		return nil
+	}
+
+	orderIDs := make([]int64, 0, len(codes))
+	hashes := make([]string, 0, len(codes))
+	expiresAts := make([]time.Time, 0, len(codes))
+	for _, code := range codes {
+		orderIDs = append(orderIDs, code.OrderID)
+		hashes = append(hashes, code.CodeHash)
+		expiresAts = append(expiresAts, code.ExpiresAt)
+	}
+
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO order_pickup_codes (order_id, code_hash, expires_at, attempts, locked_until)
+		SELECT u.order_id, u.code_hash, u.expires_at, 0, NULL
+		FROM unnest($1::bigint[], $2::text[], $3::timestamptz[]) AS u(order_id, code_hash, expires_at);
+	`, orderIDs, hashes, expiresAts)
+	return err
+}
+
+// GetForUpdate locks the code row so that concurrent verifications count attempts correctly.
+func (r *PickupCodeRepo) GetForUpdate(ctx context.Context, orderId int64) (*pvz_domain.PickupCode, error) {
+	var c pickupCodeDTO
+	err := r.db.Get(ctx, &c, "SELECT * FROM order_pickup_codes WHERE order_id=$1 FOR UPDATE", orderId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("pickup code for order %d: %w", orderId, pvz_domain.ErrPickupCodeNotIssued)
+		}
+		return nil, err
+	}
+	return transformPickupCodeDtoToModel(&c), nil
+}
+
+func (r *PickupCodeRepo) UpdateAttempts(ctx context.Context, code *pvz_domain.PickupCode) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE order_pickup_codes
+		SET attempts = $2,
+			locked_until = $3
+		WHERE order_id = $1;
+	`, code.OrderID, code.Attempts, code.LockedUntil)
+	return err
+}
+
+func (r *PickupCodeRepo) Delete(ctx context.Context, orderId int64) error {
+	_, err := r.db.Exec(ctx, "DELETE FROM order_pickup_codes WHERE order_id=$1", orderId)
+	return err
+}
@@ -0,0 +1,31 @@
+package pickupcode
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/Staspol216/gh1/internal/domain/order"
+)
+
+type pickupCodeDTO struct {
+	OrderID     int64        `db:"order_id"`
+	CodeHash    string       `db:"code_hash"`
+	ExpiresAt   time.Time    `db:"expires_at"`
+	Attempts    int64        `db:"attempts"`
+	LockedUntil sql.NullTime `db:"locked_until"`
+	CreatedAt   time.Time    `db:"created_at"`
+}
+
+func transformPickupCodeDtoToModel(c *pickupCodeDTO) *pvz_domain.PickupCode {
+	code := &pvz_domain.PickupCode{
+		OrderID:   c.OrderID,
+		CodeHash:  c.CodeHash,
+		ExpiresAt: c.ExpiresAt,
+		Attempts:  c.Attempts,
+	}
+	if c.LockedUntil.Valid {
+		lockedUntil := c.LockedUntil.Time
+		code.LockedUntil = &lockedUntil
+	}
+	return code
+}
@@ -0,0 +1,81 @@
+package tx_manager
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Staspol216/gh1/pkg/logger"
+	"github.com/Staspol216/gh1/pkg/monitoring"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"go.uber.org/zap"
+)
+
+// replicaLagQuery reports how far the replica is behind the primary in seconds.
+// A replica that has replayed everything it received is not lagging even if the
+// last replayed transaction is old; an unknown lag is reported as infinite.
+const replicaLagQuery = `SELECT CASE
+	WHEN NOT pg_is_in_recovery() THEN 0
+	WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+	ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 'Infinity')
+END::float8`
+
+const replicaLagCheckTimeout = time.Second
+
+// Replica is a read-only pool guarded against staleness: it is used only while its
+// replication lag, checked at most once per CheckInterval, stays within MaxLag.
+type Replica struct {
+	pool          *pgxpool.Pool
+	maxLag        time.Duration
+	checkInterval time.Duration
+
+	checking  sync.Mutex
+	checkedAt atomic.Int64
+	fresh     atomic.Bool
+}
+
+func NewReplica(pool *pgxpool.Pool, maxLag time.Duration, checkInterval time.Duration) *Replica {
+	return &Replica{
+		pool:          pool,
+		maxLag:        maxLag,
+		checkInterval: checkInterval,
+	}
+}
+
+// Fresh reports whether reads may go to the replica. Callers never wait for a lag
+// check that is already running; they use the last known state instead.
+func (r *Replica) Fresh(ctx context.Context) bool {
+	if r == nil {
+		return false
+	}
+
+	due := time.Since(time.Unix(0, r.checkedAt.Load())) >= r.checkInterval
+	if due && r.checking.TryLock() {
+		r.check(ctx)
+		r.checking.Unlock()
+	}
+
+	return r.fresh.Load()
+}
+
+func (r *Replica) check(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), replicaLagCheckTimeout)
+	defer cancel()
+
+	var lag float64
+	err := r.pool.QueryRow(ctx, replicaLagQuery).Scan(&lag)
+	fresh := err == nil && lag <= r.maxLag.Seconds()
+
+	if err != nil {
+		app_logger.MyLogger.Warn("replica lag check failed", zap.Error(err))
+	}
+	if wasFresh := r.fresh.Swap(fresh); wasFresh != fresh {
+		app_logger.MyLogger.Info("replica routing changed",
+			zap.Bool("fresh", fresh),
+			zap.Float64("lag_seconds", lag),
+		)
+	}
+	monitoring.ObserveReplicaLag(lag, err)
+	r.checkedAt.Store(time.Now().UnixNano())
+}
@@ -3,69 +3,318 @@ package tx_manager
 import (
 	"context"
 	"errors"
+	"math/rand/v2"
+	"time"
 
 	"github.com/Staspol216/gh1/internal/ports"
 	"github.com/Staspol216/gh1/pkg/logger"
+	"github.com/Staspol216/gh1/pkg/monitoring"
+	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/opentracing/opentracing-go"
 	"go.uber.org/zap"
 )
 
+const (
+	sqlStateSerializationFailure = "40001"
+	sqlStateDeadlockDetected     = "40P01"
+)
+
 type txManagerKey struct{}
 
+type txHooksKey struct{}
+
 var txKey = &txManagerKey{}
 
+var hooksKey = &txHooksKey{}
+
+// txHooks collects work that must only happen once the transaction outcome is known.
+type txHooks struct {
+	afterCommit   []pvz_ports.TxHook
+	afterRollback []pvz_ports.TxHook
+}
+
+// runHooks executes hooks in registration order. A failing hook does not stop the
+// following ones; all errors are joined.
+func runHooks(ctx context.Context, hooks []pvz_ports.TxHook) error {
+	var errs []error
+	for _, hook := range hooks {
+		if err := hook(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// RetryPolicy controls how a transaction aborted by a serialization failure or a
+// deadlock is replayed. MaxRetries of 0 disables retries.
+type RetryPolicy struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}
+
+// delay returns the wait before the given retry (starting at 1): exponential
+// backoff capped at MaxDelay, with full jitter so that conflicting transactions
+// don't collide again. A MaxDelay of 0 leaves the backoff uncapped; if the shift
+// overflows then, the backoff falls back to BaseDelay.
+func (p RetryPolicy) delay(retry int) time.Duration {
+	if p.BaseDelay <= 0 {
+		return 0
+	}
+
+	backoff := p.BaseDelay << min(retry-1, 30)
+	if p.MaxDelay > 0 && (backoff <= 0 || backoff > p.MaxDelay) {
+		backoff = p.MaxDelay
+	}
+	if backoff <= 0 {
+		backoff = p.BaseDelay
+	}
+	return rand.N(backoff) + 1
+}
+
+// retryableSQLState returns the SQLSTATE of err if Postgres aborted the transaction
+// in a way that is safe to replay from the start.
+func retryableSQLState(err error) (string, bool) {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return "", false
+	}
+
+	switch pgErr.Code {
+	case sqlStateSerializationFailure, sqlStateDeadlockDetected:
+		return pgErr.Code, true
+	}
+	return "", false
+}
+
+// pool is the part of *pgxpool.Pool that TxManager uses.
+type pool interface {
+	pvz_ports.QueryEngine
+	BeginTx(ctx context.Context, txOptions pgx.TxOptions) (pgx.Tx, error)
+}
+
 type TxManager struct {
-	pool    *pgxpool.Pool
-	context context.Context
+	pool    pool
+	replica *Replica
+	retry   RetryPolicy
 }
 
-func New(pool *pgxpool.Pool, context context.Context) *TxManager {
-	return &TxManager{pool: pool, context: context}
+// New creates a TxManager over the primary pool. replica may be nil, then all
+// reads go to the primary.
+func New(pool *pgxpool.Pool, replica *Replica, retry RetryPolicy) *TxManager {
+	return &TxManager{pool: pool, replica: replica, retry: retry}
 }
 
-func (m *TxManager) RunSerializable(fn func(ctxTx context.Context) error) error {
-	opts := pgx.TxOptions{
+// RunReadOnly runs fn in a read-only snapshot. Outside of a transaction it goes to
+// the replica when it is fresh enough.
+func (m *TxManager) RunReadOnly(ctx context.Context, fn func(ctxTx context.Context) error, opts ...pvz_ports.TxOption) error {
+	txOptions := pgx.TxOptions{
+		IsoLevel:   pgx.RepeatableRead,
+		AccessMode: pgx.ReadOnly,
+	}
+	return m.beginFunc(ctx, txOptions, pvz_ports.NewTxOptions(opts...), fn)
+}
+
+func (m *TxManager) RunSerializable(ctx context.Context, fn func(ctxTx context.Context) error, opts ...pvz_ports.TxOption) error {
+	txOptions := pgx.TxOptions{
 		IsoLevel:   pgx.Serializable,
 		AccessMode: pgx.ReadWrite,
 	}
-	return m.beginFunc(opts, fn)
+	return m.beginFunc(ctx, txOptions, pvz_ports.NewTxOptions(opts...), fn)
 }
 
-func (m *TxManager) RunRepeatableRead(fn func(ctxTx context.Context) error) error {
-	opts := pgx.TxOptions{
+func (m *TxManager) RunRepeatableRead(ctx context.Context, fn func(ctxTx context.Context) error, opts ...pvz_ports.TxOption) error {
+	txOptions := pgx.TxOptions{
 		IsoLevel:   pgx.RepeatableRead,
 		AccessMode: pgx.ReadWrite,
 	}
-	return m.beginFunc(opts, fn)
+	return m.beginFunc(ctx, txOptions, pvz_ports.NewTxOptions(opts...), fn)
 }
 
-func (m *TxManager) RunReadCommitted(fn func(ctxTx context.Context) error) error {
-	opts := pgx.TxOptions{
+func (m *TxManager) RunReadCommitted(ctx context.Context, fn func(ctxTx context.Context) error, opts ...pvz_ports.TxOption) error {
+	txOptions := pgx.TxOptions{
 		IsoLevel:   pgx.ReadCommitted,
 		AccessMode: pgx.ReadWrite,
 	}
-	return m.beginFunc(opts, fn)
+	return m.beginFunc(ctx, txOptions, pvz_ports.NewTxOptions(opts...), fn)
+}
+
+// beginFunc runs fn in a new transaction, or according to options.Propagation when
+// ctx already carries one. Nested work always uses the isolation level of the outer
+// transaction unless it requires a new one.
+func (m *TxManager) beginFunc(ctx context.Context, txOptions pgx.TxOptions, options pvz_ports.TxOptions, fn func(ctxTx context.Context) error) error {
+	if outer, ok := ctx.Value(txKey).(pgx.Tx); ok && outer != nil {
+		switch options.Propagation {
+		case pvz_ports.PropagationJoin:
+			return fn(ctx)
+		case pvz_ports.PropagationSavepoint:
+			// A serialization failure aborts the whole outer transaction, so it is
+			// retried there rather than here.
+			return m.savepointFunc(ctx, outer, fn)
+		}
+	}
+
+	begin := m.pool
+	if txOptions.AccessMode == pgx.ReadOnly {
+		begin = m.readPool(ctx)
+	}
+
+	return m.retryFunc(ctx, begin, txOptions, fn)
+}
+
+// retryFunc runs fn in a new transaction and replays it while Postgres aborts it
+// with a retryable SQLSTATE, up to m.retry.MaxRetries times. Every attempt gets its
+// own hooks: after-commit hooks of a failed attempt are dropped together with it.
+func (m *TxManager) retryFunc(ctx context.Context, pool pool, txOptions pgx.TxOptions, fn func(ctxTx context.Context) error) error {
+	isolation := string(txOptions.IsoLevel)
+
+	for retry := 1; ; retry++ {
+		err := m.txFunc(ctx, pool, txOptions, fn)
+		sqlState, ok := retryableSQLState(err)
+		if !ok {
+			return err
+		}
+
+		span := opentracing.SpanFromContext(ctx)
+		if retry > m.retry.MaxRetries {
+			monitoring.ObserveTxRetry(isolation, sqlState, true)
+			if span != nil {
+				span.SetTag("tx.retries_exhausted", true)
+			}
+			app_logger.MyLogger.Warn("transaction retries exhausted",
+				zap.String("isolation", isolation),
+				zap.String("sqlstate", sqlState),
+				zap.Int("retries", m.retry.MaxRetries),
+			)
+			return err
+		}
+
+		monitoring.ObserveTxRetry(isolation, sqlState, false)
+		if span != nil {
+			span.SetTag("tx.retries", retry)
+			span.LogKV("event", "tx_retry", "sqlstate", sqlState, "retry", retry)
+		}
+
+		timer := time.NewTimer(m.retry.delay(retry))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
 }
 
-func (m *TxManager) beginFunc(opts pgx.TxOptions, fn func(ctxTx context.Context) error) error {
-	tx, err := m.pool.BeginTx(m.context, opts)
+// txFunc runs a single attempt of fn in a new transaction.
+func (m *TxManager) txFunc(ctx context.Context, pool pool, txOptions pgx.TxOptions, fn func(ctxTx context.Context) error) error {
+	tx, err := pool.BeginTx(ctx, txOptions)
 	if err != nil {
 		return err
 	}
 
+	// Rollback must reach the server even when ctx is already cancelled.
+	cleanupCtx := context.WithoutCancel(ctx)
+
 	defer func() {
-		if rollbackErr := tx.Rollback(m.context); rollbackErr != nil && !errors.Is(rollbackErr, pgx.ErrTxClosed) {
+		if rollbackErr := tx.Rollback(cleanupCtx); rollbackErr != nil && !errors.Is(rollbackErr, pgx.ErrTxClosed) {
 			app_logger.MyLogger.Error("transaction rollback failed", zap.Error(rollbackErr))
 		}
 	}()
 
-	ctxTx := context.WithValue(m.context, txKey, tx)
+	hooks := &txHooks{}
+	ctxTx := context.WithValue(ctx, txKey, tx)
+	ctxTx = context.WithValue(ctxTx, hooksKey, hooks)
 	if err := fn(ctxTx); err != nil {
+		m.rollback(cleanupCtx, tx, hooks)
 		return err
 	}
 
-	return tx.Commit(m.context)
+	if err := tx.Commit(ctx); err != nil {
+		m.rollback(cleanupCtx, tx, hooks)
+		return err
+	}
+
+	// The transaction is durable at this point, so hook failures are reported but not returned.
+	if err := runHooks(detach(cleanupCtx), hooks.afterCommit); err != nil {
+		app_logger.MyLogger.Error("after commit hooks failed", zap.Error(err))
+	}
+
+	return nil
+}
+
+// savepointFunc runs fn in a SAVEPOINT of outer. Hooks registered by fn follow the
+// outer transaction if the savepoint is released. If it is rolled back, its
+// after-rollback hooks run right away and its after-commit hooks are dropped.
+func (m *TxManager) savepointFunc(ctx context.Context, outer pgx.Tx, fn func(ctxTx context.Context) error) error {
+	savepoint, err := outer.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	cleanupCtx := context.WithoutCancel(ctx)
+
+	hooks := &txHooks{}
+	ctxTx := context.WithValue(ctx, txKey, savepoint)
+	ctxTx = context.WithValue(ctxTx, hooksKey, hooks)
+	if err := fn(ctxTx); err != nil {
+		m.rollback(cleanupCtx, savepoint, hooks)
+		return err
+	}
+
+	if err := savepoint.Commit(ctx); err != nil {
+		m.rollback(cleanupCtx, savepoint, hooks)
+		return err
+	}
+
+	if parent, ok := ctx.Value(hooksKey).(*txHooks); ok {
+		parent.afterCommit = append(parent.afterCommit, hooks.afterCommit...)
+		parent.afterRollback = append(parent.afterRollback, hooks.afterRollback...)
+	}
+
+	return nil
+}
+
+func (m *TxManager) rollback(ctx context.Context, tx pgx.Tx, hooks *txHooks) {
+	if rollbackErr := tx.Rollback(ctx); rollbackErr != nil && !errors.Is(rollbackErr, pgx.ErrTxClosed) {
+		app_logger.MyLogger.Error("transaction rollback failed", zap.Error(rollbackErr))
+	}
+
+	if err := runHooks(detach(ctx), hooks.afterRollback); err != nil {
+		app_logger.MyLogger.Error("after rollback hooks failed", zap.Error(err))
+	}
+}
+
+// detach strips the transaction from ctx, so that hooks run outside of it.
+func detach(ctx context.Context) context.Context {
+	ctx = context.WithValue(ctx, txKey, nil)
+	return context.WithValue(ctx, hooksKey, nil)
+}
+
+// AfterCommit schedules fn to run after the transaction in ctxTx commits, in
+// registration order. Outside of a transaction fn runs immediately.
+func (m *TxManager) AfterCommit(ctxTx context.Context, fn pvz_ports.TxHook) {
+	hooks, ok := ctxTx.Value(hooksKey).(*txHooks)
+	if !ok {
+		if err := fn(ctxTx); err != nil {
+			app_logger.MyLogger.Error("after commit hook failed", zap.Error(err))
+		}
+		return
+	}
+
+	hooks.afterCommit = append(hooks.afterCommit, fn)
+}
+
+// AfterRollback schedules fn to run after the transaction in ctxTx is rolled back,
+// in registration order. Outside of a transaction fn is never run.
+func (m *TxManager) AfterRollback(ctxTx context.Context, fn pvz_ports.TxHook) {
+	hooks, ok := ctxTx.Value(hooksKey).(*txHooks)
+	if !ok {
+		return
+	}
+
+	hooks.afterRollback = append(hooks.afterRollback, fn)
 }
 
 func (m *TxManager) GetQueryEngine(ctx context.Context) pvz_ports.QueryEngine {
@@ -76,3 +325,24 @@ func (m *TxManager) GetQueryEngine(ctx context.Context) pvz_ports.QueryEngine {
 
 	return m.pool
 }
+
+// GetReadQueryEngine is GetQueryEngine for statements that only read: outside of a
+// transaction they go to the replica when it is fresh enough.
+func (m *TxManager) GetReadQueryEngine(ctx context.Context) pvz_ports.QueryEngine {
+	v, ok := ctx.Value(txKey).(pvz_ports.QueryEngine)
+	if ok && v != nil {
+		return v
+	}
+
+	return m.readPool(ctx)
+}
+
+func (m *TxManager) readPool(ctx context.Context) pool {
+	if !pvz_ports.IsPrimaryRequired(ctx) && m.replica.Fresh(ctx) {
+		monitoring.ObserveReadRouting("replica")
+		return m.replica.pool
+	}
+
+	monitoring.ObserveReadRouting("primary")
+	return m.pool
+}
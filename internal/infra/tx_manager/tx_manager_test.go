@@ -0,0 +1,686 @@
+package tx_manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Staspol216/gh1/internal/ports"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errTest = errors.New("test error")
+
+// fakePool begins fake transactions and records what happens to them, together
+// with the events of the test's hooks, in one log.
+type fakePool struct {
+	pvz_ports.QueryEngine
+
+	mu         sync.Mutex
+	events     []string
+	commitErrs []error
+}
+
+func (p *fakePool) record(event string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event)
+}
+
+func (p *fakePool) log() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]string(nil), p.events...)
+}
+
+// hook returns a hook that records event.
+func (p *fakePool) hook(event string) pvz_ports.TxHook {
+	return func(context.Context) error {
+		p.record(event)
+		return nil
+	}
+}
+
+// failCommits makes the next commits fail with errs, one error per commit.
+func (p *fakePool) failCommits(errs ...error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.commitErrs = append(p.commitErrs, errs...)
+}
+
+func (p *fakePool) nextCommitErr() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.commitErrs) == 0 {
+		return nil
+	}
+	err := p.commitErrs[0]
+	p.commitErrs = p.commitErrs[1:]
+	return err
+}
+
+func (p *fakePool) BeginTx(context.Context, pgx.TxOptions) (pgx.Tx, error) {
+	p.record("begin")
+	return &fakeTx{pool: p}, nil
+}
+
+// fakeTx is a transaction, or a savepoint of one, that only records its outcome.
+type fakeTx struct {
+	pgx.Tx
+
+	pool      *fakePool
+	savepoint bool
+	closed    bool
+}
+
+func (t *fakeTx) Begin(context.Context) (pgx.Tx, error) {
+	t.pool.record("savepoint")
+	return &fakeTx{pool: t.pool, savepoint: true}, nil
+}
+
+func (t *fakeTx) Commit(context.Context) error {
+	if t.closed {
+		return pgx.ErrTxClosed
+	}
+	t.closed = true
+
+	if t.savepoint {
+		t.pool.record("release savepoint")
+		return nil
+	}
+	if err := t.pool.nextCommitErr(); err != nil {
+		t.pool.record("commit failed")
+		return err
+	}
+	t.pool.record("commit")
+	return nil
+}
+
+func (t *fakeTx) Rollback(context.Context) error {
+	if t.closed {
+		return pgx.ErrTxClosed
+	}
+	t.closed = true
+
+	if t.savepoint {
+		t.pool.record("rollback to savepoint")
+		return nil
+	}
+	t.pool.record("rollback")
+	return nil
+}
+
+func newTestTxManager(retry RetryPolicy) (*TxManager, *fakePool) {
+	pool := &fakePool{}
+	return &TxManager{pool: pool, retry: retry}, pool
+}
+
+func pgError(code string) error {
+	return &pgconn.PgError{Code: code}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		policy RetryPolicy
+		retry  int
+		max    time.Duration
+	}{
+		{
+			name:   "no base delay retries right away",
+			policy: RetryPolicy{MaxDelay: time.Second},
+			retry:  3,
+			max:    0,
+		},
+		{
+			name:   "first retry waits up to base delay",
+			policy: RetryPolicy{BaseDelay: 10 * time.Millisecond, MaxDelay: time.Second},
+			retry:  1,
+			max:    10 * time.Millisecond,
+		},
+		{
+			name:   "backoff doubles with every retry",
+			policy: RetryPolicy{BaseDelay: 10 * time.Millisecond, MaxDelay: time.Second},
+			retry:  4,
+			max:    80 * time.Millisecond,
+		},
+		{
+			name:   "backoff is capped at max delay",
+			policy: RetryPolicy{BaseDelay: 10 * time.Millisecond, MaxDelay: 50 * time.Millisecond},
+			retry:  10,
+			max:    50 * time.Millisecond,
+		},
+		{
+			name:   "overflowed shift is capped at max delay",
+			policy: RetryPolicy{BaseDelay: time.Hour, MaxDelay: time.Minute},
+			retry:  math.MaxInt,
+			max:    time.Minute,
+		},
+		{
+			name:   "overflowed shift without max delay falls back to base delay",
+			policy: RetryPolicy{BaseDelay: time.Hour},
+			retry:  math.MaxInt,
+			max:    time.Hour,
+		},
+		{
+			name:   "backoff without max delay is uncapped",
+			policy: RetryPolicy{BaseDelay: time.Millisecond},
+			retry:  11,
+			max:    1024 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			for i := 0; i < 100; i++ {
+				// act
+				delay := tt.policy.delay(tt.retry)
+
+				// assert
+				if tt.max == 0 {
+					require.Zero(t, delay)
+					continue
+				}
+				require.Positive(t, delay)
+				require.LessOrEqual(t, delay, tt.max)
+			}
+		})
+	}
+}
+
+func TestRetryableSQLState(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		err           error
+		wantSQLState  string
+		wantRetryable bool
+	}{
+		{
+			name:          "serialization failure",
+			err:           pgError(sqlStateSerializationFailure),
+			wantSQLState:  "40001",
+			wantRetryable: true,
+		},
+		{
+			name:          "deadlock detected",
+			err:           pgError(sqlStateDeadlockDetected),
+			wantSQLState:  "40P01",
+			wantRetryable: true,
+		},
+		{
+			name:          "wrapped serialization failure",
+			err:           fmt.Errorf("update order: %w", pgError(sqlStateSerializationFailure)),
+			wantSQLState:  "40001",
+			wantRetryable: true,
+		},
+		{
+			name: "unique violation",
+			err:  pgError("23505"),
+		},
+		{
+			name: "lock not available",
+			err:  pgError("55P03"),
+		},
+		{
+			name: "not a postgres error",
+			err:  errTest,
+		},
+		{
+			name: "no error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// act
+			sqlState, retryable := retryableSQLState(tt.err)
+
+			// assert
+			assert.Equal(t, tt.wantSQLState, sqlState)
+			assert.Equal(t, tt.wantRetryable, retryable)
+		})
+	}
+}
+
+func TestTxManager_Retry(t *testing.T) {
+	t.Parallel()
+
+	t.Run("replays a transaction aborted by a serialization failure", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		manager, pool := newTestTxManager(RetryPolicy{MaxRetries: 2})
+		attempt := 0
+
+		// act
+		err := manager.RunSerializable(context.Background(), func(ctxTx context.Context) error {
+			attempt++
+			manager.AfterCommit(ctxTx, pool.hook(fmt.Sprintf("after commit of attempt %d", attempt)))
+			manager.AfterRollback(ctxTx, pool.hook(fmt.Sprintf("after rollback of attempt %d", attempt)))
+			if attempt == 1 {
+				return pgError(sqlStateSerializationFailure)
+			}
+			return nil
+		})
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"begin", "rollback", "after rollback of attempt 1",
+			"begin", "commit", "after commit of attempt 2",
+		}, pool.log())
+	})
+
+	t.Run("replays a transaction whose commit hit a deadlock", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		manager, pool := newTestTxManager(RetryPolicy{MaxRetries: 2})
+		pool.failCommits(pgError(sqlStateDeadlockDetected))
+
+		// act
+		err := manager.RunRepeatableRead(context.Background(), func(ctxTx context.Context) error {
+			manager.AfterCommit(ctxTx, pool.hook("after commit"))
+			return nil
+		})
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{"begin", "commit failed", "begin", "commit", "after commit"}, pool.log())
+	})
+
+	t.Run("gives up after max retries", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		manager, _ := newTestTxManager(RetryPolicy{MaxRetries: 2})
+		attempts := 0
+
+		// act
+		err := manager.RunSerializable(context.Background(), func(context.Context) error {
+			attempts++
+			return pgError(sqlStateSerializationFailure)
+		})
+
+		// assert
+		sqlState, retryable := retryableSQLState(err)
+		require.True(t, retryable)
+		assert.Equal(t, sqlStateSerializationFailure, sqlState)
+		assert.Equal(t, 3, attempts)
+	})
+
+	t.Run("does not replay other errors", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		manager, _ := newTestTxManager(RetryPolicy{MaxRetries: 2})
+		attempts := 0
+
+		// act
+		err := manager.RunSerializable(context.Background(), func(context.Context) error {
+			attempts++
+			return pgError("23505")
+		})
+
+		// assert
+		require.Error(t, err)
+		assert.Equal(t, 1, attempts)
+	})
+
+	t.Run("stops waiting for a retry once ctx is done", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		manager, _ := newTestTxManager(RetryPolicy{MaxRetries: 2, BaseDelay: time.Hour, MaxDelay: time.Hour})
+		ctx, cancel := context.WithCancel(context.Background())
+		attempts := 0
+
+		// act
+		err := manager.RunSerializable(ctx, func(context.Context) error {
+			attempts++
+			cancel()
+			return pgError(sqlStateSerializationFailure)
+		})
+
+		// assert
+		require.Error(t, err)
+		assert.Equal(t, 1, attempts)
+	})
+}
+
+func TestRunHooks(t *testing.T) {
+	t.Parallel()
+
+	// arrange
+	var ran []int
+	errFirst := errors.New("first hook failed")
+	errThird := errors.New("third hook failed")
+	hooks := []pvz_ports.TxHook{
+		func(context.Context) error { ran = append(ran, 1); return errFirst },
+		func(context.Context) error { ran = append(ran, 2); return nil },
+		func(context.Context) error { ran = append(ran, 3); return errThird },
+	}
+
+	// act
+	err := runHooks(context.Background(), hooks)
+
+	// assert
+	assert.Equal(t, []int{1, 2, 3}, ran)
+	assert.ErrorIs(t, err, errFirst)
+	assert.ErrorIs(t, err, errThird)
+}
+
+func TestTxManager_Hooks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("after commit hooks run in registration order once the transaction committed", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		manager, pool := newTestTxManager(RetryPolicy{})
+
+		// act
+		err := manager.RunReadCommitted(context.Background(), func(ctxTx context.Context) error {
+			manager.AfterCommit(ctxTx, pool.hook("first"))
+			manager.AfterRollback(ctxTx, pool.hook("after rollback"))
+			manager.AfterCommit(ctxTx, pool.hook("second"))
+			pool.record("work")
+			manager.AfterCommit(ctxTx, pool.hook("third"))
+			return nil
+		})
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{"begin", "work", "commit", "first", "second", "third"}, pool.log())
+	})
+
+	t.Run("a failing hook neither stops the others nor fails the committed transaction", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		manager, pool := newTestTxManager(RetryPolicy{})
+
+		// act
+		err := manager.RunReadCommitted(context.Background(), func(ctxTx context.Context) error {
+			manager.AfterCommit(ctxTx, func(context.Context) error {
+				pool.record("first")
+				return errTest
+			})
+			manager.AfterCommit(ctxTx, pool.hook("second"))
+			return nil
+		})
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{"begin", "commit", "first", "second"}, pool.log())
+	})
+
+	t.Run("hooks run outside of the transaction", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		manager, pool := newTestTxManager(RetryPolicy{})
+		var hookEngine pvz_ports.QueryEngine
+
+		// act
+		err := manager.RunReadCommitted(context.Background(), func(ctxTx context.Context) error {
+			manager.AfterCommit(ctxTx, func(ctx context.Context) error {
+				hookEngine = manager.GetQueryEngine(ctx)
+				manager.AfterCommit(ctx, pool.hook("nested after commit"))
+				return nil
+			})
+			return nil
+		})
+
+		// assert
+		require.NoError(t, err)
+		assert.Same(t, pool, hookEngine)
+		assert.Equal(t, []string{"begin", "commit", "nested after commit"}, pool.log())
+	})
+
+	t.Run("rollback skips after commit hooks and runs after rollback hooks", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		manager, pool := newTestTxManager(RetryPolicy{})
+
+		// act
+		err := manager.RunReadCommitted(context.Background(), func(ctxTx context.Context) error {
+			manager.AfterCommit(ctxTx, pool.hook("after commit"))
+			manager.AfterRollback(ctxTx, pool.hook("first after rollback"))
+			manager.AfterRollback(ctxTx, func(context.Context) error {
+				pool.record("second after rollback")
+				return errTest
+			})
+			manager.AfterRollback(ctxTx, pool.hook("third after rollback"))
+			return errTest
+		})
+
+		// assert
+		require.ErrorIs(t, err, errTest)
+		assert.Equal(t, []string{
+			"begin", "rollback", "first after rollback", "second after rollback", "third after rollback",
+		}, pool.log())
+	})
+
+	t.Run("failed commit skips after commit hooks and runs after rollback hooks", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		manager, pool := newTestTxManager(RetryPolicy{})
+		pool.failCommits(errTest)
+
+		// act
+		err := manager.RunReadCommitted(context.Background(), func(ctxTx context.Context) error {
+			manager.AfterCommit(ctxTx, pool.hook("after commit"))
+			manager.AfterRollback(ctxTx, pool.hook("after rollback"))
+			return nil
+		})
+
+		// assert
+		require.ErrorIs(t, err, errTest)
+		assert.Equal(t, []string{"begin", "commit failed", "after rollback"}, pool.log())
+	})
+
+	t.Run("after commit outside of a transaction runs immediately", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		manager, pool := newTestTxManager(RetryPolicy{})
+
+		// act
+		manager.AfterCommit(context.Background(), pool.hook("after commit"))
+		manager.AfterCommit(context.Background(), func(context.Context) error { return errTest })
+		pool.record("work")
+
+		// assert
+		assert.Equal(t, []string{"after commit", "work"}, pool.log())
+	})
+
+	t.Run("after rollback outside of a transaction never runs", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		manager, pool := newTestTxManager(RetryPolicy{})
+
+		// act
+		manager.AfterRollback(context.Background(), pool.hook("after rollback"))
+
+		// assert
+		assert.Empty(t, pool.log())
+	})
+}
+
+func TestTxManager_Propagation(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nested run uses a savepoint by default", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		manager, pool := newTestTxManager(RetryPolicy{})
+
+		// act
+		err := manager.RunRepeatableRead(context.Background(), func(ctxTx context.Context) error {
+			outer := manager.GetQueryEngine(ctxTx)
+			return manager.RunReadCommitted(ctxTx, func(ctxNested context.Context) error {
+				assert.NotSame(t, outer, manager.GetQueryEngine(ctxNested))
+				pool.record("nested work")
+				return nil
+			})
+		})
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{"begin", "savepoint", "nested work", "release savepoint", "commit"}, pool.log())
+	})
+
+	t.Run("released savepoint hands its hooks to the parent", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		manager, pool := newTestTxManager(RetryPolicy{})
+
+		// act
+		err := manager.RunRepeatableRead(context.Background(), func(ctxTx context.Context) error {
+			manager.AfterCommit(ctxTx, pool.hook("outer after commit"))
+			nestedErr := manager.RunReadCommitted(ctxTx, func(ctxNested context.Context) error {
+				manager.AfterCommit(ctxNested, pool.hook("nested after commit"))
+				manager.AfterRollback(ctxNested, pool.hook("nested after rollback"))
+				return nil
+			}, pvz_ports.WithPropagation(pvz_ports.PropagationSavepoint))
+			manager.AfterCommit(ctxTx, pool.hook("outer after commit, registered later"))
+			return nestedErr
+		})
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"begin", "savepoint", "release savepoint", "commit",
+			"outer after commit", "nested after commit", "outer after commit, registered later",
+		}, pool.log())
+	})
+
+	t.Run("released savepoint hooks follow the parent rollback", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		manager, pool := newTestTxManager(RetryPolicy{})
+
+		// act
+		err := manager.RunRepeatableRead(context.Background(), func(ctxTx context.Context) error {
+			manager.AfterRollback(ctxTx, pool.hook("outer after rollback"))
+			require.NoError(t, manager.RunReadCommitted(ctxTx, func(ctxNested context.Context) error {
+				manager.AfterCommit(ctxNested, pool.hook("nested after commit"))
+				manager.AfterRollback(ctxNested, pool.hook("nested after rollback"))
+				return nil
+			}))
+			return errTest
+		})
+
+		// assert
+		require.ErrorIs(t, err, errTest)
+		assert.Equal(t, []string{
+			"begin", "savepoint", "release savepoint", "rollback",
+			"outer after rollback", "nested after rollback",
+		}, pool.log())
+	})
+
+	t.Run("rolled back savepoint runs its after rollback hooks and leaves the parent committing", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		manager, pool := newTestTxManager(RetryPolicy{})
+
+		// act
+		err := manager.RunRepeatableRead(context.Background(), func(ctxTx context.Context) error {
+			manager.AfterCommit(ctxTx, pool.hook("outer after commit"))
+			manager.AfterRollback(ctxTx, pool.hook("outer after rollback"))
+			nestedErr := manager.RunReadCommitted(ctxTx, func(ctxNested context.Context) error {
+				manager.AfterCommit(ctxNested, pool.hook("nested after commit"))
+				manager.AfterRollback(ctxNested, pool.hook("nested after rollback"))
+				return errTest
+			})
+			require.ErrorIs(t, nestedErr, errTest)
+			pool.record("outer work")
+			return nil
+		})
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"begin", "savepoint", "rollback to savepoint", "nested after rollback",
+			"outer work", "commit", "outer after commit",
+		}, pool.log())
+	})
+
+	t.Run("join runs nested work in the outer transaction", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		manager, pool := newTestTxManager(RetryPolicy{})
+
+		// act
+		err := manager.RunRepeatableRead(context.Background(), func(ctxTx context.Context) error {
+			outer := manager.GetQueryEngine(ctxTx)
+			nestedErr := manager.RunReadCommitted(ctxTx, func(ctxNested context.Context) error {
+				assert.Same(t, outer, manager.GetQueryEngine(ctxNested))
+				manager.AfterCommit(ctxNested, pool.hook("nested after commit"))
+				manager.AfterRollback(ctxNested, pool.hook("nested after rollback"))
+				return errTest
+			}, pvz_ports.WithPropagation(pvz_ports.PropagationJoin))
+			return nestedErr
+		})
+
+		// assert
+		require.ErrorIs(t, err, errTest)
+		assert.Equal(t, []string{"begin", "rollback", "nested after rollback"}, pool.log())
+	})
+
+	t.Run("requires new commits on its own before the outer transaction", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		manager, pool := newTestTxManager(RetryPolicy{})
+
+		// act
+		err := manager.RunRepeatableRead(context.Background(), func(ctxTx context.Context) error {
+			manager.AfterCommit(ctxTx, pool.hook("outer after commit"))
+			manager.AfterRollback(ctxTx, pool.hook("outer after rollback"))
+			outer := manager.GetQueryEngine(ctxTx)
+			require.NoError(t, manager.RunReadCommitted(ctxTx, func(ctxNested context.Context) error {
+				assert.NotSame(t, outer, manager.GetQueryEngine(ctxNested))
+				manager.AfterCommit(ctxNested, pool.hook("nested after commit"))
+				manager.AfterRollback(ctxNested, pool.hook("nested after rollback"))
+				return nil
+			}, pvz_ports.WithPropagation(pvz_ports.PropagationRequiresNew)))
+			return errTest
+		})
+
+		// assert
+		require.ErrorIs(t, err, errTest)
+		assert.Equal(t, []string{
+			"begin", "begin", "commit", "nested after commit", "rollback", "outer after rollback",
+		}, pool.log())
+	})
+
+	t.Run("requires new rolls back on its own", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		manager, pool := newTestTxManager(RetryPolicy{})
+
+		// act
+		err := manager.RunRepeatableRead(context.Background(), func(ctxTx context.Context) error {
+			manager.AfterCommit(ctxTx, pool.hook("outer after commit"))
+			nestedErr := manager.RunReadCommitted(ctxTx, func(ctxNested context.Context) error {
+				manager.AfterRollback(ctxNested, pool.hook("nested after rollback"))
+				return errTest
+			}, pvz_ports.WithPropagation(pvz_ports.PropagationRequiresNew))
+			require.ErrorIs(t, nestedErr, errTest)
+			return nil
+		})
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"begin", "begin", "rollback", "nested after rollback", "commit", "outer after commit",
+		}, pool.log())
+	})
+}
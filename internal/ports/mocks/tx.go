@@ -126,6 +126,30 @@ func (m *MockTransactionManager) EXPECT() *MockTransactionManagerMockRecorder {
 	return m.recorder
 }
 
+// AfterCommit mocks base method.
+func (m *MockTransactionManager) AfterCommit(ctxTx context.Context, fn pvz_ports.TxHook) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AfterCommit", ctxTx, fn)
+}
+
+// AfterCommit indicates an expected call of AfterCommit.
+func (mr *MockTransactionManagerMockRecorder) AfterCommit(ctxTx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AfterCommit", reflect.TypeOf((*MockTransactionManager)(nil).AfterCommit), ctxTx, fn)
+}
+
+// AfterRollback mocks base method.
+func (m *MockTransactionManager) AfterRollback(ctxTx context.Context, fn pvz_ports.TxHook) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "AfterRollback", ctxTx, fn)
+}
+
+// AfterRollback indicates an expected call of AfterRollback.
+func (mr *MockTransactionManagerMockRecorder) AfterRollback(ctxTx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AfterRollback", reflect.TypeOf((*MockTransactionManager)(nil).AfterRollback), ctxTx, fn)
+}
+
 // GetQueryEngine mocks base method.
 func (m *MockTransactionManager) GetQueryEngine(ctxTx context.Context) pvz_ports.QueryEngine {
 	m.ctrl.T.Helper()
@@ -140,44 +164,92 @@ func (mr *MockTransactionManagerMockRecorder) GetQueryEngine(ctxTx any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetQueryEngine", reflect.TypeOf((*MockTransactionManager)(nil).GetQueryEngine), ctxTx)
 }
 
+// GetReadQueryEngine mocks base method.
+func (m *MockTransactionManager) GetReadQueryEngine(ctx context.Context) pvz_ports.QueryEngine {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReadQueryEngine", ctx)
+	ret0, _ := ret[0].(pvz_ports.QueryEngine)
+	return ret0
+}
+
+// GetReadQueryEngine indicates an expected call of GetReadQueryEngine.
+func (mr *MockTransactionManagerMockRecorder) GetReadQueryEngine(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReadQueryEngine", reflect.TypeOf((*MockTransactionManager)(nil).GetReadQueryEngine), ctx)
+}
+
 // RunReadCommitted mocks base method.
-func (m *MockTransactionManager) RunReadCommitted(fn func(context.Context) error) error {
+func (m *MockTransactionManager) RunReadCommitted(ctx context.Context, fn func(context.Context) error, opts ...pvz_ports.TxOption) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RunReadCommitted", fn)
+	varargs := []any{ctx, fn}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RunReadCommitted", varargs...)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RunReadCommitted indicates an expected call of RunReadCommitted.
-func (mr *MockTransactionManagerMockRecorder) RunReadCommitted(fn any) *gomock.Call {
+func (mr *MockTransactionManagerMockRecorder) RunReadCommitted(ctx, fn any, opts ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx, fn}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunReadCommitted", reflect.TypeOf((*MockTransactionManager)(nil).RunReadCommitted), varargs...)
+}
+
+// RunReadOnly mocks base method.
+func (m *MockTransactionManager) RunReadOnly(ctx context.Context, fn func(context.Context) error, opts ...pvz_ports.TxOption) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx, fn}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RunReadOnly", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RunReadOnly indicates an expected call of RunReadOnly.
+func (mr *MockTransactionManagerMockRecorder) RunReadOnly(ctx, fn any, opts ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunReadCommitted", reflect.TypeOf((*MockTransactionManager)(nil).RunReadCommitted), fn)
+	varargs := append([]any{ctx, fn}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunReadOnly", reflect.TypeOf((*MockTransactionManager)(nil).RunReadOnly), varargs...)
 }
 
 // RunRepeatableRead mocks base method.
-func (m *MockTransactionManager) RunRepeatableRead(fn func(context.Context) error) error {
+func (m *MockTransactionManager) RunRepeatableRead(ctx context.Context, fn func(context.Context) error, opts ...pvz_ports.TxOption) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RunRepeatableRead", fn)
+	varargs := []any{ctx, fn}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RunRepeatableRead", varargs...)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RunRepeatableRead indicates an expected call of RunRepeatableRead.
-func (mr *MockTransactionManagerMockRecorder) RunRepeatableRead(fn any) *gomock.Call {
+func (mr *MockTransactionManagerMockRecorder) RunRepeatableRead(ctx, fn any, opts ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunRepeatableRead", reflect.TypeOf((*MockTransactionManager)(nil).RunRepeatableRead), fn)
+	varargs := append([]any{ctx, fn}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunRepeatableRead", reflect.TypeOf((*MockTransactionManager)(nil).RunRepeatableRead), varargs...)
 }
 
 // RunSerializable mocks base method.
-func (m *MockTransactionManager) RunSerializable(fn func(context.Context) error) error {
+func (m *MockTransactionManager) RunSerializable(ctx context.Context, fn func(context.Context) error, opts ...pvz_ports.TxOption) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "RunSerializable", fn)
+	varargs := []any{ctx, fn}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RunSerializable", varargs...)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // RunSerializable indicates an expected call of RunSerializable.
-func (mr *MockTransactionManagerMockRecorder) RunSerializable(fn any) *gomock.Call {
+func (mr *MockTransactionManagerMockRecorder) RunSerializable(ctx, fn any, opts ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunSerializable", reflect.TypeOf((*MockTransactionManager)(nil).RunSerializable), fn)
+	varargs := append([]any{ctx, fn}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunSerializable", reflect.TypeOf((*MockTransactionManager)(nil).RunSerializable), varargs...)
 }
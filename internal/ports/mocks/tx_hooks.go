@@ -0,0 +1,43 @@
+package mocks
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Staspol216/gh1/internal/ports"
+	"go.uber.org/mock/gomock"
+)
+
+// TxHooks runs the hooks registered through a MockTransactionManager the way
+// TxManager does once the transaction outcome is known, collecting their errors.
+type TxHooks struct {
+	mock *MockTransactionManager
+	errs []error
+}
+
+func NewTxHooks(m *MockTransactionManager) *TxHooks {
+	return &TxHooks{mock: m}
+}
+
+// ExpectCommitted expects a post-commit hook and runs it right away, as if the
+// surrounding transaction had committed.
+func (h *TxHooks) ExpectCommitted() *gomock.Call {
+	return h.mock.EXPECT().AfterCommit(gomock.Any(), gomock.Any()).Do(h.run)
+}
+
+// ExpectRolledBack expects a post-rollback hook and runs it right away, as if the
+// surrounding transaction had been rolled back.
+func (h *TxHooks) ExpectRolledBack() *gomock.Call {
+	return h.mock.EXPECT().AfterRollback(gomock.Any(), gomock.Any()).Do(h.run)
+}
+
+// Err joins the errors returned by the hooks that ran so far.
+func (h *TxHooks) Err() error {
+	return errors.Join(h.errs...)
+}
+
+func (h *TxHooks) run(ctx context.Context, fn pvz_ports.TxHook) {
+	if err := fn(ctx); err != nil {
+		h.errs = append(h.errs, err)
+	}
+}
@@ -15,9 +15,74 @@ type QueryEngine interface {
 	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
 }
 
+// TxHook is work scheduled on a transaction outcome. It runs outside of the
+// transaction, so it must not use the transaction's query engine.
+type TxHook = func(ctx context.Context) error
+
+// Propagation decides what Run* does when ctx already carries a transaction.
+type Propagation int
+
+const (
+	// PropagationSavepoint runs nested work in a SAVEPOINT of the outer transaction,
+	// so it can fail without aborting the outer work. It is the default.
+	PropagationSavepoint Propagation = iota
+	// PropagationJoin runs nested work directly in the outer transaction.
+	PropagationJoin
+	// PropagationRequiresNew runs nested work in an independent transaction that
+	// commits on its own, regardless of the outer outcome.
+	PropagationRequiresNew
+)
+
+type TxOptions struct {
+	Propagation Propagation
+}
+
+type TxOption func(*TxOptions)
+
+func WithPropagation(propagation Propagation) TxOption {
+	return func(o *TxOptions) {
+		o.Propagation = propagation
+	}
+}
+
+func NewTxOptions(opts ...TxOption) TxOptions {
+	var o TxOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+type primaryKey struct{}
+
+// WithPrimary marks ctx so that reads outside a transaction go to the primary even
+// when a replica is configured. Use it for reads that must see the latest writes,
+// e.g. before caching the result.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+func IsPrimaryRequired(ctx context.Context) bool {
+	required, _ := ctx.Value(primaryKey{}).(bool)
+	return required
+}
+
+// TransactionManager runs fn in a transaction bound to ctx: cancelling ctx or
+// hitting its deadline aborts the transaction.
+//
+// Run* nested in a transaction uses a SAVEPOINT of it by default: nested work can
+// fail on its own, but only commits with the outer transaction. Pass
+// WithPropagation(PropagationRequiresNew) for work that must commit independently.
+//
+// RunReadOnly runs fn in a read-only snapshot, on the replica when it is fresh
+// enough. Writes nested in it fail, whatever their propagation.
 type TransactionManager interface {
 	GetQueryEngine(ctxTx context.Context) QueryEngine
-	RunReadCommitted(fn func(ctxTx context.Context) error) error
-	RunRepeatableRead(fn func(ctxTx context.Context) error) error
-	RunSerializable(fn func(ctxTx context.Context) error) error
+	GetReadQueryEngine(ctx context.Context) QueryEngine
+	RunReadOnly(ctx context.Context, fn func(ctxTx context.Context) error, opts ...TxOption) error
+	RunReadCommitted(ctx context.Context, fn func(ctxTx context.Context) error, opts ...TxOption) error
+	RunRepeatableRead(ctx context.Context, fn func(ctxTx context.Context) error, opts ...TxOption) error
+	RunSerializable(ctx context.Context, fn func(ctxTx context.Context) error, opts ...TxOption) error
+	AfterCommit(ctxTx context.Context, fn TxHook)
+	AfterRollback(ctxTx context.Context, fn TxHook)
 }
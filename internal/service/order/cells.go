@@ -0,0 +1,17 @@
+//go:generate mockgen -source=cells.go -destination=mocks/cells.go -package=mocks
+
+package pvz_order_service
+
+import (
+	"context"
+
+	"github.com/Staspol216/gh1/internal/domain/order"
+)
+
+type CellStorage interface {
+	Add(ctx context.Context, cell *pvz_domain.Cell) (*pvz_domain.Cell, error)
+	Allocate(ctx context.Context, pickupPointID int64, size pvz_domain.CellSize, weight float64) (*pvz_domain.Cell, error)
+	Occupy(ctx context.Context, pickupPointID int64, cellID int64, weight float64) (*pvz_domain.Cell, error)
+	Release(ctx context.Context, pickupPointID int64, cellID int64, weight float64) error
+	GetByID(ctx context.Context, pickupPointID int64, cellID int64) (*pvz_domain.Cell, error)
+}
@@ -0,0 +1,116 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: cells.go
+//
+// Generated by this command:
+//
+//	mockgen -source=cells.go -destination=mocks/cells.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	pvz_domain "github.com/Staspol216/gh1/internal/domain/order"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockCellStorage is a mock of CellStorage interface.
+type MockCellStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockCellStorageMockRecorder
+	isgomock struct{}
+}
+
+// MockCellStorageMockRecorder is the mock recorder for MockCellStorage.
+type MockCellStorageMockRecorder struct {
+	mock *MockCellStorage
+}
+
+// NewMockCellStorage creates a new mock instance.
+func NewMockCellStorage(ctrl *gomock.Controller) *MockCellStorage {
+	mock := &MockCellStorage{ctrl: ctrl}
+	mock.recorder = &MockCellStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockCellStorage) EXPECT() *MockCellStorageMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockCellStorage) Add(ctx context.Context, cell *pvz_domain.Cell) (*pvz_domain.Cell, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, cell)
+	ret0, _ := ret[0].(*pvz_domain.Cell)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockCellStorageMockRecorder) Add(ctx, cell any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockCellStorage)(nil).Add), ctx, cell)
+}
+
+// Allocate mocks base method.
+func (m *MockCellStorage) Allocate(ctx context.Context, pickupPointID int64, size pvz_domain.CellSize, weight float64) (*pvz_domain.Cell, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Allocate", ctx, pickupPointID, size, weight)
+	ret0, _ := ret[0].(*pvz_domain.Cell)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Allocate indicates an expected call of Allocate.
+func (mr *MockCellStorageMockRecorder) Allocate(ctx, pickupPointID, size, weight any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Allocate", reflect.TypeOf((*MockCellStorage)(nil).Allocate), ctx, pickupPointID, size, weight)
+}
+
+// GetByID mocks base method.
+func (m *MockCellStorage) GetByID(ctx context.Context, pickupPointID, cellID int64) (*pvz_domain.Cell, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByID", ctx, pickupPointID, cellID)
+	ret0, _ := ret[0].(*pvz_domain.Cell)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByID indicates an expected call of GetByID.
+func (mr *MockCellStorageMockRecorder) GetByID(ctx, pickupPointID, cellID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockCellStorage)(nil).GetByID), ctx, pickupPointID, cellID)
+}
+
+// Occupy mocks base method.
+func (m *MockCellStorage) Occupy(ctx context.Context, pickupPointID, cellID int64, weight float64) (*pvz_domain.Cell, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Occupy", ctx, pickupPointID, cellID, weight)
+	ret0, _ := ret[0].(*pvz_domain.Cell)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Occupy indicates an expected call of Occupy.
+func (mr *MockCellStorageMockRecorder) Occupy(ctx, pickupPointID, cellID, weight any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Occupy", reflect.TypeOf((*MockCellStorage)(nil).Occupy), ctx, pickupPointID, cellID, weight)
+}
+
+// Release mocks base method.
+func (m *MockCellStorage) Release(ctx context.Context, pickupPointID, cellID int64, weight float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, pickupPointID, cellID, weight)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockCellStorageMockRecorder) Release(ctx, pickupPointID, cellID, weight any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockCellStorage)(nil).Release), ctx, pickupPointID, cellID, weight)
+}
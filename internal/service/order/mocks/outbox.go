@@ -56,6 +56,20 @@ func (mr *MockOutboxMockRecorder) AddTask(ctx, task any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTask", reflect.TypeOf((*MockOutbox)(nil).AddTask), ctx, task)
 }
 
+// AddTasks mocks base method.
+func (m *MockOutbox) AddTasks(ctx context.Context, tasks []*order_outbox.OrderOutboxTask) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddTasks", ctx, tasks)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddTasks indicates an expected call of AddTasks.
+func (mr *MockOutboxMockRecorder) AddTasks(ctx, tasks any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddTasks", reflect.TypeOf((*MockOutbox)(nil).AddTasks), ctx, tasks)
+}
+
 // DeleteTask mocks base method.
 func (m *MockOutbox) DeleteTask(ctx context.Context, id int64) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,113 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: pickup_codes.go
+//
+// Generated by this command:
+//
+//	mockgen -source=pickup_codes.go -destination=mocks/pickup_codes.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	pvz_domain "github.com/Staspol216/gh1/internal/domain/order"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockPickupCodeStorage is a mock of PickupCodeStorage interface.
+type MockPickupCodeStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockPickupCodeStorageMockRecorder
+	isgomock struct{}
+}
+
+// MockPickupCodeStorageMockRecorder is the mock recorder for MockPickupCodeStorage.
+type MockPickupCodeStorageMockRecorder struct {
+	mock *MockPickupCodeStorage
+}
+
+// NewMockPickupCodeStorage creates a new mock instance.
+func NewMockPickupCodeStorage(ctrl *gomock.Controller) *MockPickupCodeStorage {
+	mock := &MockPickupCodeStorage{ctrl: ctrl}
+	mock.recorder = &MockPickupCodeStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPickupCodeStorage) EXPECT() *MockPickupCodeStorageMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockPickupCodeStorage) Delete(ctx context.Context, orderId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, orderId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockPickupCodeStorageMockRecorder) Delete(ctx, orderId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPickupCodeStorage)(nil).Delete), ctx, orderId)
+}
+
+// GetForUpdate mocks base method.
+func (m *MockPickupCodeStorage) GetForUpdate(ctx context.Context, orderId int64) (*pvz_domain.PickupCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForUpdate", ctx, orderId)
+	ret0, _ := ret[0].(*pvz_domain.PickupCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForUpdate indicates an expected call of GetForUpdate.
+func (mr *MockPickupCodeStorageMockRecorder) GetForUpdate(ctx, orderId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForUpdate", reflect.TypeOf((*MockPickupCodeStorage)(nil).GetForUpdate), ctx, orderId)
+}
+
+// Save mocks base method.
+func (m *MockPickupCodeStorage) Save(ctx context.Context, code *pvz_domain.PickupCode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockPickupCodeStorageMockRecorder) Save(ctx, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockPickupCodeStorage)(nil).Save), ctx, code)
+}
+
+// SaveBatch mocks base method.
+func (m *MockPickupCodeStorage) SaveBatch(ctx context.Context, codes []*pvz_domain.PickupCode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveBatch", ctx, codes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveBatch indicates an expected call of SaveBatch.
+func (mr *MockPickupCodeStorageMockRecorder) SaveBatch(ctx, codes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveBatch", reflect.TypeOf((*MockPickupCodeStorage)(nil).SaveBatch), ctx, codes)
+}
+
+// UpdateAttempts mocks base method.
+func (m *MockPickupCodeStorage) UpdateAttempts(ctx context.Context, code *pvz_domain.PickupCode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateAttempts", ctx, code)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateAttempts indicates an expected call of UpdateAttempts.
+func (mr *MockPickupCodeStorageMockRecorder) UpdateAttempts(ctx, code any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateAttempts", reflect.TypeOf((*MockPickupCodeStorage)(nil).UpdateAttempts), ctx, code)
+}
@@ -0,0 +1,56 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: status_feed.go
+//
+// Generated by this command:
+//
+//	mockgen -source=status_feed.go -destination=mocks/status_feed.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	pvz_domain "github.com/Staspol216/gh1/internal/domain/order"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockStatusFeed is a mock of StatusFeed interface.
+type MockStatusFeed struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatusFeedMockRecorder
+	isgomock struct{}
+}
+
+// MockStatusFeedMockRecorder is the mock recorder for MockStatusFeed.
+type MockStatusFeedMockRecorder struct {
+	mock *MockStatusFeed
+}
+
+// NewMockStatusFeed creates a new mock instance.
+func NewMockStatusFeed(ctrl *gomock.Controller) *MockStatusFeed {
+	mock := &MockStatusFeed{ctrl: ctrl}
+	mock.recorder = &MockStatusFeedMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatusFeed) EXPECT() *MockStatusFeedMockRecorder {
+	return m.recorder
+}
+
+// Subscribe mocks base method.
+func (m *MockStatusFeed) Subscribe(pickupPointID int64) (<-chan *pvz_domain.OrderStatusChange, func()) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", pickupPointID)
+	ret0, _ := ret[0].(<-chan *pvz_domain.OrderStatusChange)
+	ret1, _ := ret[1].(func())
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockStatusFeedMockRecorder) Subscribe(pickupPointID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockStatusFeed)(nil).Subscribe), pickupPointID)
+}
@@ -57,6 +57,20 @@ func (mr *MockOrderStorageMockRecorder) Add(ctx, newOrder any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockOrderStorage)(nil).Add), ctx, newOrder)
 }
 
+// AddBatch mocks base method.
+func (m *MockOrderStorage) AddBatch(ctx context.Context, newOrders []*pvz_domain.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddBatch", ctx, newOrders)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddBatch indicates an expected call of AddBatch.
+func (mr *MockOrderStorageMockRecorder) AddBatch(ctx, newOrders any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBatch", reflect.TypeOf((*MockOrderStorage)(nil).AddBatch), ctx, newOrders)
+}
+
 // AddHistoryRecord mocks base method.
 func (m *MockOrderStorage) AddHistoryRecord(ctx context.Context, record *pvz_domain.OrderRecord, orderId int64) (int64, error) {
 	m.ctrl.T.Helper()
@@ -72,93 +86,151 @@ func (mr *MockOrderStorageMockRecorder) AddHistoryRecord(ctx, record, orderId an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddHistoryRecord", reflect.TypeOf((*MockOrderStorage)(nil).AddHistoryRecord), ctx, record, orderId)
 }
 
+// AddHistoryRecords mocks base method.
+func (m *MockOrderStorage) AddHistoryRecords(ctx context.Context, records []*pvz_domain.OrderRecord, orderIds []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddHistoryRecords", ctx, records, orderIds)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddHistoryRecords indicates an expected call of AddHistoryRecords.
+func (mr *MockOrderStorageMockRecorder) AddHistoryRecords(ctx, records, orderIds any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddHistoryRecords", reflect.TypeOf((*MockOrderStorage)(nil).AddHistoryRecords), ctx, records, orderIds)
+}
+
 // Delete mocks base method.
-func (m *MockOrderStorage) Delete(ctx context.Context, orderId int64) error {
+func (m *MockOrderStorage) Delete(ctx context.Context, pickupPointID, orderId int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, orderId)
+	ret := m.ctrl.Call(m, "Delete", ctx, pickupPointID, orderId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockOrderStorageMockRecorder) Delete(ctx, orderId any) *gomock.Call {
+func (mr *MockOrderStorageMockRecorder) Delete(ctx, pickupPointID, orderId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockOrderStorage)(nil).Delete), ctx, pickupPointID, orderId)
+}
+
+// Export mocks base method.
+func (m *MockOrderStorage) Export(ctx context.Context, pickupPointID int64, filter *pvz_domain.OrderExportFilter, afterID, limit int64) ([]*pvz_domain.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, pickupPointID, filter, afterID, limit)
+	ret0, _ := ret[0].([]*pvz_domain.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockOrderStorageMockRecorder) Export(ctx, pickupPointID, filter, afterID, limit any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockOrderStorage)(nil).Delete), ctx, orderId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockOrderStorage)(nil).Export), ctx, pickupPointID, filter, afterID, limit)
 }
 
 // GetAll mocks base method.
-func (m *MockOrderStorage) GetAll(ctx context.Context) ([]*pvz_domain.Order, error) {
+func (m *MockOrderStorage) GetAll(ctx context.Context, pickupPointID int64) ([]*pvz_domain.Order, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret := m.ctrl.Call(m, "GetAll", ctx, pickupPointID)
 	ret0, _ := ret[0].([]*pvz_domain.Order)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAll indicates an expected call of GetAll.
-func (mr *MockOrderStorageMockRecorder) GetAll(ctx any) *gomock.Call {
+func (mr *MockOrderStorageMockRecorder) GetAll(ctx, pickupPointID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockOrderStorage)(nil).GetAll), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockOrderStorage)(nil).GetAll), ctx, pickupPointID)
 }
 
 // GetByID mocks base method.
-func (m *MockOrderStorage) GetByID(ctx context.Context, orderId int64) (*pvz_domain.Order, error) {
+func (m *MockOrderStorage) GetByID(ctx context.Context, pickupPointID, orderId int64) (*pvz_domain.Order, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByID", ctx, orderId)
+	ret := m.ctrl.Call(m, "GetByID", ctx, pickupPointID, orderId)
 	ret0, _ := ret[0].(*pvz_domain.Order)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByID indicates an expected call of GetByID.
-func (mr *MockOrderStorageMockRecorder) GetByID(ctx, orderId any) *gomock.Call {
+func (mr *MockOrderStorageMockRecorder) GetByID(ctx, pickupPointID, orderId any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockOrderStorage)(nil).GetByID), ctx, orderId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByID", reflect.TypeOf((*MockOrderStorage)(nil).GetByID), ctx, pickupPointID, orderId)
 }
 
 // GetByIDs mocks base method.
-func (m *MockOrderStorage) GetByIDs(ctx context.Context, orderIds []int64) ([]*pvz_domain.Order, error) {
+func (m *MockOrderStorage) GetByIDs(ctx context.Context, pickupPointID int64, orderIds []int64) ([]*pvz_domain.Order, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetByIDs", ctx, orderIds)
+	ret := m.ctrl.Call(m, "GetByIDs", ctx, pickupPointID, orderIds)
 	ret0, _ := ret[0].([]*pvz_domain.Order)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetByIDs indicates an expected call of GetByIDs.
-func (mr *MockOrderStorageMockRecorder) GetByIDs(ctx, orderIds any) *gomock.Call {
+func (mr *MockOrderStorageMockRecorder) GetByIDs(ctx, pickupPointID, orderIds any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDs", reflect.TypeOf((*MockOrderStorage)(nil).GetByIDs), ctx, orderIds)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByIDs", reflect.TypeOf((*MockOrderStorage)(nil).GetByIDs), ctx, pickupPointID, orderIds)
 }
 
 // GetList mocks base method.
-func (m *MockOrderStorage) GetList(ctx context.Context, pagination *pvz_domain.Pagination) ([]*pvz_domain.Order, error) {
+func (m *MockOrderStorage) GetList(ctx context.Context, pickupPointID int64, pagination *pvz_domain.Pagination) ([]*pvz_domain.Order, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetList", ctx, pagination)
+	ret := m.ctrl.Call(m, "GetList", ctx, pickupPointID, pagination)
 	ret0, _ := ret[0].([]*pvz_domain.Order)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetList indicates an expected call of GetList.
-func (mr *MockOrderStorageMockRecorder) GetList(ctx, pagination any) *gomock.Call {
+func (mr *MockOrderStorageMockRecorder) GetList(ctx, pickupPointID, pagination any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockOrderStorage)(nil).GetList), ctx, pagination)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockOrderStorage)(nil).GetList), ctx, pickupPointID, pagination)
 }
 
 // GetRecipientOrderByID mocks base method.
-func (m *MockOrderStorage) GetRecipientOrderByID(ctx context.Context, id, recipientId int64) (*pvz_domain.Order, error) {
+func (m *MockOrderStorage) GetRecipientOrderByID(ctx context.Context, pickupPointID, id, recipientId int64) (*pvz_domain.Order, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetRecipientOrderByID", ctx, id, recipientId)
+	ret := m.ctrl.Call(m, "GetRecipientOrderByID", ctx, pickupPointID, id, recipientId)
 	ret0, _ := ret[0].(*pvz_domain.Order)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetRecipientOrderByID indicates an expected call of GetRecipientOrderByID.
-func (mr *MockOrderStorageMockRecorder) GetRecipientOrderByID(ctx, id, recipientId any) *gomock.Call {
+func (mr *MockOrderStorageMockRecorder) GetRecipientOrderByID(ctx, pickupPointID, id, recipientId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipientOrderByID", reflect.TypeOf((*MockOrderStorage)(nil).GetRecipientOrderByID), ctx, pickupPointID, id, recipientId)
+}
+
+// GetStatusChanges mocks base method.
+func (m *MockOrderStorage) GetStatusChanges(ctx context.Context, pickupPointID, afterRecordID, limit int64) ([]*pvz_domain.OrderStatusChange, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatusChanges", ctx, pickupPointID, afterRecordID, limit)
+	ret0, _ := ret[0].([]*pvz_domain.OrderStatusChange)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatusChanges indicates an expected call of GetStatusChanges.
+func (mr *MockOrderStorageMockRecorder) GetStatusChanges(ctx, pickupPointID, afterRecordID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatusChanges", reflect.TypeOf((*MockOrderStorage)(nil).GetStatusChanges), ctx, pickupPointID, afterRecordID, limit)
+}
+
+// MoveToPickupPoint mocks base method.
+func (m *MockOrderStorage) MoveToPickupPoint(ctx context.Context, orderId, fromPickupPointID, toPickupPointID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MoveToPickupPoint", ctx, orderId, fromPickupPointID, toPickupPointID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MoveToPickupPoint indicates an expected call of MoveToPickupPoint.
+func (mr *MockOrderStorageMockRecorder) MoveToPickupPoint(ctx, orderId, fromPickupPointID, toPickupPointID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecipientOrderByID", reflect.TypeOf((*MockOrderStorage)(nil).GetRecipientOrderByID), ctx, id, recipientId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MoveToPickupPoint", reflect.TypeOf((*MockOrderStorage)(nil).MoveToPickupPoint), ctx, orderId, fromPickupPointID, toPickupPointID)
 }
 
 // Update mocks base method.
@@ -200,44 +272,145 @@ func (m *MockOrdersCache) EXPECT() *MockOrdersCacheMockRecorder {
 }
 
 // DeleteOrder mocks base method.
-func (m *MockOrdersCache) DeleteOrder(ctx context.Context, orderId int64) error {
+func (m *MockOrdersCache) DeleteOrder(ctx context.Context, pickupPointID, orderId int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteOrder", ctx, orderId)
+	ret := m.ctrl.Call(m, "DeleteOrder", ctx, pickupPointID, orderId)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteOrder indicates an expected call of DeleteOrder.
-func (mr *MockOrdersCacheMockRecorder) DeleteOrder(ctx, orderId any) *gomock.Call {
+func (mr *MockOrdersCacheMockRecorder) DeleteOrder(ctx, pickupPointID, orderId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOrder", reflect.TypeOf((*MockOrdersCache)(nil).DeleteOrder), ctx, pickupPointID, orderId)
+}
+
+// ForgetMissingOrders mocks base method.
+func (m *MockOrdersCache) ForgetMissingOrders(ctx context.Context, pickupPointID int64, ids []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForgetMissingOrders", ctx, pickupPointID, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForgetMissingOrders indicates an expected call of ForgetMissingOrders.
+func (mr *MockOrdersCacheMockRecorder) ForgetMissingOrders(ctx, pickupPointID, ids any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOrder", reflect.TypeOf((*MockOrdersCache)(nil).DeleteOrder), ctx, orderId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForgetMissingOrders", reflect.TypeOf((*MockOrdersCache)(nil).ForgetMissingOrders), ctx, pickupPointID, ids)
 }
 
 // GetOrder mocks base method.
-func (m *MockOrdersCache) GetOrder(ctx context.Context, id any) (*pvz_domain.Order, error) {
+func (m *MockOrdersCache) GetOrder(ctx context.Context, pickupPointID, id, recipientId int64) (*pvz_domain.Order, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOrder", ctx, id)
+	ret := m.ctrl.Call(m, "GetOrder", ctx, pickupPointID, id, recipientId)
 	ret0, _ := ret[0].(*pvz_domain.Order)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetOrder indicates an expected call of GetOrder.
-func (mr *MockOrdersCacheMockRecorder) GetOrder(ctx, id any) *gomock.Call {
+func (mr *MockOrdersCacheMockRecorder) GetOrder(ctx, pickupPointID, id, recipientId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockOrdersCache)(nil).GetOrder), ctx, pickupPointID, id, recipientId)
+}
+
+// GetOrderPage mocks base method.
+func (m *MockOrdersCache) GetOrderPage(ctx context.Context, pickupPointID int64, pagination *pvz_domain.Pagination) ([]*pvz_domain.Order, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderPage", ctx, pickupPointID, pagination)
+	ret0, _ := ret[0].([]*pvz_domain.Order)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetOrderPage indicates an expected call of GetOrderPage.
+func (mr *MockOrdersCacheMockRecorder) GetOrderPage(ctx, pickupPointID, pagination any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderPage", reflect.TypeOf((*MockOrdersCache)(nil).GetOrderPage), ctx, pickupPointID, pagination)
+}
+
+// GetOrders mocks base method.
+func (m *MockOrdersCache) GetOrders(ctx context.Context, pickupPointID int64, ids []int64) (map[int64]*pvz_domain.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrders", ctx, pickupPointID, ids)
+	ret0, _ := ret[0].(map[int64]*pvz_domain.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrders indicates an expected call of GetOrders.
+func (mr *MockOrdersCacheMockRecorder) GetOrders(ctx, pickupPointID, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrders", reflect.TypeOf((*MockOrdersCache)(nil).GetOrders), ctx, pickupPointID, ids)
+}
+
+// InvalidateOrderPages mocks base method.
+func (m *MockOrdersCache) InvalidateOrderPages(ctx context.Context, pickupPointID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InvalidateOrderPages", ctx, pickupPointID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InvalidateOrderPages indicates an expected call of InvalidateOrderPages.
+func (mr *MockOrdersCacheMockRecorder) InvalidateOrderPages(ctx, pickupPointID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockOrdersCache)(nil).GetOrder), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InvalidateOrderPages", reflect.TypeOf((*MockOrdersCache)(nil).InvalidateOrderPages), ctx, pickupPointID)
 }
 
 // SetOrder mocks base method.
-func (m *MockOrdersCache) SetOrder(ctx context.Context, order *pvz_domain.Order, ttl time.Duration) error {
+func (m *MockOrdersCache) SetOrder(ctx context.Context, order *pvz_domain.Order, loadTime time.Duration) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SetOrder", ctx, order, ttl)
+	ret := m.ctrl.Call(m, "SetOrder", ctx, order, loadTime)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // SetOrder indicates an expected call of SetOrder.
-func (mr *MockOrdersCacheMockRecorder) SetOrder(ctx, order, ttl any) *gomock.Call {
+func (mr *MockOrdersCacheMockRecorder) SetOrder(ctx, order, loadTime any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOrder", reflect.TypeOf((*MockOrdersCache)(nil).SetOrder), ctx, order, loadTime)
+}
+
+// SetOrderMissing mocks base method.
+func (m *MockOrdersCache) SetOrderMissing(ctx context.Context, pickupPointID, id, recipientId int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetOrderMissing", ctx, pickupPointID, id, recipientId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetOrderMissing indicates an expected call of SetOrderMissing.
+func (mr *MockOrdersCacheMockRecorder) SetOrderMissing(ctx, pickupPointID, id, recipientId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOrderMissing", reflect.TypeOf((*MockOrdersCache)(nil).SetOrderMissing), ctx, pickupPointID, id, recipientId)
+}
+
+// SetOrderPage mocks base method.
+func (m *MockOrdersCache) SetOrderPage(ctx context.Context, pickupPointID, generation int64, pagination *pvz_domain.Pagination, orders []*pvz_domain.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetOrderPage", ctx, pickupPointID, generation, pagination, orders)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetOrderPage indicates an expected call of SetOrderPage.
+func (mr *MockOrdersCacheMockRecorder) SetOrderPage(ctx, pickupPointID, generation, pagination, orders any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOrderPage", reflect.TypeOf((*MockOrdersCache)(nil).SetOrderPage), ctx, pickupPointID, generation, pagination, orders)
+}
+
+// SetOrders mocks base method.
+func (m *MockOrdersCache) SetOrders(ctx context.Context, orders []*pvz_domain.Order, loadTime time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetOrders", ctx, orders, loadTime)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetOrders indicates an expected call of SetOrders.
+func (mr *MockOrdersCacheMockRecorder) SetOrders(ctx, orders, loadTime any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOrder", reflect.TypeOf((*MockOrdersCache)(nil).SetOrder), ctx, order, ttl)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOrders", reflect.TypeOf((*MockOrdersCache)(nil).SetOrders), ctx, orders, loadTime)
 }
@@ -10,6 +10,7 @@ import (
 
 type Outbox interface {
 	AddTask(ctx context.Context, task *order_outbox.OrderOutboxTask) (int64, error)
+	AddTasks(ctx context.Context, tasks []*order_outbox.OrderOutboxTask) error
 	LockPending(ctx context.Context) ([]order_outbox.OrderOutboxTask, error)
 	MarkTaskAsFailed(ctx context.Context, id int64) error
 	DeleteTasks(ctx context.Context, ids []int64) error
@@ -0,0 +1,17 @@
+//go:generate mockgen -source=pickup_codes.go -destination=mocks/pickup_codes.go -package=mocks
+
+package pvz_order_service
+
+import (
+	"context"
+
+	"github.com/Staspol216/gh1/internal/domain/order"
+)
+
+type PickupCodeStorage interface {
+	Save(ctx context.Context, code *pvz_domain.PickupCode) error
+	SaveBatch(ctx context.Context, codes []*pvz_domain.PickupCode) error
+	GetForUpdate(ctx context.Context, orderId int64) (*pvz_domain.PickupCode, error)
+	UpdateAttempts(ctx context.Context, code *pvz_domain.PickupCode) error
+	Delete(ctx context.Context, orderId int64) error
+}
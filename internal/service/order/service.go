@@ -1,10 +1,12 @@
 package pvz_order_service
 
 import (
+	"cmp"
 	"context"
 	"errors"
 	"fmt"
 	"slices"
+	"strconv"
 	"time"
 
 	"github.com/Staspol216/gh1/internal/domain/order"
@@ -15,26 +17,86 @@ import (
 	"github.com/Staspol216/gh1/pkg/tracing"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// exportBatchSize is how many orders ExportOrders reads per query.
+	exportBatchSize int64 = 100
+	// watchReplayBatchSize is how many status changes WatchOrders replays per query.
+	watchReplayBatchSize int64 = 100
 )
 
 type PvzService struct {
-	outbox    Outbox
-	storage   OrderStorage
-	cache     OrdersCache
-	txManager pvz_ports.TransactionManager
+	outbox           Outbox
+	storage          OrderStorage
+	cells            CellStorage
+	pickupCodes      PickupCodeStorage
+	pickupCodePolicy pvz_domain.PickupCodePolicy
+	cache            OrdersCache
+	statusFeed       StatusFeed
+	txManager        pvz_ports.TransactionManager
+	// orderLoads coalesces concurrent storage lookups of the same order on cache misses.
+	orderLoads singleflight.Group
+}
+
+// AcceptedOrder is the result of intake. PickupCode is only available here in plain
+// form and has to be passed on to the recipient.
+type AcceptedOrder struct {
+	OrderID    int64
+	PickupCode string
+}
+
+// CourierParcel is one order of a courier delivery with the packaging it came in.
+type CourierParcel struct {
+	Order              *pvz_domain.OrderParams
+	PackagingType      string
+	AdditionalMembrana bool
+}
+
+// ParcelIntake is the outcome of one parcel of a courier delivery: the accepted
+// order, or Err if the parcel was rejected.
+type ParcelIntake struct {
+	Accepted *AcceptedOrder
+	Err      error
+}
+
+// receivedParcel is a validated and packaged parcel waiting to be stored.
+type receivedParcel struct {
+	order    *pvz_domain.Order
+	cellSize pvz_domain.CellSize
+}
+
+// ServeRecipientParams describes a handover of orders to a recipient or their refund.
+type ServeRecipientParams struct {
+	OrderIDs    []int64
+	RecipientID int64
+	Action      string
+	// PickupCodes holds the recipient's pickup code per order, required for delivery.
+	PickupCodes map[int64]string
+	// ItemIDs limits the action to some items of an order; orders without an entry are served whole.
+	ItemIDs map[int64][]int64
 }
 
 func NewPvzService(
 	storage OrderStorage,
+	cells CellStorage,
+	pickupCodes PickupCodeStorage,
+	pickupCodePolicy pvz_domain.PickupCodePolicy,
 	outbox Outbox,
 	cache OrdersCache,
+	statusFeed StatusFeed,
 	txManager pvz_ports.TransactionManager,
 ) *PvzService {
 	return &PvzService{
-		outbox,
-		storage,
-		cache,
-		txManager,
+		outbox:           outbox,
+		storage:          storage,
+		cells:            cells,
+		pickupCodes:      pickupCodes,
+		pickupCodePolicy: pickupCodePolicy,
+		cache:            cache,
+		statusFeed:       statusFeed,
+		txManager:        txManager,
 	}
 }
 
@@ -47,10 +109,16 @@ func (s *PvzService) GetOrders(ctx context.Context, pagination *pvz_domain.Pagin
 		tracing.FinishSpan(span, startTime, err)
 	}()
 	defer func() {
-		monitoring.ObserveOrderOperation("get_orders", err)
+		monitoring.ObserveOrderOperation("get_orders", pickupPointLabel(ctx), err)
 	}()
 
-	return s.storage.GetList(ctx, pagination)
+	pickupPointID, err := pvz_domain.PickupPointFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	span.SetTag("pickup_point_id", pickupPointID)
+
+	return s.listOrders(ctx, pickupPointID, pagination)
 }
 
 func (s *PvzService) GetOrderByID(ctx context.Context, orderId int64, recipientId int64) (result *pvz_domain.Order, err error) {
@@ -62,16 +130,30 @@ func (s *PvzService) GetOrderByID(ctx context.Context, orderId int64, recipientI
 		tracing.FinishSpan(span, startTime, err)
 	}()
 	defer func() {
-		monitoring.ObserveOrderOperation("get_order_by_id", err)
+		monitoring.ObserveOrderOperation("get_order_by_id", pickupPointLabel(ctx), err)
 	}()
 
-	order, err := s.cache.GetOrder(ctx, orderId)
-	if err == nil {
+	pickupPointID, err := pvz_domain.PickupPointFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	span.SetTag("pickup_point_id", pickupPointID)
+
+	order, err := s.cache.GetOrder(ctx, pickupPointID, orderId, recipientId)
+	if err == nil && order.RecipientID == recipientId {
 		span.SetTag("cache", "hit")
 		monitoring.ObserveCacheOperation("get_order_hit", nil)
 		return order, nil
 	}
-	if errors.Is(err, redis.Nil) {
+	if err == nil {
+		// A cached order of another recipient must not leak; storage decides what to return.
+		span.SetTag("cache", "foreign")
+		monitoring.ObserveCacheOperation("get_order_foreign", nil)
+	} else if errors.Is(err, pvz_domain.ErrOrderNotFound) {
+		span.SetTag("cache", "negative")
+		monitoring.ObserveCacheOperation("get_order_negative_hit", nil)
+		return nil, err
+	} else if errors.Is(err, redis.Nil) {
 		span.SetTag("cache", "miss")
 		monitoring.ObserveCacheOperation("get_order_miss", nil)
 	} else {
@@ -79,20 +161,64 @@ func (s *PvzService) GetOrderByID(ctx context.Context, orderId int64, recipientI
 		monitoring.ObserveCacheOperation("get_order_error", err)
 	}
 
-	order, err = s.storage.GetRecipientOrderByID(ctx, orderId, recipientId)
+	return s.loadOrder(ctx, pickupPointID, orderId, recipientId)
+}
+
+// loadOrder reads the order from storage and caches the outcome, including that it
+// does not exist. Concurrent loads of the same order share one storage lookup; the
+// lookup outlives a caller that gives up, as others may be waiting for it.
+func (s *PvzService) loadOrder(ctx context.Context, pickupPointID int64, orderId int64, recipientId int64) (*pvz_domain.Order, error) {
+	key := fmt.Sprintf("%d:%d:%d", pickupPointID, orderId, recipientId)
+
+	loaded := false
+	results := s.orderLoads.DoChan(key, func() (interface{}, error) {
+		loaded = true
+		return s.loadOrderFromStorage(context.WithoutCancel(ctx), pickupPointID, orderId, recipientId)
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-results:
+		if loaded {
+			monitoring.ObserveCacheOperation("load_order", result.Err)
+		} else {
+			monitoring.ObserveCacheOperation("load_order_coalesced", result.Err)
+		}
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		return result.Val.(*pvz_domain.Order), nil
+	}
+}
+
+func (s *PvzService) loadOrderFromStorage(ctx context.Context, pickupPointID int64, orderId int64, recipientId int64) (*pvz_domain.Order, error) {
+	startTime := time.Now()
+
+	order, err := s.storage.GetRecipientOrderByID(readForCache(ctx), pickupPointID, orderId, recipientId)
+	if errors.Is(err, pvz_domain.ErrOrderNotFound) {
+		cacheErr := s.cache.SetOrderMissing(ctx, pickupPointID, orderId, recipientId)
+		if cacheFailed(cacheErr) {
+			app_logger.MyLogger.Warn("failed to cache missing order",
+				zap.Int64("order_id", orderId),
+				zap.Error(cacheErr),
+			)
+		}
+		monitoring.ObserveCacheOperation("set_order_missing", cacheErr)
+		return nil, err
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	if cacheErr := s.cache.SetOrder(ctx, order, 0); cacheErr != nil {
+	cacheErr := s.cache.SetOrder(ctx, order, time.Since(startTime))
+	if cacheFailed(cacheErr) {
 		app_logger.MyLogger.Warn("failed to cache order after storage lookup",
 			zap.Int64("order_id", order.ID),
 			zap.Error(cacheErr),
 		)
-		monitoring.ObserveCacheOperation("set_order", cacheErr)
-	} else {
-		monitoring.ObserveCacheOperation("set_order", nil)
 	}
+	monitoring.ObserveCacheOperation("set_order", cacheErr)
 
 	return order, nil
 }
@@ -105,19 +231,70 @@ func (s *PvzService) GetOrdersByIDs(ctx context.Context, ordersIds []int64) (ord
 		tracing.FinishSpan(span, startTime, err)
 	}()
 	defer func() {
-		monitoring.ObserveOrderOperation("get_orders_by_ids", err)
+		monitoring.ObserveOrderOperation("get_orders_by_ids", pickupPointLabel(ctx), err)
 	}()
 
-	orders, err = s.storage.GetByIDs(ctx, ordersIds)
-
+	pickupPointID, err := pvz_domain.PickupPointFromContext(ctx)
 	if err != nil {
 		return nil, err
 	}
+	span.SetTag("pickup_point_id", pickupPointID)
+
+	cached, err := s.cache.GetOrders(ctx, pickupPointID, ordersIds)
+	if err != nil {
+		monitoring.ObserveCacheOperation("get_orders_error", err)
+		cached = map[int64]*pvz_domain.Order{}
+	}
+
+	missing := make([]int64, 0, len(ordersIds))
+	for _, id := range ordersIds {
+		if order, ok := cached[id]; ok {
+			orders = append(orders, order)
+			monitoring.ObserveCacheOperation("get_orders_hit", nil)
+			continue
+		}
+		if !slices.Contains(missing, id) {
+			missing = append(missing, id)
+			monitoring.ObserveCacheOperation("get_orders_miss", nil)
+		}
+	}
+	span.SetTag("cache_misses", len(missing))
+
+	if len(missing) > 0 {
+		var loaded []*pvz_domain.Order
+		loadStart := time.Now()
+		txError := s.txManager.RunReadOnly(readForCache(ctx), func(ctxTx context.Context) error {
+			var err error
+			loaded, err = s.storage.GetByIDs(ctxTx, pickupPointID, missing)
+			return err
+		})
+		if txError != nil {
+			return nil, txError
+		}
+
+		cacheErr := s.cache.SetOrders(ctx, loaded, time.Since(loadStart))
+		if cacheFailed(cacheErr) {
+			app_logger.MyLogger.Warn("failed to cache orders after storage lookup",
+				zap.Int("orders_count", len(loaded)),
+				zap.Error(cacheErr),
+			)
+		}
+		monitoring.ObserveCacheOperation("set_orders", cacheErr)
+
+		orders = append(orders, loaded...)
+	}
+
+	slices.SortFunc(orders, func(a *pvz_domain.Order, b *pvz_domain.Order) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	orders = slices.CompactFunc(orders, func(a *pvz_domain.Order, b *pvz_domain.Order) bool {
+		return a.ID == b.ID
+	})
 
 	return orders, nil
 }
 
-func (s *PvzService) AcceptFromCourier(ctx context.Context, payload *pvz_domain.OrderParams, packagingType string, additionalMembrana bool) (orderID *int64, err error) {
+func (s *PvzService) AcceptFromCourier(ctx context.Context, payload *pvz_domain.OrderParams, packagingType string, additionalMembrana bool) (accepted *AcceptedOrder, err error) {
 	startTime := time.Now()
 	span, ctx := tracing.StartSpanFromContext(ctx, "OrderService.AcceptFromCourier")
 	span.SetTag("packaging_type", packagingType)
@@ -126,24 +303,41 @@ func (s *PvzService) AcceptFromCourier(ctx context.Context, payload *pvz_domain.
 		span.SetTag("recipient_id", payload.RecipientId)
 	}
 	defer func() {
-		if orderID != nil {
-			span.SetTag("order_id", *orderID)
+		if accepted != nil {
+			span.SetTag("order_id", accepted.OrderID)
 		}
 		tracing.FinishSpan(span, startTime, err)
 	}()
 	defer func() {
-		monitoring.ObserveOrderOperation("accept_from_courier", err)
+		monitoring.ObserveOrderOperation("accept_from_courier", pickupPointLabel(ctx), err)
 	}()
 
+	pickupPointID, err := pvz_domain.PickupPointFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	span.SetTag("pickup_point_id", pickupPointID)
+
+	if err := payload.ValidateForIntake(time.Now()); err != nil {
+		return nil, err
+	}
+
 	var order *pvz_domain.Order
+	var pickupCode string
+
+	txError := s.txManager.RunReadCommitted(ctx, func(ctxTx context.Context) error {
+		result, err := s.ProcessOrderReceive(ctxTx, pickupPointID, payload, packagingType, additionalMembrana)
+		if err != nil {
+			return err
+		}
 
-	txError := s.txManager.RunReadCommitted(func(ctxTx context.Context) error {
-		result, err := s.ProcessOrderReceive(ctxTx, payload, packagingType, additionalMembrana)
+		code, err := s.issuePickupCode(ctxTx, result.ID)
 		if err != nil {
 			return err
 		}
 
 		order = result
+		pickupCode = code
 
 		return nil
 	})
@@ -152,51 +346,200 @@ func (s *PvzService) AcceptFromCourier(ctx context.Context, payload *pvz_domain.
 		return nil, txError
 	}
 
-	if err := s.cache.SetOrder(ctx, order, 0); err != nil {
-		monitoring.ObserveCacheOperation("set_order", err)
+	return &AcceptedOrder{OrderID: order.ID, PickupCode: pickupCode}, nil
+}
+
+// AcceptBatchFromCourier accepts a whole courier delivery in one transaction. Every
+// parcel is validated and packaged first; rejected parcels are reported in their
+// result and do not hold up the others. Results follow the order of parcels.
+//
+// Storing is all-or-nothing: the accepted parcels are written with one statement
+// per table, so a storage failure rolls back the whole delivery and is returned as
+// the error, with no order stored. The courier can resend the delivery as is.
+func (s *PvzService) AcceptBatchFromCourier(ctx context.Context, parcels []*CourierParcel) (results []*ParcelIntake, err error) {
+	startTime := time.Now()
+	span, ctx := tracing.StartSpanFromContext(ctx, "OrderService.AcceptBatchFromCourier")
+	span.SetTag("parcels_count", len(parcels))
+	defer func() {
+		tracing.FinishSpan(span, startTime, err)
+	}()
+	defer func() {
+		monitoring.ObserveOrderOperation("accept_batch_from_courier", pickupPointLabel(ctx), err)
+	}()
+
+	pickupPointID, err := pvz_domain.PickupPointFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	span.SetTag("pickup_point_id", pickupPointID)
+
+	if err := pvz_domain.ValidateIntakeBatchSize(len(parcels)); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	results = make([]*ParcelIntake, len(parcels))
+	received := make([]*receivedParcel, 0, len(parcels))
+	indexes := make([]int, 0, len(parcels))
+	for i, parcel := range parcels {
+		r, err := receiveParcel(pickupPointID, parcel, now)
+		if err != nil {
+			results[i] = &ParcelIntake{Err: err}
+			continue
+		}
+		received = append(received, r)
+		indexes = append(indexes, i)
+	}
+	span.SetTag("rejected_count", len(parcels)-len(received))
+
+	if len(received) == 0 {
+		return results, nil
+	}
+
+	var pickupCodes []string
+	txError := s.txManager.RunReadCommitted(ctx, func(ctxTx context.Context) error {
+		var err error
+		pickupCodes, err = s.processBatchReceive(ctxTx, pickupPointID, received)
+		return err
+	})
+	if txError != nil {
+		return nil, txError
+	}
+
+	for j, r := range received {
+		results[indexes[j]] = &ParcelIntake{
+			Accepted: &AcceptedOrder{OrderID: r.order.ID, PickupCode: pickupCodes[j]},
+		}
+	}
+
+	return results, nil
+}
+
+func receiveParcel(pickupPointID int64, parcel *CourierParcel, now time.Time) (*receivedParcel, error) {
+	if parcel == nil {
+		return nil, fmt.Errorf("%w: parcel is required", pvz_domain.ErrInvalidOrderParams)
+	}
+	if err := parcel.Order.ValidateForIntake(now); err != nil {
+		return nil, err
+	}
+
+	order := pvz_domain.NewOrder(pickupPointID, parcel.Order)
+	if err := order.AddItems(parcel.Order.Items); err != nil {
+		return nil, err
+	}
+	if err := order.ApplyPackaging(parcel.PackagingType, parcel.AdditionalMembrana); err != nil {
+		return nil, err
+	}
+	order.Received()
+
+	return &receivedParcel{
+		order:    order,
+		cellSize: pvz_domain.RequiredCellSize(parcel.PackagingType, order.Weight),
+	}, nil
+}
+
+// processBatchReceive shelves and stores received parcels with one statement per
+// table and returns their pickup codes in the same order.
+func (s *PvzService) processBatchReceive(ctxTx context.Context, pickupPointID int64, received []*receivedParcel) ([]string, error) {
+	orders := make([]*pvz_domain.Order, 0, len(received))
+	for _, r := range received {
+		if err := s.allocateCell(ctxTx, r.order, r.cellSize); err != nil {
+			return nil, err
+		}
+		orders = append(orders, r.order)
+	}
+
+	if err := s.storage.AddBatch(ctxTx, orders); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	records := make([]*pvz_domain.OrderRecord, 0, len(orders))
+	orderIds := make([]int64, 0, len(orders))
+	tasks := make([]*order_outbox.OrderOutboxTask, 0, len(orders))
+	storedCodes := make([]*pvz_domain.PickupCode, 0, len(orders))
+	pickupCodes := make([]string, 0, len(orders))
+	for _, order := range orders {
+		record := pvz_domain.NewOrderRecordReceived(pickupPointID)
+		records = append(records, record)
+		orderIds = append(orderIds, order.ID)
+
+		task := &order_outbox.OrderOutboxTask{
+			Status:    order_outbox.Created,
+			CreatedAt: now,
+		}
+		task.SetOrderStatusDetails(order.ID, record)
+		tasks = append(tasks, task)
+
+		stored, code, err := s.pickupCodePolicy.Issue(order.ID, now)
+		if err != nil {
+			return nil, err
+		}
+		storedCodes = append(storedCodes, stored)
+		pickupCodes = append(pickupCodes, code)
+	}
+
+	if err := s.storage.AddHistoryRecords(ctxTx, records, orderIds); err != nil {
+		return nil, err
+	}
+
+	if err := s.outbox.AddTasks(ctxTx, tasks); err != nil {
+		return nil, err
+	}
+
+	if err := s.pickupCodes.SaveBatch(ctxTx, storedCodes); err != nil {
 		return nil, err
 	}
-	monitoring.ObserveCacheOperation("set_order", nil)
 
-	return &order.ID, txError
+	s.invalidateAddedOrdersAfterCommit(ctxTx, pickupPointID, orderIds)
+
+	return pickupCodes, nil
 }
 
-func (s *PvzService) ProcessOrderReceive(ctxTx context.Context, payload *pvz_domain.OrderParams, packagingType string, additionalMembrana bool) (*pvz_domain.Order, error) {
-	newOrder := pvz_domain.NewOrder(payload)
+func (s *PvzService) ProcessOrderReceive(ctxTx context.Context, pickupPointID int64, payload *pvz_domain.OrderParams, packagingType string, additionalMembrana bool) (*pvz_domain.Order, error) {
+	newOrder := pvz_domain.NewOrder(pickupPointID, payload)
+	if err := newOrder.AddItems(payload.Items); err != nil {
+		return nil, err
+	}
 	if err := newOrder.ApplyPackaging(packagingType, additionalMembrana); err != nil {
 		return nil, err
 	}
 
 	newOrder.Received()
 
+	if err := s.allocateCell(ctxTx, newOrder, pvz_domain.RequiredCellSize(packagingType, newOrder.Weight)); err != nil {
+		return nil, err
+	}
+
 	id, err := s.storage.Add(ctxTx, newOrder)
 	if err != nil {
 		return nil, err
 	}
 
-	orderRecord := pvz_domain.NewOrderRecordReceived()
+	orderRecord := pvz_domain.NewOrderRecordReceived(pickupPointID)
 
 	if _, er := s.storage.AddHistoryRecord(ctxTx, orderRecord, id); er != nil {
 		return nil, er
 	}
 
-	result, err := s.storage.GetByID(ctxTx, id)
+	result, err := s.storage.GetByID(ctxTx, pickupPointID, id)
 	if err != nil {
 		return nil, err
 	}
 
 	task := &order_outbox.OrderOutboxTask{
-		Status:      order_outbox.Created,
-		CreatedAt:   time.Now(),
-		OrderStatus: orderRecord.Status,
-		Description: orderRecord.Description,
-		Timestamp:   orderRecord.Timestamp,
+		Status:    order_outbox.Created,
+		CreatedAt: time.Now(),
 	}
+	task.SetOrderStatusDetails(id, orderRecord)
 
 	_, outboxErr := s.outbox.AddTask(ctxTx, task)
 	if outboxErr != nil {
 		return nil, outboxErr
 	}
+
+	s.invalidateAddedOrdersAfterCommit(ctxTx, pickupPointID, []int64{id})
+
 	return result, nil
 }
 
@@ -208,59 +551,75 @@ func (s *PvzService) ReturnToCourier(ctx context.Context, orderId int64) (err er
 		tracing.FinishSpan(span, startTime, err)
 	}()
 	defer func() {
-		monitoring.ObserveOrderOperation("return_to_courier", err)
+		monitoring.ObserveOrderOperation("return_to_courier", pickupPointLabel(ctx), err)
 	}()
 
-	txError := s.txManager.RunRepeatableRead(func(ctxTx context.Context) error {
-		order, err := s.storage.GetByID(ctxTx, orderId)
+	pickupPointID, err := pvz_domain.PickupPointFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	span.SetTag("pickup_point_id", pickupPointID)
+
+	txError := s.txManager.RunRepeatableRead(ctx, func(ctxTx context.Context) error {
+		order, err := s.storage.GetByID(ctxTx, pickupPointID, orderId)
 
 		if err != nil {
 			return err
 		}
 
+		if err := checkExpectedVersion(ctxTx, order); err != nil {
+			return err
+		}
+
 		if !order.IsExpired() {
-			return errors.New("order cannot be returned to courier as it's not expired")
+			return fmt.Errorf("%w: order %d can't be returned to courier", pvz_domain.ErrOrderNotExpired, order.ID)
+		}
+
+		if errRelease := s.releaseCell(ctxTx, order); errRelease != nil {
+			return errRelease
 		}
 
-		if errDel := s.storage.Delete(ctxTx, orderId); errDel != nil {
+		if errDel := s.storage.Delete(ctxTx, pickupPointID, orderId); errDel != nil {
 			return errDel
 		}
 
+		s.invalidateOrderAfterCommit(ctxTx, pickupPointID, orderId)
+
 		return nil
 	})
 
-	if txError == nil {
-		if err := s.cache.DeleteOrder(ctx, orderId); err != nil {
-			monitoring.ObserveCacheOperation("delete_order", err)
-			return err
-		}
-		monitoring.ObserveCacheOperation("delete_order", nil)
-	}
-
 	return txError
 }
 
-func (s *PvzService) ServeRecipient(ctx context.Context, ordersIds []int64, recipientId int64, action string) (err error) {
+// ServeRecipient hands orders over to the recipient or takes them back. Delivery
+// requires the pickup code of every order.
+func (s *PvzService) ServeRecipient(ctx context.Context, params *ServeRecipientParams) (err error) {
 	startTime := time.Now()
 	span, ctx := tracing.StartSpanFromContext(ctx, "OrderService.ServeRecipient")
-	span.SetTag("orders_count", len(ordersIds))
-	span.SetTag("recipient_id", recipientId)
-	span.SetTag("action", action)
+	span.SetTag("orders_count", len(params.OrderIDs))
+	span.SetTag("recipient_id", params.RecipientID)
+	span.SetTag("action", params.Action)
 	defer func() {
 		tracing.FinishSpan(span, startTime, err)
 	}()
 	defer func() {
-		monitoring.ObserveOrderOperation("serve_recipient", err)
+		monitoring.ObserveOrderOperation("serve_recipient", pickupPointLabel(ctx), err)
 	}()
 
-	switch action {
+	// Every order commits on its own, so an expected version is only accepted for one
+	// order: a batch would stop at the first mismatch with earlier orders already served.
+	if _, ok := pvz_domain.ExpectedVersionFromContext(ctx); ok && len(params.OrderIDs) > 1 {
+		return fmt.Errorf("%w: an expected version applies to a single order, got %d", pvz_domain.ErrInvalidOrderParams, len(params.OrderIDs))
+	}
+
+	switch params.Action {
 	case Deliver.String():
-		err := s.DeliverOrders(ctx, ordersIds, recipientId)
+		err := s.DeliverOrders(ctx, params.OrderIDs, params.RecipientID, params.PickupCodes, params.ItemIDs)
 		if err != nil {
 			return err
 		}
 	case Refund.String():
-		err := s.RefundOrders(ctx, ordersIds, recipientId)
+		err := s.RefundOrders(ctx, params.OrderIDs, params.RecipientID, params.ItemIDs)
 		if err != nil {
 			return err
 		}
@@ -271,7 +630,7 @@ func (s *PvzService) ServeRecipient(ctx context.Context, ordersIds []int64, reci
 	return nil
 }
 
-func (s *PvzService) RefundOrders(ctx context.Context, ordersIds []int64, recipientId int64) (err error) {
+func (s *PvzService) RefundOrders(ctx context.Context, ordersIds []int64, recipientId int64, itemIds map[int64][]int64) (err error) {
 	startTime := time.Now()
 	span, ctx := tracing.StartSpanFromContext(ctx, "OrderService.RefundOrders")
 	span.SetTag("orders_count", len(ordersIds))
@@ -280,53 +639,48 @@ func (s *PvzService) RefundOrders(ctx context.Context, ordersIds []int64, recipi
 		tracing.FinishSpan(span, startTime, err)
 	}()
 	defer func() {
-		monitoring.ObserveOrderOperation("refund_orders", err)
+		monitoring.ObserveOrderOperation("refund_orders", pickupPointLabel(ctx), err)
 	}()
 
-	for _, orderId := range ordersIds {
-
-		var updatedOrder *pvz_domain.Order
-
-		txError := s.txManager.RunRepeatableRead(func(ctxTx context.Context) error {
-
-			order, err := s.ProcessOrderRefund(ctxTx, orderId, recipientId)
-			if err != nil {
-				return err
-			}
+	pickupPointID, err := pvz_domain.PickupPointFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	span.SetTag("pickup_point_id", pickupPointID)
 
-			updatedOrder = order
+	for _, orderId := range ordersIds {
 
-			return nil
+		txError := s.txManager.RunRepeatableRead(ctx, func(ctxTx context.Context) error {
+			_, err := s.ProcessOrderRefund(ctxTx, pickupPointID, orderId, recipientId, itemIds[orderId])
+			return err
 		})
 
 		if txError != nil {
 			return txError
 		}
-
-		if err := s.cache.SetOrder(ctx, updatedOrder, 0); err != nil {
-			monitoring.ObserveCacheOperation("set_order", err)
-			return err
-		}
-		monitoring.ObserveCacheOperation("set_order", nil)
 	}
 
 	return nil
 }
 
-func (s *PvzService) ProcessOrderRefund(ctx context.Context, orderId int64, recipientId int64) (*pvz_domain.Order, error) {
-	order, err := s.storage.GetRecipientOrderByID(ctx, orderId, recipientId)
+// ProcessOrderRefund takes back the given items of the order, or the whole order
+// when itemIds is empty.
+func (s *PvzService) ProcessOrderRefund(ctx context.Context, pickupPointID int64, orderId int64, recipientId int64, itemIds []int64) (*pvz_domain.Order, error) {
+	order, err := s.storage.GetRecipientOrderByID(ctx, pickupPointID, orderId, recipientId)
 	if err != nil {
 		return nil, err
 	}
 
-	if !order.CanBeRefunded() {
-		return nil, fmt.Errorf(`order %d can not be refunded to recipient because refund time has expired or it has already refunded by recipient`, order.ID)
+	if err := checkExpectedVersion(ctx, order); err != nil {
+		return nil, err
 	}
 
-	order.Refund()
+	if !order.CanBeRefunded() {
+		return nil, fmt.Errorf("%w: order %d", pvz_domain.ErrOrderNotRefundable, order.ID)
+	}
 
-	orderRecord := pvz_domain.NewOrderRecordRefunded()
-	if _, err := s.storage.AddHistoryRecord(ctx, orderRecord, order.ID); err != nil {
+	items, err := order.RefundItems(itemIds)
+	if err != nil {
 		return nil, err
 	}
 
@@ -334,22 +688,16 @@ func (s *PvzService) ProcessOrderRefund(ctx context.Context, orderId int64, reci
 		return nil, err
 	}
 
-	job := &order_outbox.OrderOutboxTask{
-		Status:      order_outbox.Created,
-		CreatedAt:   time.Now(),
-		OrderStatus: orderRecord.Status,
-		Description: orderRecord.Description,
-		Timestamp:   orderRecord.Timestamp,
+	if err := s.recordItemsStatusChange(ctx, pickupPointID, order, items); err != nil {
+		return nil, err
 	}
 
-	_, outboxErr := s.outbox.AddTask(ctx, job)
-	if outboxErr != nil {
-		return nil, outboxErr
-	}
+	s.invalidateOrderAfterCommit(ctx, pickupPointID, order.ID)
+
 	return order, nil
 }
 
-func (s *PvzService) DeliverOrders(ctx context.Context, ordersIds []int64, recipientId int64) (err error) {
+func (s *PvzService) DeliverOrders(ctx context.Context, ordersIds []int64, recipientId int64, pickupCodes map[int64]string, itemIds map[int64][]int64) (err error) {
 	startTime := time.Now()
 	span, ctx := tracing.StartSpanFromContext(ctx, "OrderService.DeliverOrders")
 	span.SetTag("orders_count", len(ordersIds))
@@ -358,47 +706,48 @@ func (s *PvzService) DeliverOrders(ctx context.Context, ordersIds []int64, recip
 		tracing.FinishSpan(span, startTime, err)
 	}()
 	defer func() {
-		monitoring.ObserveOrderOperation("deliver_orders", err)
+		monitoring.ObserveOrderOperation("deliver_orders", pickupPointLabel(ctx), err)
 	}()
 
-	for _, orderId := range ordersIds {
-
-		var updatedOrder *pvz_domain.Order
-
-		txError := s.txManager.RunRepeatableRead(func(ctxTx context.Context) error {
-
-			order, err2 := s.ProcessOrderDeliver(ctxTx, orderId, recipientId)
-			if err2 != nil {
-				return err2
-			}
+	pickupPointID, err := pvz_domain.PickupPointFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	span.SetTag("pickup_point_id", pickupPointID)
 
-			updatedOrder = order
+	for _, orderId := range ordersIds {
 
-			return nil
+		txError := s.txManager.RunRepeatableRead(ctx, func(ctxTx context.Context) error {
+			_, err := s.ProcessOrderDeliver(ctxTx, pickupPointID, orderId, recipientId, pickupCodes[orderId], itemIds[orderId])
+			return err
 		})
 
 		if txError != nil {
 			return txError
 		}
-
-		if err := s.cache.SetOrder(ctx, updatedOrder, 0); err != nil {
-			monitoring.ObserveCacheOperation("set_order", err)
-			return err
-		}
-		monitoring.ObserveCacheOperation("set_order", nil)
 	}
 
 	return nil
 }
 
-func (s *PvzService) ProcessOrderDeliver(ctxTx context.Context, orderId int64, recipientId int64) (*pvz_domain.Order, error) {
-	order, err := s.storage.GetRecipientOrderByID(ctxTx, orderId, recipientId)
+// ProcessOrderDeliver hands the given items of the order over to the recipient, or
+// the whole order when itemIds is empty, once pickupCode is verified.
+func (s *PvzService) ProcessOrderDeliver(ctxTx context.Context, pickupPointID int64, orderId int64, recipientId int64, pickupCode string, itemIds []int64) (*pvz_domain.Order, error) {
+	order, err := s.storage.GetRecipientOrderByID(ctxTx, pickupPointID, orderId, recipientId)
 	if err != nil {
 		return nil, err
 	}
 
-	if !order.IsReceived() {
-		return nil, fmt.Errorf("order %d must be received from courier", order.ID)
+	if err := checkExpectedVersion(ctxTx, order); err != nil {
+		return nil, err
+	}
+
+	if !order.CanBeDelivered() {
+		return nil, fmt.Errorf("%w: order %d must be received from courier to be delivered", pvz_domain.ErrOrderNotReceived, order.ID)
+	}
+
+	if err := s.verifyPickupCode(ctxTx, pickupPointID, order.ID, pickupCode); err != nil {
+		return nil, err
 	}
 
 	if order.IsExpired() {
@@ -407,7 +756,7 @@ func (s *PvzService) ProcessOrderDeliver(ctxTx context.Context, orderId int64, r
 			return nil, err
 		}
 
-		orderRecord := pvz_domain.NewOrderRecordExpired()
+		orderRecord := pvz_domain.NewOrderRecordExpired(pickupPointID)
 
 		_, err := s.storage.AddHistoryRecord(ctxTx, orderRecord, order.ID)
 		if err != nil {
@@ -415,89 +764,192 @@ func (s *PvzService) ProcessOrderDeliver(ctxTx context.Context, orderId int64, r
 		}
 
 		task := &order_outbox.OrderOutboxTask{
-			Status:      order_outbox.Created,
-			CreatedAt:   time.Now(),
-			OrderStatus: orderRecord.Status,
-			Description: orderRecord.Description,
-			Timestamp:   orderRecord.Timestamp,
+			Status:    order_outbox.Created,
+			CreatedAt: time.Now(),
 		}
+		task.SetOrderStatusDetails(order.ID, orderRecord)
 
 		_, outboxErr := s.outbox.AddTask(ctxTx, task)
 		if outboxErr != nil {
 			return nil, outboxErr
 		}
 
+		s.invalidateOrderAfterCommit(ctxTx, pickupPointID, order.ID)
+
 		return order, nil
 	}
 
-	order.Deliver()
-	if err := s.storage.Update(ctxTx, order); err != nil {
+	items, err := order.DeliverItems(itemIds)
+	if err != nil {
 		return nil, err
 	}
 
-	orderRecord := pvz_domain.NewOrderRecordDelivered()
-
-	if _, err := s.storage.AddHistoryRecord(ctxTx, orderRecord, order.ID); err != nil {
+	// The code is used up by the handover. Remaining items stay in the cell and are
+	// picked up with a new code from RegeneratePickupCode.
+	if err := s.pickupCodes.Delete(ctxTx, order.ID); err != nil {
 		return nil, err
 	}
-
-	task := &order_outbox.OrderOutboxTask{
-		Status:      order_outbox.Created,
-		CreatedAt:   time.Now(),
-		OrderStatus: orderRecord.Status,
-		Description: orderRecord.Description,
-		Timestamp:   orderRecord.Timestamp,
+	if !order.HasItemsToDeliver() {
+		if err := s.releaseCell(ctxTx, order); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.storage.Update(ctxTx, order); err != nil {
+		return nil, err
 	}
 
-	_, outboxErr := s.outbox.AddTask(ctxTx, task)
-	if outboxErr != nil {
-		return nil, outboxErr
+	if err := s.recordItemsStatusChange(ctxTx, pickupPointID, order, items); err != nil {
+		return nil, err
 	}
 
+	s.invalidateOrderAfterCommit(ctxTx, pickupPointID, order.ID)
+
 	return order, nil
 }
 
-func (s *PvzService) GetAllRefunds(ctx context.Context, pagination *pvz_domain.Pagination) (result []*pvz_domain.Order, err error) {
-	startTime := time.Now()
-	span, ctx := tracing.StartSpanFromContext(ctx, "OrderService.GetAllRefunds")
-	span.SetTag("offset", pagination.Offset)
-	span.SetTag("limit", pagination.Limit)
-	defer func() {
-		tracing.FinishSpan(span, startTime, err)
-	}()
-	defer func() {
-		monitoring.ObserveOrderOperation("get_all_refunds", err)
-	}()
-
-	orders, err := s.storage.GetList(ctx, pagination)
+// verifyPickupCode checks the recipient's code on the locked code row, so that the
+// code cannot be used by two deliveries at once. A failed attempt is counted in a
+// transaction of its own once the delivery is rolled back.
+func (s *PvzService) verifyPickupCode(ctxTx context.Context, pickupPointID int64, orderId int64, code string) error {
+	stored, err := s.pickupCodes.GetForUpdate(ctxTx, orderId)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	var refundedOrders []*pvz_domain.Order
+	verifyErr := s.pickupCodePolicy.Verify(stored, code, time.Now())
+	if verifyErr == nil {
+		return nil
+	}
 
-	for _, order := range orders {
-		if order.IsRefunded() {
-			refundedOrders = append(refundedOrders, order)
-		}
+	if errors.Is(verifyErr, pvz_domain.ErrPickupCodeInvalid) {
+		s.txManager.AfterRollback(ctxTx, func(ctx context.Context) error {
+			return s.countFailedPickupAttempt(ctx, orderId, code)
+		})
 	}
 
-	return refundedOrders, nil
+	app_logger.MyLogger.Warn("pickup code verification failed",
+		zap.Int64("pickup_point_id", pickupPointID),
+		zap.Int64("order_id", orderId),
+		zap.Error(verifyErr),
+	)
+	return fmt.Errorf("order %d: %w", orderId, verifyErr)
 }
 
-func (s *PvzService) GetHistory(ctx context.Context, pagination *pvz_domain.Pagination) (result []*pvz_domain.Order, err error) {
+// countFailedPickupAttempt verifies code again on the locked row and stores the
+// failed attempt, which may lock the code out. The attempt is committed on its own,
+// even when ctx carries a transaction.
+func (s *PvzService) countFailedPickupAttempt(ctx context.Context, orderId int64, code string) error {
+	return s.txManager.RunReadCommitted(ctx, func(ctxTx context.Context) error {
+		stored, err := s.pickupCodes.GetForUpdate(ctxTx, orderId)
+		if err != nil {
+			return err
+		}
+
+		if !errors.Is(s.pickupCodePolicy.Verify(stored, code, time.Now()), pvz_domain.ErrPickupCodeInvalid) {
+			return nil
+		}
+		return s.pickupCodes.UpdateAttempts(ctxTx, stored)
+	}, pvz_ports.WithPropagation(pvz_ports.PropagationRequiresNew))
+}
+
+// RegeneratePickupCode replaces the order's pickup code for staff to hand over to the
+// recipient. Failed attempts and a lock-out carry over to the new code.
+func (s *PvzService) RegeneratePickupCode(ctx context.Context, orderId int64, recipientId int64) (code string, err error) {
 	startTime := time.Now()
-	span, ctx := tracing.StartSpanFromContext(ctx, "OrderService.GetHistory")
-	span.SetTag("offset", pagination.Offset)
-	span.SetTag("limit", pagination.Limit)
+	span, ctx := tracing.StartSpanFromContext(ctx, "OrderService.RegeneratePickupCode")
+	span.SetTag("order_id", orderId)
+	span.SetTag("recipient_id", recipientId)
 	defer func() {
 		tracing.FinishSpan(span, startTime, err)
 	}()
 	defer func() {
-		monitoring.ObserveOrderOperation("get_history", err)
+		monitoring.ObserveOrderOperation("regenerate_pickup_code", pickupPointLabel(ctx), err)
 	}()
 
-	orders, err := s.storage.GetList(ctx, pagination)
+	pickupPointID, err := pvz_domain.PickupPointFromContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	span.SetTag("pickup_point_id", pickupPointID)
+
+	txError := s.txManager.RunReadCommitted(ctx, func(ctxTx context.Context) error {
+		order, err := s.storage.GetRecipientOrderByID(ctxTx, pickupPointID, orderId, recipientId)
+		if err != nil {
+			return err
+		}
+
+		if err := checkExpectedVersion(ctxTx, order); err != nil {
+			return err
+		}
+
+		if !order.CanBeDelivered() {
+			return fmt.Errorf("%w: order %d must have items to deliver to issue a pickup code", pvz_domain.ErrOrderNotReceived, order.ID)
+		}
+
+		code, err = s.issuePickupCode(ctxTx, order.ID)
+		return err
+	})
+
+	if txError != nil {
+		return "", txError
+	}
+
+	return code, nil
+}
+
+func (s *PvzService) GetAllRefunds(ctx context.Context, pagination *pvz_domain.Pagination) (result []*pvz_domain.Order, err error) {
+	startTime := time.Now()
+	span, ctx := tracing.StartSpanFromContext(ctx, "OrderService.GetAllRefunds")
+	span.SetTag("offset", pagination.Offset)
+	span.SetTag("limit", pagination.Limit)
+	defer func() {
+		tracing.FinishSpan(span, startTime, err)
+	}()
+	defer func() {
+		monitoring.ObserveOrderOperation("get_all_refunds", pickupPointLabel(ctx), err)
+	}()
+
+	pickupPointID, err := pvz_domain.PickupPointFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	span.SetTag("pickup_point_id", pickupPointID)
+
+	orders, err := s.listOrders(ctx, pickupPointID, pagination)
+	if err != nil {
+		return nil, err
+	}
+
+	var refundedOrders []*pvz_domain.Order
+
+	for _, order := range orders {
+		if order.IsRefunded() {
+			refundedOrders = append(refundedOrders, order)
+		}
+	}
+
+	return refundedOrders, nil
+}
+
+func (s *PvzService) GetHistory(ctx context.Context, pagination *pvz_domain.Pagination) (result []*pvz_domain.Order, err error) {
+	startTime := time.Now()
+	span, ctx := tracing.StartSpanFromContext(ctx, "OrderService.GetHistory")
+	span.SetTag("offset", pagination.Offset)
+	span.SetTag("limit", pagination.Limit)
+	defer func() {
+		tracing.FinishSpan(span, startTime, err)
+	}()
+	defer func() {
+		monitoring.ObserveOrderOperation("get_history", pickupPointLabel(ctx), err)
+	}()
+
+	pickupPointID, err := pvz_domain.PickupPointFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	span.SetTag("pickup_point_id", pickupPointID)
+
+	orders, err := s.listOrders(ctx, pickupPointID, pagination)
 	if err != nil {
 		return nil, err
 	}
@@ -517,3 +969,560 @@ func (s *PvzService) GetHistory(ctx context.Context, pagination *pvz_domain.Pagi
 
 	return orders, nil
 }
+
+// ExportOrders passes every order of the pickup point after afterID that matches
+// the filter to send, in id order. Orders are read in batches, each one only after
+// the previous was sent, so a slow receiver holds reading back instead of orders
+// piling up in memory.
+func (s *PvzService) ExportOrders(ctx context.Context, filter *pvz_domain.OrderExportFilter, afterID int64, send func(*pvz_domain.Order) error) (err error) {
+	startTime := time.Now()
+	exported := 0
+	span, ctx := tracing.StartSpanFromContext(ctx, "OrderService.ExportOrders")
+	span.SetTag("after_id", afterID)
+	defer func() {
+		span.SetTag("orders_count", exported)
+		tracing.FinishSpan(span, startTime, err)
+	}()
+	defer func() {
+		monitoring.ObserveOrderOperation("export_orders", pickupPointLabel(ctx), err)
+	}()
+
+	pickupPointID, err := pvz_domain.PickupPointFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	span.SetTag("pickup_point_id", pickupPointID)
+
+	if filter == nil {
+		filter = &pvz_domain.OrderExportFilter{}
+	}
+
+	for {
+		var orders []*pvz_domain.Order
+		txError := s.txManager.RunReadOnly(ctx, func(ctxTx context.Context) error {
+			var err error
+			orders, err = s.storage.Export(ctxTx, pickupPointID, filter, afterID, exportBatchSize)
+			return err
+		})
+		if txError != nil {
+			return txError
+		}
+
+		for _, order := range orders {
+			if err := send(order); err != nil {
+				return err
+			}
+			afterID = order.ID
+			exported++
+		}
+
+		if int64(len(orders)) < exportBatchSize {
+			return nil
+		}
+	}
+}
+
+// WatchOrders passes status changes of the pickup point to send as they are
+// committed, until ctx is done. With afterRecordID set, changes after it are
+// replayed first. Record ids are taken before commit, so a change that commits out
+// of id order can be missed by a watcher resuming right at it.
+func (s *PvzService) WatchOrders(ctx context.Context, afterRecordID int64, send func(*pvz_domain.OrderStatusChange) error) (err error) {
+	startTime := time.Now()
+	span, ctx := tracing.StartSpanFromContext(ctx, "OrderService.WatchOrders")
+	span.SetTag("after_record_id", afterRecordID)
+	defer func() {
+		tracing.FinishSpan(span, startTime, err)
+	}()
+	defer func() {
+		monitoring.ObserveOrderOperation("watch_orders", pickupPointLabel(ctx), err)
+	}()
+
+	pickupPointID, err := pvz_domain.PickupPointFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	span.SetTag("pickup_point_id", pickupPointID)
+
+	// Subscribing before the replay leaves no gap between replayed and live changes.
+	changes, unsubscribe := s.statusFeed.Subscribe(pickupPointID)
+	defer unsubscribe()
+
+	replayedUpTo := afterRecordID
+	for afterRecordID > 0 {
+		var replayed []*pvz_domain.OrderStatusChange
+		txError := s.txManager.RunReadOnly(pvz_ports.WithPrimary(ctx), func(ctxTx context.Context) error {
+			var err error
+			replayed, err = s.storage.GetStatusChanges(ctxTx, pickupPointID, replayedUpTo, watchReplayBatchSize)
+			return err
+		})
+		if txError != nil {
+			return txError
+		}
+
+		for _, change := range replayed {
+			if err := send(change); err != nil {
+				return err
+			}
+			replayedUpTo = change.RecordID
+		}
+
+		if int64(len(replayed)) < watchReplayBatchSize {
+			break
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case change, ok := <-changes:
+			if !ok {
+				return pvz_domain.ErrStatusFeedInterrupted
+			}
+			if change.RecordID <= replayedUpTo {
+				continue
+			}
+			if err := send(change); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *PvzService) TransferOrder(ctx context.Context, orderId int64, targetPickupPointID int64) (err error) {
+	startTime := time.Now()
+	span, ctx := tracing.StartSpanFromContext(ctx, "OrderService.TransferOrder")
+	span.SetTag("order_id", orderId)
+	span.SetTag("target_pickup_point_id", targetPickupPointID)
+	defer func() {
+		tracing.FinishSpan(span, startTime, err)
+	}()
+	defer func() {
+		monitoring.ObserveOrderOperation("transfer_order", pickupPointLabel(ctx), err)
+	}()
+
+	pickupPointID, err := pvz_domain.PickupPointFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	span.SetTag("pickup_point_id", pickupPointID)
+
+	txError := s.txManager.RunRepeatableRead(ctx, func(ctxTx context.Context) error {
+		_, err := s.ProcessOrderTransfer(ctxTx, pickupPointID, orderId, targetPickupPointID)
+		return err
+	})
+
+	return txError
+}
+
+// ProcessOrderTransfer hands a received order over to another pickup point, leaving
+// a "transferred" record at the source and an "in_transfer" record at the target.
+func (s *PvzService) ProcessOrderTransfer(ctxTx context.Context, pickupPointID int64, orderId int64, targetPickupPointID int64) (*pvz_domain.Order, error) {
+	if targetPickupPointID <= 0 || targetPickupPointID == pickupPointID {
+		return nil, fmt.Errorf("%w: order %d to pickup point %d", pvz_domain.ErrInvalidTransferTarget, orderId, targetPickupPointID)
+	}
+
+	order, err := s.storage.GetByID(ctxTx, pickupPointID, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkExpectedVersion(ctxTx, order); err != nil {
+		return nil, err
+	}
+
+	if !order.CanBeTransferred() {
+		return nil, fmt.Errorf("%w: order %d", pvz_domain.ErrOrderNotTransferable, order.ID)
+	}
+
+	if err := s.releaseCell(ctxTx, order); err != nil {
+		return nil, err
+	}
+
+	order.StartTransfer(targetPickupPointID)
+
+	if err := s.storage.MoveToPickupPoint(ctxTx, order.ID, pickupPointID, targetPickupPointID); err != nil {
+		return nil, err
+	}
+
+	if err := s.storage.Update(ctxTx, order); err != nil {
+		return nil, err
+	}
+
+	if err := s.recordStatusChange(ctxTx, order.ID, pvz_domain.NewOrderRecordTransferred(pickupPointID)); err != nil {
+		return nil, err
+	}
+
+	if err := s.recordStatusChange(ctxTx, order.ID, pvz_domain.NewOrderRecordInTransfer(targetPickupPointID)); err != nil {
+		return nil, err
+	}
+
+	s.invalidateOrderAfterCommit(ctxTx, pickupPointID, order.ID)
+	s.invalidateOrderAfterCommit(ctxTx, targetPickupPointID, order.ID)
+
+	return order, nil
+}
+
+func (s *PvzService) AcceptTransfer(ctx context.Context, orderId int64) (err error) {
+	startTime := time.Now()
+	span, ctx := tracing.StartSpanFromContext(ctx, "OrderService.AcceptTransfer")
+	span.SetTag("order_id", orderId)
+	defer func() {
+		tracing.FinishSpan(span, startTime, err)
+	}()
+	defer func() {
+		monitoring.ObserveOrderOperation("accept_transfer", pickupPointLabel(ctx), err)
+	}()
+
+	pickupPointID, err := pvz_domain.PickupPointFromContext(ctx)
+	if err != nil {
+		return err
+	}
+	span.SetTag("pickup_point_id", pickupPointID)
+
+	txError := s.txManager.RunRepeatableRead(ctx, func(ctxTx context.Context) error {
+		_, err := s.ProcessTransferAccept(ctxTx, pickupPointID, orderId)
+		return err
+	})
+
+	return txError
+}
+
+func (s *PvzService) ProcessTransferAccept(ctxTx context.Context, pickupPointID int64, orderId int64) (*pvz_domain.Order, error) {
+	order, err := s.storage.GetByID(ctxTx, pickupPointID, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkExpectedVersion(ctxTx, order); err != nil {
+		return nil, err
+	}
+
+	if !order.IsInTransfer() {
+		return nil, fmt.Errorf("%w: order %d", pvz_domain.ErrOrderNotInTransfer, order.ID)
+	}
+
+	order.Received()
+
+	// Packaging is not known after a transfer, so the order gets a cell by weight only.
+	if err := s.allocateCell(ctxTx, order, pvz_domain.RequiredCellSize("", order.Weight)); err != nil {
+		return nil, err
+	}
+
+	if err := s.storage.Update(ctxTx, order); err != nil {
+		return nil, err
+	}
+
+	if err := s.recordStatusChange(ctxTx, order.ID, pvz_domain.NewOrderRecordReceived(pickupPointID)); err != nil {
+		return nil, err
+	}
+
+	s.invalidateOrderAfterCommit(ctxTx, pickupPointID, order.ID)
+
+	return order, nil
+}
+
+func (s *PvzService) GetOrderCell(ctx context.Context, orderId int64) (result *pvz_domain.Cell, err error) {
+	startTime := time.Now()
+	span, ctx := tracing.StartSpanFromContext(ctx, "OrderService.GetOrderCell")
+	span.SetTag("order_id", orderId)
+	defer func() {
+		tracing.FinishSpan(span, startTime, err)
+	}()
+	defer func() {
+		monitoring.ObserveOrderOperation("get_order_cell", pickupPointLabel(ctx), err)
+	}()
+
+	pickupPointID, err := pvz_domain.PickupPointFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	span.SetTag("pickup_point_id", pickupPointID)
+
+	order, err := s.storage.GetByID(ctx, pickupPointID, orderId)
+	if err != nil {
+		return nil, err
+	}
+
+	if order.CellID == nil {
+		return nil, fmt.Errorf("%w: order %d", pvz_domain.ErrOrderNotInCell, order.ID)
+	}
+
+	return s.cells.GetByID(ctx, pickupPointID, *order.CellID)
+}
+
+func (s *PvzService) ReshelveOrder(ctx context.Context, orderId int64, cellId int64) (result *pvz_domain.Cell, err error) {
+	startTime := time.Now()
+	span, ctx := tracing.StartSpanFromContext(ctx, "OrderService.ReshelveOrder")
+	span.SetTag("order_id", orderId)
+	span.SetTag("cell_id", cellId)
+	defer func() {
+		tracing.FinishSpan(span, startTime, err)
+	}()
+	defer func() {
+		monitoring.ObserveOrderOperation("reshelve_order", pickupPointLabel(ctx), err)
+	}()
+
+	pickupPointID, err := pvz_domain.PickupPointFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	span.SetTag("pickup_point_id", pickupPointID)
+
+	txError := s.txManager.RunReadCommitted(ctx, func(ctxTx context.Context) error {
+		_, cell, err := s.ProcessOrderReshelve(ctxTx, pickupPointID, orderId, cellId)
+		if err != nil {
+			return err
+		}
+
+		result = cell
+
+		return nil
+	})
+
+	if txError != nil {
+		return nil, txError
+	}
+
+	return result, nil
+}
+
+// AddCell provisions an empty storage cell at the pickup point, so that intake can
+// shelve orders into it.
+func (s *PvzService) AddCell(ctx context.Context, cell *pvz_domain.Cell) (result *pvz_domain.Cell, err error) {
+	startTime := time.Now()
+	span, ctx := tracing.StartSpanFromContext(ctx, "OrderService.AddCell")
+	span.SetTag("cell_code", cell.Code)
+	defer func() {
+		tracing.FinishSpan(span, startTime, err)
+	}()
+	defer func() {
+		monitoring.ObserveOrderOperation("add_cell", pickupPointLabel(ctx), err)
+	}()
+
+	pickupPointID, err := pvz_domain.PickupPointFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	span.SetTag("pickup_point_id", pickupPointID)
+
+	cell.PickupPointID = pickupPointID
+
+	return s.cells.Add(ctx, cell)
+}
+
+func (s *PvzService) ProcessOrderReshelve(ctxTx context.Context, pickupPointID int64, orderId int64, cellId int64) (*pvz_domain.Order, *pvz_domain.Cell, error) {
+	order, err := s.storage.GetByID(ctxTx, pickupPointID, orderId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := checkExpectedVersion(ctxTx, order); err != nil {
+		return nil, nil, err
+	}
+
+	if !order.IsReceived() {
+		return nil, nil, fmt.Errorf("%w: order %d must be received to be placed in a storage cell", pvz_domain.ErrOrderNotReceived, order.ID)
+	}
+
+	if order.CellID != nil && *order.CellID == cellId {
+		cell, err := s.cells.GetByID(ctxTx, pickupPointID, cellId)
+		return order, cell, err
+	}
+
+	cell, err := s.cells.Occupy(ctxTx, pickupPointID, cellId, order.Weight)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.releaseCell(ctxTx, order); err != nil {
+		return nil, nil, err
+	}
+
+	order.PlaceInCell(cell.ID)
+
+	if err := s.storage.Update(ctxTx, order); err != nil {
+		return nil, nil, err
+	}
+
+	s.invalidateOrderAfterCommit(ctxTx, pickupPointID, order.ID)
+
+	return order, cell, nil
+}
+
+// allocateCell shelves the order into the best fitting free cell. A full pickup point
+// does not block intake: the order stays unshelved until staff re-shelve it, and is
+// counted in orders_unshelved_total.
+func (s *PvzService) allocateCell(ctxTx context.Context, order *pvz_domain.Order, size pvz_domain.CellSize) error {
+	cell, err := s.cells.Allocate(ctxTx, order.PickupPointID, size, order.Weight)
+	if errors.Is(err, pvz_domain.ErrNoFreeCell) {
+		app_logger.MyLogger.Warn("no free storage cell for order",
+			zap.Int64("pickup_point_id", order.PickupPointID),
+			zap.Int("cell_size", int(size)),
+			zap.Float64("weight", order.Weight),
+		)
+		monitoring.ObserveUnshelvedOrder(strconv.FormatInt(order.PickupPointID, 10))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	order.PlaceInCell(cell.ID)
+	return nil
+}
+
+// checkExpectedVersion enforces the version the client expects the order at, if any.
+func checkExpectedVersion(ctx context.Context, order *pvz_domain.Order) error {
+	expected, ok := pvz_domain.ExpectedVersionFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	return order.MatchVersion(expected)
+}
+
+// readForCache routes reads whose results get cached to the primary: a lagging
+// replica would have its stale orders served from the cache until they expire.
+func readForCache(ctx context.Context) context.Context {
+	return pvz_ports.WithPrimary(ctx)
+}
+
+// cacheFailed reports whether err is a cache failure worth reporting. Calls skipped
+// while the cache is down are only counted in metrics.
+func cacheFailed(err error) bool {
+	return err != nil && !errors.Is(err, pvz_ports.ErrCacheUnavailable)
+}
+
+// listOrders returns a page of orders with their history and items. Pages are cached
+// until any order of the pickup point changes; a miss reads the page from one snapshot.
+func (s *PvzService) listOrders(ctx context.Context, pickupPointID int64, pagination *pvz_domain.Pagination) ([]*pvz_domain.Order, error) {
+	orders, generation, err := s.cache.GetOrderPage(ctx, pickupPointID, pagination)
+	if err == nil {
+		monitoring.ObserveCacheOperation("get_page_hit", nil)
+		return orders, nil
+	}
+	cacheable := errors.Is(err, redis.Nil)
+	if cacheable {
+		monitoring.ObserveCacheOperation("get_page_miss", nil)
+	} else {
+		monitoring.ObserveCacheOperation("get_page_error", err)
+	}
+
+	txError := s.txManager.RunReadOnly(readForCache(ctx), func(ctxTx context.Context) error {
+		var err error
+		orders, err = s.storage.GetList(ctxTx, pickupPointID, pagination)
+		return err
+	})
+	if txError != nil {
+		return nil, txError
+	}
+
+	if cacheable {
+		cacheErr := s.cache.SetOrderPage(ctx, pickupPointID, generation, pagination, orders)
+		if cacheFailed(cacheErr) {
+			app_logger.MyLogger.Warn("failed to cache orders page",
+				zap.Int64("pickup_point_id", pickupPointID),
+				zap.Error(cacheErr),
+			)
+		}
+		monitoring.ObserveCacheOperation("set_page", cacheErr)
+	}
+
+	return orders, nil
+}
+
+// invalidateOrderAfterCommit drops the cached order once the transaction commits. The
+// change is durable by then, so a cache failure is only reported and bounded by the TTL.
+func (s *PvzService) invalidateOrderAfterCommit(ctxTx context.Context, pickupPointID int64, orderId int64) {
+	s.txManager.AfterCommit(ctxTx, func(ctx context.Context) error {
+		err := s.cache.DeleteOrder(ctx, pickupPointID, orderId)
+		monitoring.ObserveCacheOperation("delete_order", err)
+		if cacheFailed(err) {
+			return fmt.Errorf("invalidate cached order %d of pickup point %d: %w", orderId, pickupPointID, err)
+		}
+		return nil
+	})
+}
+
+// invalidateAddedOrdersAfterCommit drops cached order lists of the pickup point and
+// lookups that missed the added orders once they are committed.
+func (s *PvzService) invalidateAddedOrdersAfterCommit(ctxTx context.Context, pickupPointID int64, orderIds []int64) {
+	s.txManager.AfterCommit(ctxTx, func(ctx context.Context) error {
+		var errs []error
+
+		err := s.cache.InvalidateOrderPages(ctx, pickupPointID)
+		monitoring.ObserveCacheOperation("invalidate_pages", err)
+		if cacheFailed(err) {
+			errs = append(errs, fmt.Errorf("invalidate cached order pages of pickup point %d: %w", pickupPointID, err))
+		}
+
+		err = s.cache.ForgetMissingOrders(ctx, pickupPointID, orderIds)
+		monitoring.ObserveCacheOperation("forget_missing_orders", err)
+		if cacheFailed(err) {
+			errs = append(errs, fmt.Errorf("forget missed lookups of orders %v of pickup point %d: %w", orderIds, pickupPointID, err))
+		}
+
+		return errors.Join(errs...)
+	})
+}
+
+func (s *PvzService) issuePickupCode(ctxTx context.Context, orderId int64) (string, error) {
+	stored, code, err := s.pickupCodePolicy.Issue(orderId, time.Now())
+	if err != nil {
+		return "", err
+	}
+
+	if err := s.pickupCodes.Save(ctxTx, stored); err != nil {
+		return "", err
+	}
+
+	return code, nil
+}
+
+func (s *PvzService) releaseCell(ctxTx context.Context, order *pvz_domain.Order) error {
+	if order.CellID == nil {
+		return nil
+	}
+
+	if err := s.cells.Release(ctxTx, order.PickupPointID, *order.CellID, order.Weight); err != nil {
+		return err
+	}
+
+	order.TakeFromCell()
+	return nil
+}
+
+// recordStatusChange stores a history record and the matching outbox task.
+func (s *PvzService) recordStatusChange(ctxTx context.Context, orderId int64, orderRecord *pvz_domain.OrderRecord) error {
+	if _, err := s.storage.AddHistoryRecord(ctxTx, orderRecord, orderId); err != nil {
+		return err
+	}
+
+	task := &order_outbox.OrderOutboxTask{
+		Status:    order_outbox.Created,
+		CreatedAt: time.Now(),
+	}
+	task.SetOrderStatusDetails(orderId, orderRecord)
+
+	_, err := s.outbox.AddTask(ctxTx, task)
+	return err
+}
+
+// recordItemsStatusChange records every changed item and then the order status derived from them.
+func (s *PvzService) recordItemsStatusChange(ctxTx context.Context, pickupPointID int64, order *pvz_domain.Order, items []*pvz_domain.OrderItem) error {
+	for _, item := range items {
+		if err := s.recordStatusChange(ctxTx, order.ID, pvz_domain.NewOrderItemRecord(pickupPointID, item)); err != nil {
+			return err
+		}
+	}
+
+	return s.recordStatusChange(ctxTx, order.ID, pvz_domain.NewOrderRecord(pickupPointID, order.Status))
+}
+
+func pickupPointLabel(ctx context.Context) string {
+	pickupPointID, err := pvz_domain.PickupPointFromContext(ctx)
+	if err != nil {
+		return ""
+	}
+	return strconv.FormatInt(pickupPointID, 10)
+}
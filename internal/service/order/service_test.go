@@ -8,24 +8,39 @@ import (
 
 	"github.com/Staspol216/gh1/internal/domain/order"
 	"github.com/Staspol216/gh1/internal/infra/order_outbox"
+	"github.com/Staspol216/gh1/internal/ports"
 	portsMocks "github.com/Staspol216/gh1/internal/ports/mocks"
 	"github.com/Staspol216/gh1/internal/service/order/mocks"
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
 
 const (
-	testOrderID     int64 = 1
-	testRecipientID int64 = 123
+	testOrderID       int64 = 1
+	testRecipientID   int64 = 123
+	testPickupPointID int64 = 7
+	testCellID        int64 = 42
 )
 
+var testPickupCodePolicy = pvz_domain.PickupCodePolicy{
+	Secret:      []byte("test-secret"),
+	TTL:         time.Hour,
+	MaxAttempts: 3,
+	Lockout:     time.Minute,
+}
+
 type pvzServiceTestFixture struct {
-	service   *PvzService
-	storage   *mocks.MockOrderStorage
-	cache     *mocks.MockOrdersCache
-	outbox    *mocks.MockOutbox
-	txManager *portsMocks.MockTransactionManager
+	service     *PvzService
+	storage     *mocks.MockOrderStorage
+	cells       *mocks.MockCellStorage
+	pickupCodes *mocks.MockPickupCodeStorage
+	cache       *mocks.MockOrdersCache
+	outbox      *mocks.MockOutbox
+	statusFeed  *mocks.MockStatusFeed
+	txManager   *portsMocks.MockTransactionManager
+	txHooks     *portsMocks.TxHooks
 }
 
 func newPvzServiceTestFixture(t *testing.T) *pvzServiceTestFixture {
@@ -34,25 +49,59 @@ func newPvzServiceTestFixture(t *testing.T) *pvzServiceTestFixture {
 	ctrl := gomock.NewController(t)
 
 	storage := mocks.NewMockOrderStorage(ctrl)
+	cells := mocks.NewMockCellStorage(ctrl)
+	pickupCodes := mocks.NewMockPickupCodeStorage(ctrl)
 	cache := mocks.NewMockOrdersCache(ctrl)
 	outbox := mocks.NewMockOutbox(ctrl)
+	statusFeed := mocks.NewMockStatusFeed(ctrl)
 	txManager := portsMocks.NewMockTransactionManager(ctrl)
 
 	return &pvzServiceTestFixture{
-		service:   NewPvzService(storage, outbox, cache, txManager),
-		storage:   storage,
-		cache:     cache,
-		outbox:    outbox,
-		txManager: txManager,
+		service:     NewPvzService(storage, cells, pickupCodes, testPickupCodePolicy, outbox, cache, statusFeed, txManager),
+		storage:     storage,
+		cells:       cells,
+		pickupCodes: pickupCodes,
+		cache:       cache,
+		outbox:      outbox,
+		statusFeed:  statusFeed,
+		txManager:   txManager,
+		txHooks:     portsMocks.NewTxHooks(txManager),
+	}
+}
+
+// expectOrderInvalidated runs post-commit hooks right away and expects them to drop the cached order.
+func (f *pvzServiceTestFixture) expectOrderInvalidated(pickupPointIDs ...int64) {
+	for _, pickupPointID := range pickupPointIDs {
+		f.txHooks.ExpectCommitted()
+		f.cache.EXPECT().DeleteOrder(gomock.Any(), pickupPointID, testOrderID)
 	}
 }
 
+// expectAddedOrdersInvalidated runs post-commit hooks right away and expects them to
+// drop cached order pages and lookups that missed the added orders.
+func (f *pvzServiceTestFixture) expectAddedOrdersInvalidated(pickupPointID int64, orderIds ...int64) {
+	f.txHooks.ExpectCommitted()
+	f.cache.EXPECT().InvalidateOrderPages(gomock.Any(), pickupPointID)
+	f.cache.EXPECT().ForgetMissingOrders(gomock.Any(), pickupPointID, orderIds)
+}
+
+// expectPickupCode expects the test order's pickup code to be locked and returns a
+// code that passes verification.
+func (f *pvzServiceTestFixture) expectPickupCode(t *testing.T) string {
+	t.Helper()
+
+	stored, code, err := testPickupCodePolicy.Issue(testOrderID, time.Now())
+	require.NoError(t, err)
+	f.pickupCodes.EXPECT().GetForUpdate(gomock.Any(), testOrderID).Return(stored, nil)
+	return code
+}
+
 func newReceiveOrderParams() *pvz_domain.OrderParams {
 	return &pvz_domain.OrderParams{
 		RecipientId:    testRecipientID,
 		ExpirationDate: time.Now().Add(24 * time.Hour),
 		Weight:         5,
-		Worth:          100,
+		Worth:          pvz_domain.NewMoney(10000, pvz_domain.DefaultCurrency),
 	}
 }
 
@@ -77,10 +126,21 @@ func newReceivedTestOrder(expirationDate time.Time) *pvz_domain.Order {
 
 func newReceivedStoredTestOrder() *pvz_domain.Order {
 	order := newReceivedTestOrder(time.Now().Add(24 * time.Hour))
-	order.Worth = 121
+	order.Worth = pvz_domain.NewMoney(12100, pvz_domain.DefaultCurrency)
 	return order
 }
 
+func newTestCell() *pvz_domain.Cell {
+	return &pvz_domain.Cell{
+		ID:            testCellID,
+		PickupPointID: testPickupPointID,
+		Code:          "A-1",
+		Size:          pvz_domain.CellSizeMedium,
+		Capacity:      10,
+		MaxWeight:     50,
+	}
+}
+
 func TestPvzService_ProcessOrderReceive(t *testing.T) {
 	t.Parallel()
 
@@ -90,26 +150,33 @@ func TestPvzService_ProcessOrderReceive(t *testing.T) {
 		t.Parallel()
 		// arrange
 		fixture := newPvzServiceTestFixture(t)
+		fixture.expectAddedOrdersInvalidated(testPickupPointID, testOrderID)
 		payload := newReceiveOrderParams()
 		storedOrder := newReceivedStoredTestOrder()
 
+		fixture.cells.EXPECT().Allocate(gomock.Any(), testPickupPointID, pvz_domain.CellSizeMedium, payload.Weight).Return(newTestCell(), nil)
 		fixture.storage.EXPECT().Add(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, order *pvz_domain.Order) (int64, error) {
+			require.NotNil(t, order.CellID)
+			assert.Equal(t, testCellID, *order.CellID)
 			assert.Equal(t, testRecipientID, order.RecipientID)
+			assert.Equal(t, testPickupPointID, order.PickupPointID)
 			assert.Equal(t, pvz_domain.OrderStatusReceived, order.Status)
-			assert.Equal(t, float64(121), order.Worth)
+			assert.Equal(t, pvz_domain.NewMoney(12100, pvz_domain.DefaultCurrency), order.Worth)
 			return testOrderID, nil
 		})
 		fixture.storage.EXPECT().AddHistoryRecord(gomock.Any(), gomock.Any(), testOrderID)
-		fixture.storage.EXPECT().GetByID(gomock.Any(), testOrderID).Return(storedOrder, nil)
+		fixture.storage.EXPECT().GetByID(gomock.Any(), testPickupPointID, testOrderID).Return(storedOrder, nil)
 		fixture.outbox.EXPECT().AddTask(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, task *order_outbox.OrderOutboxTask) (int64, error) {
 			assert.Equal(t, order_outbox.Created, task.Status)
+			assert.Equal(t, testOrderID, task.OrderID)
+			assert.Equal(t, testPickupPointID, task.PickupPointID)
 			assert.Equal(t, pvz_domain.OrderStatusReceived, task.OrderStatus)
 			assert.Equal(t, pvz_domain.OrderStatusDescription[pvz_domain.OrderStatusReceived], task.Description)
 			return int64(1), nil
 		})
 
 		// act
-		order, err := fixture.service.ProcessOrderReceive(ctx, payload, "box", true)
+		order, err := fixture.service.ProcessOrderReceive(ctx, testPickupPointID, payload, "box", true)
 
 		// assert
 		require.NoError(t, err)
@@ -124,13 +191,38 @@ func TestPvzService_ProcessOrderReceive(t *testing.T) {
 		payload.Weight = 10.01
 
 		// act
-		order, err := fixture.service.ProcessOrderReceive(ctx, payload, "bag", false)
+		order, err := fixture.service.ProcessOrderReceive(ctx, testPickupPointID, payload, "bag", false)
 
 		// assert
 		require.Error(t, err)
 		assert.Nil(t, order)
 	})
 
+	t.Run("accepts order without cell when pickup point is full", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fixture := newPvzServiceTestFixture(t)
+		fixture.expectAddedOrdersInvalidated(testPickupPointID, testOrderID)
+		payload := newReceiveOrderParams()
+		storedOrder := newReceivedStoredTestOrder()
+
+		fixture.cells.EXPECT().Allocate(gomock.Any(), testPickupPointID, pvz_domain.CellSizeSmall, payload.Weight).Return(nil, pvz_domain.ErrNoFreeCell)
+		fixture.storage.EXPECT().Add(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, order *pvz_domain.Order) (int64, error) {
+			assert.Nil(t, order.CellID)
+			return testOrderID, nil
+		})
+		fixture.storage.EXPECT().AddHistoryRecord(gomock.Any(), gomock.Any(), testOrderID)
+		fixture.storage.EXPECT().GetByID(gomock.Any(), testPickupPointID, testOrderID).Return(storedOrder, nil)
+		fixture.outbox.EXPECT().AddTask(gomock.Any(), gomock.Any())
+
+		// act
+		order, err := fixture.service.ProcessOrderReceive(ctx, testPickupPointID, payload, "bag", false)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, storedOrder, order)
+	})
+
 	t.Run("returns error when add order fails", func(t *testing.T) {
 		t.Parallel()
 		// arrange
@@ -138,10 +230,11 @@ func TestPvzService_ProcessOrderReceive(t *testing.T) {
 		payload := newReceiveOrderParams()
 		expectedErr := errors.New("add order failed")
 
+		fixture.cells.EXPECT().Allocate(gomock.Any(), testPickupPointID, gomock.Any(), gomock.Any()).Return(newTestCell(), nil)
 		fixture.storage.EXPECT().Add(gomock.Any(), gomock.Any()).Return(int64(0), expectedErr)
 
 		// act
-		order, err := fixture.service.ProcessOrderReceive(ctx, payload, "box", false)
+		order, err := fixture.service.ProcessOrderReceive(ctx, testPickupPointID, payload, "box", false)
 
 		// assert
 		require.ErrorIs(t, err, expectedErr)
@@ -155,11 +248,12 @@ func TestPvzService_ProcessOrderReceive(t *testing.T) {
 		payload := newReceiveOrderParams()
 		expectedErr := errors.New("add history record failed")
 
+		fixture.cells.EXPECT().Allocate(gomock.Any(), testPickupPointID, gomock.Any(), gomock.Any()).Return(newTestCell(), nil)
 		fixture.storage.EXPECT().Add(gomock.Any(), gomock.Any()).Return(testOrderID, nil)
 		fixture.storage.EXPECT().AddHistoryRecord(gomock.Any(), gomock.Any(), testOrderID).Return(int64(0), expectedErr)
 
 		// act
-		order, err := fixture.service.ProcessOrderReceive(ctx, payload, "box", false)
+		order, err := fixture.service.ProcessOrderReceive(ctx, testPickupPointID, payload, "box", false)
 
 		// assert
 		require.ErrorIs(t, err, expectedErr)
@@ -173,12 +267,13 @@ func TestPvzService_ProcessOrderReceive(t *testing.T) {
 		payload := newReceiveOrderParams()
 		expectedErr := errors.New("get order failed")
 
+		fixture.cells.EXPECT().Allocate(gomock.Any(), testPickupPointID, gomock.Any(), gomock.Any()).Return(newTestCell(), nil)
 		fixture.storage.EXPECT().Add(gomock.Any(), gomock.Any()).Return(testOrderID, nil)
 		fixture.storage.EXPECT().AddHistoryRecord(gomock.Any(), gomock.Any(), testOrderID)
-		fixture.storage.EXPECT().GetByID(gomock.Any(), testOrderID).Return(nil, expectedErr)
+		fixture.storage.EXPECT().GetByID(gomock.Any(), testPickupPointID, testOrderID).Return(nil, expectedErr)
 
 		// act
-		order, err := fixture.service.ProcessOrderReceive(ctx, payload, "box", false)
+		order, err := fixture.service.ProcessOrderReceive(ctx, testPickupPointID, payload, "box", false)
 
 		// assert
 		require.ErrorIs(t, err, expectedErr)
@@ -193,13 +288,14 @@ func TestPvzService_ProcessOrderReceive(t *testing.T) {
 		storedOrder := newReceivedStoredTestOrder()
 		expectedErr := errors.New("add outbox task failed")
 
+		fixture.cells.EXPECT().Allocate(gomock.Any(), testPickupPointID, gomock.Any(), gomock.Any()).Return(newTestCell(), nil)
 		fixture.storage.EXPECT().Add(gomock.Any(), gomock.Any()).Return(testOrderID, nil)
 		fixture.storage.EXPECT().AddHistoryRecord(gomock.Any(), gomock.Any(), testOrderID)
-		fixture.storage.EXPECT().GetByID(gomock.Any(), testOrderID).Return(storedOrder, nil)
+		fixture.storage.EXPECT().GetByID(gomock.Any(), testPickupPointID, testOrderID).Return(storedOrder, nil)
 		fixture.outbox.EXPECT().AddTask(gomock.Any(), gomock.Any()).Return(int64(0), expectedErr)
 
 		// act
-		order, err := fixture.service.ProcessOrderReceive(ctx, payload, "box", false)
+		order, err := fixture.service.ProcessOrderReceive(ctx, testPickupPointID, payload, "box", false)
 
 		// assert
 		require.ErrorIs(t, err, expectedErr)
@@ -207,6 +303,147 @@ func TestPvzService_ProcessOrderReceive(t *testing.T) {
 	})
 }
 
+func TestPvzService_AcceptBatchFromCourier(t *testing.T) {
+	t.Parallel()
+
+	ctx := pvz_domain.WithPickupPoint(context.Background(), testPickupPointID)
+
+	t.Run("stores valid parcels in bulk and reports rejected ones", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fixture := newPvzServiceTestFixture(t)
+		fixture.expectAddedOrdersInvalidated(testPickupPointID, 1, 2)
+		expired := newReceiveOrderParams()
+		expired.ExpirationDate = time.Now().Add(-time.Hour)
+		parcels := []*CourierParcel{
+			{Order: newReceiveOrderParams(), PackagingType: "box"},
+			{Order: expired, PackagingType: "box"},
+			{Order: nil, PackagingType: "box"},
+			{Order: newReceiveOrderParams(), PackagingType: "bag"},
+		}
+
+		fixture.txManager.EXPECT().RunReadCommitted(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+		fixture.cells.EXPECT().Allocate(gomock.Any(), testPickupPointID, gomock.Any(), gomock.Any()).Return(newTestCell(), nil).Times(2)
+		fixture.storage.EXPECT().AddBatch(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, orders []*pvz_domain.Order) error {
+			require.Len(t, orders, 2)
+			for i, order := range orders {
+				assert.Equal(t, pvz_domain.OrderStatusReceived, order.Status)
+				order.ID = int64(i + 1)
+			}
+			return nil
+		})
+		fixture.storage.EXPECT().AddHistoryRecords(gomock.Any(), gomock.Any(), []int64{1, 2})
+		fixture.outbox.EXPECT().AddTasks(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, tasks []*order_outbox.OrderOutboxTask) error {
+			require.Len(t, tasks, 2)
+			assert.Equal(t, int64(1), tasks[0].OrderID)
+			assert.Equal(t, int64(2), tasks[1].OrderID)
+			return nil
+		})
+		fixture.pickupCodes.EXPECT().SaveBatch(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, codes []*pvz_domain.PickupCode) error {
+			require.Len(t, codes, 2)
+			assert.Equal(t, int64(1), codes[0].OrderID)
+			assert.Equal(t, int64(2), codes[1].OrderID)
+			return nil
+		})
+
+		// act
+		results, err := fixture.service.AcceptBatchFromCourier(ctx, parcels)
+
+		// assert
+		require.NoError(t, err)
+		require.Len(t, results, 4)
+		assert.Equal(t, int64(1), results[0].Accepted.OrderID)
+		assert.NotEmpty(t, results[0].Accepted.PickupCode)
+		assert.ErrorIs(t, results[1].Err, pvz_domain.ErrInvalidOrderParams)
+		assert.ErrorIs(t, results[2].Err, pvz_domain.ErrInvalidOrderParams)
+		assert.Equal(t, int64(2), results[3].Accepted.OrderID)
+	})
+
+	t.Run("fails the whole delivery when storing fails", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fixture := newPvzServiceTestFixture(t)
+		expectedErr := errors.New("db unavailable")
+
+		fixture.txManager.EXPECT().RunReadCommitted(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+		fixture.cells.EXPECT().Allocate(gomock.Any(), testPickupPointID, gomock.Any(), gomock.Any()).Return(newTestCell(), nil).Times(2)
+		fixture.storage.EXPECT().AddBatch(gomock.Any(), gomock.Any()).Return(expectedErr)
+
+		// act
+		results, err := fixture.service.AcceptBatchFromCourier(ctx, []*CourierParcel{
+			{Order: newReceiveOrderParams(), PackagingType: "box"},
+			{Order: newReceiveOrderParams(), PackagingType: "box"},
+		})
+
+		// assert
+		require.ErrorIs(t, err, expectedErr)
+		assert.Nil(t, results)
+	})
+
+	t.Run("skips transaction when every parcel is rejected", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fixture := newPvzServiceTestFixture(t)
+		heavy := newReceiveOrderParams()
+		heavy.Weight = 10.01
+
+		// act
+		results, err := fixture.service.AcceptBatchFromCourier(ctx, []*CourierParcel{{Order: heavy, PackagingType: "bag"}})
+
+		// assert
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Error(t, results[0].Err)
+	})
+
+	t.Run("returns error when batch is empty", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fixture := newPvzServiceTestFixture(t)
+
+		// act
+		results, err := fixture.service.AcceptBatchFromCourier(ctx, nil)
+
+		// assert
+		require.ErrorIs(t, err, pvz_domain.ErrIntakeBatchSize)
+		assert.Nil(t, results)
+	})
+}
+
+func TestPvzService_AcceptFromCourier(t *testing.T) {
+	t.Parallel()
+
+	ctx := pvz_domain.WithPickupPoint(context.Background(), testPickupPointID)
+
+	t.Run("rejects missing order params without transaction", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fixture := newPvzServiceTestFixture(t)
+
+		// act
+		accepted, err := fixture.service.AcceptFromCourier(ctx, nil, "box", false)
+
+		// assert
+		require.ErrorIs(t, err, pvz_domain.ErrInvalidOrderParams)
+		assert.Nil(t, accepted)
+	})
+
+	t.Run("rejects order that has already expired", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fixture := newPvzServiceTestFixture(t)
+		params := newReceiveOrderParams()
+		params.ExpirationDate = time.Now().Add(-time.Hour)
+
+		// act
+		accepted, err := fixture.service.AcceptFromCourier(ctx, params, "box", false)
+
+		// assert
+		require.ErrorIs(t, err, pvz_domain.ErrInvalidOrderParams)
+		assert.Nil(t, accepted)
+	})
+}
+
 func TestPvzService_ProcessOrderRefund(t *testing.T) {
 	t.Parallel()
 
@@ -216,21 +453,40 @@ func TestPvzService_ProcessOrderRefund(t *testing.T) {
 		t.Parallel()
 		// arrange
 		fixture := newPvzServiceTestFixture(t)
+		fixture.expectOrderInvalidated(testPickupPointID)
 		order := newDeliveredTestOrder()
 
-		fixture.storage.EXPECT().GetRecipientOrderByID(gomock.Any(), testOrderID, testRecipientID).Return(order, nil)
+		fixture.storage.EXPECT().GetRecipientOrderByID(gomock.Any(), testPickupPointID, testOrderID, testRecipientID).Return(order, nil)
 		fixture.storage.EXPECT().AddHistoryRecord(gomock.Any(), gomock.Any(), gomock.Any())
 		fixture.storage.EXPECT().Update(gomock.Any(), gomock.Any())
 		fixture.outbox.EXPECT().AddTask(gomock.Any(), gomock.Any())
 
 		// act
-		order, err := fixture.service.ProcessOrderRefund(ctx, testOrderID, testRecipientID)
+		order, err := fixture.service.ProcessOrderRefund(ctx, testPickupPointID, testOrderID, testRecipientID, nil)
 
 		// assert
 		require.NoError(t, err)
 		assert.Equal(t, testOrderID, order.ID)
 		assert.Equal(t, pvz_domain.OrderStatusRefunded, order.Status)
 	})
+
+	t.Run("rejects refund of outdated order version", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fixture := newPvzServiceTestFixture(t)
+		order := newDeliveredTestOrder()
+		order.Version = 3
+
+		fixture.storage.EXPECT().GetRecipientOrderByID(gomock.Any(), testPickupPointID, testOrderID, testRecipientID).Return(order, nil)
+
+		// act
+		result, err := fixture.service.ProcessOrderRefund(pvz_domain.WithExpectedVersion(ctx, 2), testPickupPointID, testOrderID, testRecipientID, nil)
+
+		// assert
+		require.ErrorIs(t, err, pvz_domain.ErrOrderVersionMismatch)
+		assert.Nil(t, result)
+		assert.Equal(t, pvz_domain.OrderStatusDelivered, order.Status)
+	})
 }
 
 func TestPvzService_ProcessOrderDeliver(t *testing.T) {
@@ -242,15 +498,18 @@ func TestPvzService_ProcessOrderDeliver(t *testing.T) {
 		t.Parallel()
 		// arrange
 		fixture := newPvzServiceTestFixture(t)
+		code := fixture.expectPickupCode(t)
+		fixture.expectOrderInvalidated(testPickupPointID)
 		order := newReceivedTestOrder(time.Now().Add(time.Hour))
 
-		fixture.storage.EXPECT().GetRecipientOrderByID(gomock.Any(), testOrderID, testRecipientID).Return(order, nil)
+		fixture.storage.EXPECT().GetRecipientOrderByID(gomock.Any(), testPickupPointID, testOrderID, testRecipientID).Return(order, nil)
+		fixture.pickupCodes.EXPECT().Delete(gomock.Any(), testOrderID)
 		fixture.storage.EXPECT().Update(gomock.Any(), gomock.Any())
 		fixture.storage.EXPECT().AddHistoryRecord(gomock.Any(), gomock.Any(), testOrderID)
 		fixture.outbox.EXPECT().AddTask(gomock.Any(), gomock.Any())
 
 		// act
-		order, err := fixture.service.ProcessOrderDeliver(ctx, testOrderID, testRecipientID)
+		order, err := fixture.service.ProcessOrderDeliver(ctx, testPickupPointID, testOrderID, testRecipientID, code, nil)
 
 		// assert
 		require.NoError(t, err)
@@ -259,19 +518,113 @@ func TestPvzService_ProcessOrderDeliver(t *testing.T) {
 		assert.NotNil(t, order.DeliveredDate)
 	})
 
+	t.Run("releases storage cell on deliver", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fixture := newPvzServiceTestFixture(t)
+		code := fixture.expectPickupCode(t)
+		fixture.expectOrderInvalidated(testPickupPointID)
+		order := newReceivedTestOrder(time.Now().Add(time.Hour))
+		order.PickupPointID = testPickupPointID
+		order.Weight = 3
+		order.PlaceInCell(testCellID)
+
+		fixture.storage.EXPECT().GetRecipientOrderByID(gomock.Any(), testPickupPointID, testOrderID, testRecipientID).Return(order, nil)
+		fixture.cells.EXPECT().Release(gomock.Any(), testPickupPointID, testCellID, float64(3))
+		fixture.pickupCodes.EXPECT().Delete(gomock.Any(), testOrderID)
+		fixture.storage.EXPECT().Update(gomock.Any(), gomock.Any())
+		fixture.storage.EXPECT().AddHistoryRecord(gomock.Any(), gomock.Any(), testOrderID)
+		fixture.outbox.EXPECT().AddTask(gomock.Any(), gomock.Any())
+
+		// act
+		order, err := fixture.service.ProcessOrderDeliver(ctx, testPickupPointID, testOrderID, testRecipientID, code, nil)
+
+		// assert
+		require.NoError(t, err)
+		assert.Nil(t, order.CellID)
+	})
+
+	t.Run("keeps cell but uses up pickup code while items remain", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fixture := newPvzServiceTestFixture(t)
+		code := fixture.expectPickupCode(t)
+		fixture.expectOrderInvalidated(testPickupPointID)
+		order := newReceivedTestOrder(time.Now().Add(time.Hour))
+		order.PlaceInCell(testCellID)
+		order.Items = []*pvz_domain.OrderItem{
+			{ID: 1, SKU: "phone", Quantity: 1, Weight: 1, Status: pvz_domain.OrderStatusReceived},
+			{ID: 2, SKU: "case", Quantity: 1, Weight: 1, Status: pvz_domain.OrderStatusReceived},
+		}
+
+		fixture.storage.EXPECT().GetRecipientOrderByID(gomock.Any(), testPickupPointID, testOrderID, testRecipientID).Return(order, nil)
+		fixture.pickupCodes.EXPECT().Delete(gomock.Any(), testOrderID)
+		fixture.storage.EXPECT().Update(gomock.Any(), gomock.Any())
+		fixture.storage.EXPECT().AddHistoryRecord(gomock.Any(), gomock.Any(), testOrderID).Times(2)
+		fixture.outbox.EXPECT().AddTask(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, task *order_outbox.OrderOutboxTask) (int64, error) {
+			assert.Equal(t, testOrderID, task.OrderID)
+			require.NotNil(t, task.ItemID)
+			assert.Equal(t, int64(1), *task.ItemID)
+			assert.Equal(t, pvz_domain.OrderStatusDelivered, task.OrderStatus)
+			return int64(1), nil
+		})
+		fixture.outbox.EXPECT().AddTask(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, task *order_outbox.OrderOutboxTask) (int64, error) {
+			assert.Equal(t, testOrderID, task.OrderID)
+			assert.Nil(t, task.ItemID)
+			assert.Equal(t, pvz_domain.OrderStatusPartiallyDelivered, task.OrderStatus)
+			return int64(2), nil
+		})
+
+		// act
+		order, err := fixture.service.ProcessOrderDeliver(ctx, testPickupPointID, testOrderID, testRecipientID, code, []int64{1})
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, pvz_domain.OrderStatusPartiallyDelivered, order.Status)
+		require.NotNil(t, order.CellID)
+		assert.Equal(t, testCellID, *order.CellID)
+	})
+
+	t.Run("does not fail delivery when cache invalidation fails", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fixture := newPvzServiceTestFixture(t)
+		code := fixture.expectPickupCode(t)
+		order := newReceivedTestOrder(time.Now().Add(time.Hour))
+		cacheErr := errors.New("redis is down")
+
+		fixture.txHooks.ExpectCommitted()
+		fixture.cache.EXPECT().DeleteOrder(gomock.Any(), testPickupPointID, testOrderID).Return(cacheErr)
+		fixture.storage.EXPECT().GetRecipientOrderByID(gomock.Any(), testPickupPointID, testOrderID, testRecipientID).Return(order, nil)
+		fixture.pickupCodes.EXPECT().Delete(gomock.Any(), testOrderID)
+		fixture.storage.EXPECT().Update(gomock.Any(), gomock.Any())
+		fixture.storage.EXPECT().AddHistoryRecord(gomock.Any(), gomock.Any(), testOrderID)
+		fixture.outbox.EXPECT().AddTask(gomock.Any(), gomock.Any())
+
+		// act
+		order, err := fixture.service.ProcessOrderDeliver(ctx, testPickupPointID, testOrderID, testRecipientID, code, nil)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, pvz_domain.OrderStatusDelivered, order.Status)
+		assert.ErrorIs(t, fixture.txHooks.Err(), cacheErr)
+	})
+
 	t.Run("expires order when storage date ended", func(t *testing.T) {
 		t.Parallel()
 		// arrange
 		fixture := newPvzServiceTestFixture(t)
+		code := fixture.expectPickupCode(t)
+		fixture.expectOrderInvalidated(testPickupPointID)
 		order := newReceivedTestOrder(time.Now().Add(-time.Hour))
 
-		fixture.storage.EXPECT().GetRecipientOrderByID(gomock.Any(), testOrderID, testRecipientID).Return(order, nil)
+		fixture.storage.EXPECT().GetRecipientOrderByID(gomock.Any(), testPickupPointID, testOrderID, testRecipientID).Return(order, nil)
 		fixture.storage.EXPECT().Update(gomock.Any(), gomock.Any())
 		fixture.storage.EXPECT().AddHistoryRecord(gomock.Any(), gomock.Any(), testOrderID)
 		fixture.outbox.EXPECT().AddTask(gomock.Any(), gomock.Any())
 
 		// act
-		order, err := fixture.service.ProcessOrderDeliver(ctx, testOrderID, testRecipientID)
+		order, err := fixture.service.ProcessOrderDeliver(ctx, testPickupPointID, testOrderID, testRecipientID, code, nil)
 
 		// assert
 		require.NoError(t, err)
@@ -287,13 +640,579 @@ func TestPvzService_ProcessOrderDeliver(t *testing.T) {
 		order := newReceivedTestOrder(time.Now().Add(time.Hour))
 		order.Deliver()
 
-		fixture.storage.EXPECT().GetRecipientOrderByID(gomock.Any(), testOrderID, testRecipientID).Return(order, nil)
+		fixture.storage.EXPECT().GetRecipientOrderByID(gomock.Any(), testPickupPointID, testOrderID, testRecipientID).Return(order, nil)
 
 		// act
-		order, err := fixture.service.ProcessOrderDeliver(ctx, testOrderID, testRecipientID)
+		order, err := fixture.service.ProcessOrderDeliver(ctx, testPickupPointID, testOrderID, testRecipientID, "", nil)
 
 		// assert
 		require.Error(t, err)
 		assert.Nil(t, order)
 	})
 }
+
+// runInTx stands in for TransactionManager.Run*: it runs fn in place, without a transaction.
+func runInTx(ctx context.Context, fn func(ctxTx context.Context) error, _ ...pvz_ports.TxOption) error {
+	return fn(ctx)
+}
+
+func TestPvzService_DeliverOrders(t *testing.T) {
+	t.Parallel()
+
+	ctx := pvz_domain.WithPickupPoint(context.Background(), testPickupPointID)
+
+	t.Run("rejects wrong pickup code and counts the attempt after rollback", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fixture := newPvzServiceTestFixture(t)
+		stored, _, err := testPickupCodePolicy.Issue(testOrderID, time.Now())
+		require.NoError(t, err)
+		locked, recounted := *stored, *stored
+
+		fixture.txManager.EXPECT().RunRepeatableRead(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+		fixture.storage.EXPECT().GetRecipientOrderByID(gomock.Any(), testPickupPointID, testOrderID, testRecipientID).Return(newReceivedTestOrder(time.Now().Add(time.Hour)), nil)
+		fixture.pickupCodes.EXPECT().GetForUpdate(gomock.Any(), testOrderID).Return(&locked, nil)
+		fixture.txHooks.ExpectRolledBack()
+		fixture.txManager.EXPECT().RunReadCommitted(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(ctxTx context.Context) error, opts ...pvz_ports.TxOption) error {
+				assert.Equal(t, pvz_ports.PropagationRequiresNew, pvz_ports.NewTxOptions(opts...).Propagation)
+				return runInTx(ctx, fn)
+			})
+		fixture.pickupCodes.EXPECT().GetForUpdate(gomock.Any(), testOrderID).Return(&recounted, nil)
+		fixture.pickupCodes.EXPECT().UpdateAttempts(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, code *pvz_domain.PickupCode) error {
+			assert.Equal(t, int64(1), code.Attempts)
+			return nil
+		})
+
+		// act
+		err = fixture.service.DeliverOrders(ctx, []int64{testOrderID}, testRecipientID, map[int64]string{testOrderID: "wrong"}, nil)
+
+		// assert
+		require.ErrorIs(t, err, pvz_domain.ErrPickupCodeInvalid)
+		require.NoError(t, fixture.txHooks.Err())
+	})
+
+	t.Run("delivers order with valid pickup code and uses it up", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fixture := newPvzServiceTestFixture(t)
+		fixture.expectOrderInvalidated(testPickupPointID)
+		code := fixture.expectPickupCode(t)
+
+		fixture.txManager.EXPECT().RunRepeatableRead(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+		fixture.storage.EXPECT().GetRecipientOrderByID(gomock.Any(), testPickupPointID, testOrderID, testRecipientID).Return(newReceivedTestOrder(time.Now().Add(time.Hour)), nil)
+		fixture.pickupCodes.EXPECT().Delete(gomock.Any(), testOrderID)
+		fixture.storage.EXPECT().Update(gomock.Any(), gomock.Any())
+		fixture.storage.EXPECT().AddHistoryRecord(gomock.Any(), gomock.Any(), testOrderID)
+		fixture.outbox.EXPECT().AddTask(gomock.Any(), gomock.Any())
+
+		// act
+		err := fixture.service.DeliverOrders(ctx, []int64{testOrderID}, testRecipientID, map[int64]string{testOrderID: code}, nil)
+
+		// assert
+		require.NoError(t, err)
+	})
+
+	t.Run("asks to regenerate a pickup code that was not issued", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fixture := newPvzServiceTestFixture(t)
+
+		fixture.txManager.EXPECT().RunRepeatableRead(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+		fixture.storage.EXPECT().GetRecipientOrderByID(gomock.Any(), testPickupPointID, testOrderID, testRecipientID).Return(newReceivedTestOrder(time.Now().Add(time.Hour)), nil)
+		fixture.pickupCodes.EXPECT().GetForUpdate(gomock.Any(), testOrderID).Return(nil, pvz_domain.ErrPickupCodeNotIssued)
+
+		// act
+		err := fixture.service.DeliverOrders(ctx, []int64{testOrderID}, testRecipientID, map[int64]string{testOrderID: "123456"}, nil)
+
+		// assert
+		require.ErrorIs(t, err, pvz_domain.ErrPickupCodeNotIssued)
+		assert.False(t, pvz_domain.IsPickupCodeError(err))
+	})
+}
+
+func TestPvzService_ServeRecipient(t *testing.T) {
+	t.Parallel()
+
+	ctx := pvz_domain.WithPickupPoint(context.Background(), testPickupPointID)
+
+	t.Run("rejects an expected version for several orders", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fixture := newPvzServiceTestFixture(t)
+
+		// act
+		err := fixture.service.ServeRecipient(pvz_domain.WithExpectedVersion(ctx, 2), &ServeRecipientParams{
+			OrderIDs:    []int64{testOrderID, testOrderID + 1},
+			RecipientID: testRecipientID,
+			Action:      Refund.String(),
+		})
+
+		// assert
+		require.ErrorIs(t, err, pvz_domain.ErrInvalidOrderParams)
+	})
+
+	t.Run("checks an expected version of a single order", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fixture := newPvzServiceTestFixture(t)
+		order := newDeliveredTestOrder()
+		order.Version = 3
+
+		fixture.txManager.EXPECT().RunRepeatableRead(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+		fixture.storage.EXPECT().GetRecipientOrderByID(gomock.Any(), testPickupPointID, testOrderID, testRecipientID).Return(order, nil)
+
+		// act
+		err := fixture.service.ServeRecipient(pvz_domain.WithExpectedVersion(ctx, 2), &ServeRecipientParams{
+			OrderIDs:    []int64{testOrderID},
+			RecipientID: testRecipientID,
+			Action:      Refund.String(),
+		})
+
+		// assert
+		require.ErrorIs(t, err, pvz_domain.ErrOrderVersionMismatch)
+	})
+}
+
+func TestPvzService_GetOrderByID(t *testing.T) {
+	t.Parallel()
+
+	ctx := pvz_domain.WithPickupPoint(context.Background(), testPickupPointID)
+
+	t.Run("serves cached order to its recipient", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fixture := newPvzServiceTestFixture(t)
+		cached := newReceivedStoredTestOrder()
+
+		fixture.cache.EXPECT().GetOrder(gomock.Any(), testPickupPointID, testOrderID, testRecipientID).Return(cached, nil)
+
+		// act
+		order, err := fixture.service.GetOrderByID(ctx, testOrderID, testRecipientID)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, cached, order)
+	})
+
+	t.Run("does not serve cached order to another recipient", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fixture := newPvzServiceTestFixture(t)
+		const otherRecipientID int64 = 456
+
+		fixture.cache.EXPECT().GetOrder(gomock.Any(), testPickupPointID, testOrderID, otherRecipientID).Return(newReceivedStoredTestOrder(), nil)
+		fixture.storage.EXPECT().GetRecipientOrderByID(gomock.Any(), testPickupPointID, testOrderID, otherRecipientID).Return(nil, pvz_domain.ErrOrderNotFound)
+		fixture.cache.EXPECT().SetOrderMissing(gomock.Any(), testPickupPointID, testOrderID, otherRecipientID)
+
+		// act
+		order, err := fixture.service.GetOrderByID(ctx, testOrderID, otherRecipientID)
+
+		// assert
+		require.ErrorIs(t, err, pvz_domain.ErrOrderNotFound)
+		assert.Nil(t, order)
+	})
+
+	t.Run("caches order loaded on miss", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fixture := newPvzServiceTestFixture(t)
+		stored := newReceivedStoredTestOrder()
+
+		fixture.cache.EXPECT().GetOrder(gomock.Any(), testPickupPointID, testOrderID, testRecipientID).Return(nil, redis.Nil)
+		fixture.storage.EXPECT().GetRecipientOrderByID(gomock.Any(), testPickupPointID, testOrderID, testRecipientID).Return(stored, nil)
+		fixture.cache.EXPECT().SetOrder(gomock.Any(), stored, gomock.Any())
+
+		// act
+		order, err := fixture.service.GetOrderByID(ctx, testOrderID, testRecipientID)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, stored, order)
+	})
+
+	t.Run("answers remembered missing order without storage", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fixture := newPvzServiceTestFixture(t)
+
+		fixture.cache.EXPECT().GetOrder(gomock.Any(), testPickupPointID, testOrderID, testRecipientID).Return(nil, pvz_domain.ErrOrderNotFound)
+
+		// act
+		order, err := fixture.service.GetOrderByID(ctx, testOrderID, testRecipientID)
+
+		// assert
+		require.ErrorIs(t, err, pvz_domain.ErrOrderNotFound)
+		assert.Nil(t, order)
+	})
+}
+
+func TestPvzService_GetOrders(t *testing.T) {
+	t.Parallel()
+
+	t.Run("scopes orders to pickup point from context", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fixture := newPvzServiceTestFixture(t)
+		ctx := pvz_domain.WithPickupPoint(context.Background(), testPickupPointID)
+		pagination := &pvz_domain.Pagination{Limit: 10}
+		orders := []*pvz_domain.Order{newDeliveredTestOrder()}
+
+		fixture.cache.EXPECT().GetOrderPage(gomock.Any(), testPickupPointID, pagination).Return(nil, int64(3), redis.Nil)
+		fixture.txManager.EXPECT().RunReadOnly(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+		fixture.storage.EXPECT().GetList(gomock.Any(), testPickupPointID, pagination).Return(orders, nil)
+		fixture.cache.EXPECT().SetOrderPage(gomock.Any(), testPickupPointID, int64(3), pagination, orders)
+
+		// act
+		result, err := fixture.service.GetOrders(ctx, pagination)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, orders, result)
+	})
+
+	t.Run("serves cached page without storage", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fixture := newPvzServiceTestFixture(t)
+		ctx := pvz_domain.WithPickupPoint(context.Background(), testPickupPointID)
+		pagination := &pvz_domain.Pagination{Limit: 10}
+		orders := []*pvz_domain.Order{newDeliveredTestOrder()}
+
+		fixture.cache.EXPECT().GetOrderPage(gomock.Any(), testPickupPointID, pagination).Return(orders, int64(3), nil)
+
+		// act
+		result, err := fixture.service.GetOrders(ctx, pagination)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, orders, result)
+	})
+
+	t.Run("returns error when pickup point is missing", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fixture := newPvzServiceTestFixture(t)
+
+		// act
+		result, err := fixture.service.GetOrders(context.Background(), &pvz_domain.Pagination{Limit: 10})
+
+		// assert
+		require.ErrorIs(t, err, pvz_domain.ErrPickupPointRequired)
+		assert.Nil(t, result)
+	})
+}
+
+func TestPvzService_GetOrdersByIDs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("loads only cache misses from storage", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fixture := newPvzServiceTestFixture(t)
+		ctx := pvz_domain.WithPickupPoint(context.Background(), testPickupPointID)
+		cachedOrder := newDeliveredTestOrder()
+		cachedOrder.ID = 2
+		loadedOrder := newDeliveredTestOrder()
+
+		fixture.cache.EXPECT().GetOrders(gomock.Any(), testPickupPointID, []int64{2, testOrderID}).
+			Return(map[int64]*pvz_domain.Order{2: cachedOrder}, nil)
+		fixture.txManager.EXPECT().RunReadOnly(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+		fixture.storage.EXPECT().GetByIDs(gomock.Any(), testPickupPointID, []int64{testOrderID}).Return([]*pvz_domain.Order{loadedOrder}, nil)
+		fixture.cache.EXPECT().SetOrders(gomock.Any(), []*pvz_domain.Order{loadedOrder}, gomock.Any())
+
+		// act
+		result, err := fixture.service.GetOrdersByIDs(ctx, []int64{2, testOrderID})
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, []*pvz_domain.Order{loadedOrder, cachedOrder}, result)
+	})
+}
+
+func TestPvzService_ExportOrders(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reads orders in batches after the last sent one", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fixture := newPvzServiceTestFixture(t)
+		ctx := pvz_domain.WithPickupPoint(context.Background(), testPickupPointID)
+		filter := &pvz_domain.OrderExportFilter{Statuses: []pvz_domain.OrderStatus{pvz_domain.OrderStatusReceived}}
+		firstBatch := make([]*pvz_domain.Order, 0, exportBatchSize)
+		for id := int64(1); id <= exportBatchSize; id++ {
+			firstBatch = append(firstBatch, &pvz_domain.Order{ID: id})
+		}
+		lastOrder := &pvz_domain.Order{ID: exportBatchSize + 1}
+
+		fixture.txManager.EXPECT().RunReadOnly(gomock.Any(), gomock.Any()).DoAndReturn(runInTx).Times(2)
+		gomock.InOrder(
+			fixture.storage.EXPECT().Export(gomock.Any(), testPickupPointID, filter, int64(0), exportBatchSize).Return(firstBatch, nil),
+			fixture.storage.EXPECT().Export(gomock.Any(), testPickupPointID, filter, exportBatchSize, exportBatchSize).
+				Return([]*pvz_domain.Order{lastOrder}, nil),
+		)
+
+		// act
+		var sent []*pvz_domain.Order
+		err := fixture.service.ExportOrders(ctx, filter, 0, func(order *pvz_domain.Order) error {
+			sent = append(sent, order)
+			return nil
+		})
+
+		// assert
+		require.NoError(t, err)
+		assert.Len(t, sent, int(exportBatchSize)+1)
+		assert.Equal(t, lastOrder, sent[exportBatchSize])
+	})
+
+	t.Run("stops when send fails", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fixture := newPvzServiceTestFixture(t)
+		ctx := pvz_domain.WithPickupPoint(context.Background(), testPickupPointID)
+		sendErr := errors.New("stream closed")
+
+		fixture.txManager.EXPECT().RunReadOnly(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+		fixture.storage.EXPECT().Export(gomock.Any(), testPickupPointID, gomock.Any(), int64(0), exportBatchSize).
+			Return([]*pvz_domain.Order{{ID: 1}, {ID: 2}}, nil)
+
+		// act
+		sent := 0
+		err := fixture.service.ExportOrders(ctx, nil, 0, func(*pvz_domain.Order) error {
+			sent++
+			return sendErr
+		})
+
+		// assert
+		require.ErrorIs(t, err, sendErr)
+		assert.Equal(t, 1, sent)
+	})
+}
+
+func TestPvzService_WatchOrders(t *testing.T) {
+	t.Parallel()
+
+	t.Run("replays missed changes and skips them on the live feed", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fixture := newPvzServiceTestFixture(t)
+		ctx := pvz_domain.WithPickupPoint(context.Background(), testPickupPointID)
+		live := make(chan *pvz_domain.OrderStatusChange, 2)
+		live <- &pvz_domain.OrderStatusChange{RecordID: 6}
+		live <- &pvz_domain.OrderStatusChange{RecordID: 7}
+		close(live)
+		unsubscribed := false
+
+		fixture.statusFeed.EXPECT().Subscribe(testPickupPointID).Return((<-chan *pvz_domain.OrderStatusChange)(live), func() { unsubscribed = true })
+		fixture.txManager.EXPECT().RunReadOnly(gomock.Any(), gomock.Any()).DoAndReturn(runInTx)
+		fixture.storage.EXPECT().GetStatusChanges(gomock.Any(), testPickupPointID, int64(4), watchReplayBatchSize).
+			Return([]*pvz_domain.OrderStatusChange{{RecordID: 5}, {RecordID: 6}}, nil)
+
+		// act
+		var sent []int64
+		err := fixture.service.WatchOrders(ctx, 4, func(change *pvz_domain.OrderStatusChange) error {
+			sent = append(sent, change.RecordID)
+			return nil
+		})
+
+		// assert
+		require.ErrorIs(t, err, pvz_domain.ErrStatusFeedInterrupted)
+		assert.Equal(t, []int64{5, 6, 7}, sent)
+		assert.True(t, unsubscribed)
+	})
+
+	t.Run("returns when the context is done", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fixture := newPvzServiceTestFixture(t)
+		ctx, cancel := context.WithCancel(pvz_domain.WithPickupPoint(context.Background(), testPickupPointID))
+		cancel()
+
+		fixture.statusFeed.EXPECT().Subscribe(testPickupPointID).
+			Return((<-chan *pvz_domain.OrderStatusChange)(make(chan *pvz_domain.OrderStatusChange)), func() {})
+
+		// act
+		err := fixture.service.WatchOrders(ctx, 0, func(*pvz_domain.OrderStatusChange) error {
+			return errors.New("unexpected change")
+		})
+
+		// assert
+		require.NoError(t, err)
+	})
+}
+
+func TestPvzService_ProcessOrderTransfer(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	const targetPickupPointID int64 = 8
+
+	t.Run("success process transfer", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fixture := newPvzServiceTestFixture(t)
+		fixture.expectOrderInvalidated(testPickupPointID, targetPickupPointID)
+		order := newReceivedTestOrder(time.Now().Add(time.Hour))
+		order.PickupPointID = testPickupPointID
+
+		fixture.storage.EXPECT().GetByID(gomock.Any(), testPickupPointID, testOrderID).Return(order, nil)
+		fixture.storage.EXPECT().MoveToPickupPoint(gomock.Any(), testOrderID, testPickupPointID, targetPickupPointID)
+		fixture.storage.EXPECT().Update(gomock.Any(), gomock.Any())
+		gomock.InOrder(
+			fixture.storage.EXPECT().AddHistoryRecord(gomock.Any(), gomock.Any(), testOrderID).DoAndReturn(func(_ context.Context, record *pvz_domain.OrderRecord, _ int64) (int64, error) {
+				assert.Equal(t, pvz_domain.OrderStatusTransferred, record.Status)
+				assert.Equal(t, testPickupPointID, record.PickupPointID)
+				return int64(1), nil
+			}),
+			fixture.storage.EXPECT().AddHistoryRecord(gomock.Any(), gomock.Any(), testOrderID).DoAndReturn(func(_ context.Context, record *pvz_domain.OrderRecord, _ int64) (int64, error) {
+				assert.Equal(t, pvz_domain.OrderStatusInTransfer, record.Status)
+				assert.Equal(t, targetPickupPointID, record.PickupPointID)
+				return int64(2), nil
+			}),
+		)
+		fixture.outbox.EXPECT().AddTask(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, task *order_outbox.OrderOutboxTask) (int64, error) {
+			assert.Equal(t, testOrderID, task.OrderID)
+			return int64(1), nil
+		}).Times(2)
+
+		// act
+		order, err := fixture.service.ProcessOrderTransfer(ctx, testPickupPointID, testOrderID, targetPickupPointID)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, targetPickupPointID, order.PickupPointID)
+		assert.Equal(t, pvz_domain.OrderStatusInTransfer, order.Status)
+	})
+
+	t.Run("returns error when order is not received", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fixture := newPvzServiceTestFixture(t)
+		order := newDeliveredTestOrder()
+
+		fixture.storage.EXPECT().GetByID(gomock.Any(), testPickupPointID, testOrderID).Return(order, nil)
+
+		// act
+		order, err := fixture.service.ProcessOrderTransfer(ctx, testPickupPointID, testOrderID, targetPickupPointID)
+
+		// assert
+		require.Error(t, err)
+		assert.Nil(t, order)
+	})
+
+	t.Run("returns error when target is the same pickup point", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fixture := newPvzServiceTestFixture(t)
+
+		// act
+		order, err := fixture.service.ProcessOrderTransfer(ctx, testPickupPointID, testOrderID, testPickupPointID)
+
+		// assert
+		require.Error(t, err)
+		assert.Nil(t, order)
+	})
+}
+
+func TestPvzService_ProcessTransferAccept(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	t.Run("success accept transfer", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fixture := newPvzServiceTestFixture(t)
+		fixture.expectOrderInvalidated(testPickupPointID)
+		order := newReceivedTestOrder(time.Now().Add(time.Hour))
+		order.StartTransfer(testPickupPointID)
+
+		fixture.storage.EXPECT().GetByID(gomock.Any(), testPickupPointID, testOrderID).Return(order, nil)
+		fixture.cells.EXPECT().Allocate(gomock.Any(), testPickupPointID, pvz_domain.CellSizeMedium, gomock.Any()).Return(newTestCell(), nil)
+		fixture.storage.EXPECT().Update(gomock.Any(), gomock.Any())
+		fixture.storage.EXPECT().AddHistoryRecord(gomock.Any(), gomock.Any(), testOrderID)
+		fixture.outbox.EXPECT().AddTask(gomock.Any(), gomock.Any())
+
+		// act
+		order, err := fixture.service.ProcessTransferAccept(ctx, testPickupPointID, testOrderID)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, pvz_domain.OrderStatusReceived, order.Status)
+	})
+}
+
+func TestPvzService_GetOrderCell(t *testing.T) {
+	t.Parallel()
+
+	ctx := pvz_domain.WithPickupPoint(context.Background(), testPickupPointID)
+
+	t.Run("returns the cell the order is stored in", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fixture := newPvzServiceTestFixture(t)
+		order := newReceivedTestOrder(time.Now().Add(time.Hour))
+		order.PlaceInCell(testCellID)
+
+		fixture.storage.EXPECT().GetByID(gomock.Any(), testPickupPointID, testOrderID).Return(order, nil)
+		fixture.cells.EXPECT().GetByID(gomock.Any(), testPickupPointID, testCellID).Return(newTestCell(), nil)
+
+		// act
+		cell, err := fixture.service.GetOrderCell(ctx, testOrderID)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, newTestCell(), cell)
+	})
+
+	t.Run("reports an order outside of a cell as not in cell", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fixture := newPvzServiceTestFixture(t)
+		fixture.storage.EXPECT().GetByID(gomock.Any(), testPickupPointID, testOrderID).Return(newReceivedTestOrder(time.Now().Add(time.Hour)), nil)
+
+		// act
+		cell, err := fixture.service.GetOrderCell(ctx, testOrderID)
+
+		// assert
+		require.ErrorIs(t, err, pvz_domain.ErrOrderNotInCell)
+		assert.Nil(t, cell)
+	})
+}
+
+func TestPvzService_AddCell(t *testing.T) {
+	t.Parallel()
+
+	t.Run("provisions the cell at the pickup point of the request", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fixture := newPvzServiceTestFixture(t)
+		ctx := pvz_domain.WithPickupPoint(context.Background(), testPickupPointID)
+		cell := &pvz_domain.Cell{Code: "A-01", Size: pvz_domain.CellSizeSmall, Capacity: 4, MaxWeight: 30}
+
+		fixture.cells.EXPECT().Add(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, c *pvz_domain.Cell) (*pvz_domain.Cell, error) {
+			assert.Equal(t, testPickupPointID, c.PickupPointID)
+			return newTestCell(), nil
+		})
+
+		// act
+		result, err := fixture.service.AddCell(ctx, cell)
+
+		// assert
+		require.NoError(t, err)
+		assert.Equal(t, newTestCell(), result)
+	})
+
+	t.Run("requires a pickup point", func(t *testing.T) {
+		t.Parallel()
+		// arrange
+		fixture := newPvzServiceTestFixture(t)
+
+		// act
+		result, err := fixture.service.AddCell(context.Background(), &pvz_domain.Cell{Code: "A-01"})
+
+		// assert
+		require.ErrorIs(t, err, pvz_domain.ErrPickupPointRequired)
+		assert.Nil(t, result)
+	})
+}
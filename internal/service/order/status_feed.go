@@ -0,0 +1,13 @@
+//go:generate mockgen -source=status_feed.go -destination=mocks/status_feed.go -package=mocks
+
+package pvz_order_service
+
+import (
+	"github.com/Staspol216/gh1/internal/domain/order"
+)
+
+// StatusFeed delivers committed status changes of orders. The channel is closed
+// when the subscriber is cut off.
+type StatusFeed interface {
+	Subscribe(pickupPointID int64) (<-chan *pvz_domain.OrderStatusChange, func())
+}
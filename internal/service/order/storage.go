@@ -12,19 +12,31 @@ import (
 // OrderStorage defines storage operations for orders used across the application.
 // Placing this in the domain layer keeps the dependency direction inward.
 type OrderStorage interface {
-	GetAll(ctx context.Context) ([]*pvz_domain.Order, error)
-	GetList(ctx context.Context, pagination *pvz_domain.Pagination) ([]*pvz_domain.Order, error)
+	GetAll(ctx context.Context, pickupPointID int64) ([]*pvz_domain.Order, error)
+	GetList(ctx context.Context, pickupPointID int64, pagination *pvz_domain.Pagination) ([]*pvz_domain.Order, error)
 	Add(ctx context.Context, newOrder *pvz_domain.Order) (int64, error)
+	AddBatch(ctx context.Context, newOrders []*pvz_domain.Order) error
 	AddHistoryRecord(ctx context.Context, record *pvz_domain.OrderRecord, orderId int64) (int64, error)
-	Delete(ctx context.Context, orderId int64) error
+	AddHistoryRecords(ctx context.Context, records []*pvz_domain.OrderRecord, orderIds []int64) error
+	Delete(ctx context.Context, pickupPointID int64, orderId int64) error
 	Update(ctx context.Context, updatedOrder *pvz_domain.Order) error
-	GetByID(ctx context.Context, orderId int64) (*pvz_domain.Order, error)
-	GetRecipientOrderByID(ctx context.Context, id int64, recipientId int64) (*pvz_domain.Order, error)
-	GetByIDs(ctx context.Context, orderIds []int64) ([]*pvz_domain.Order, error)
+	MoveToPickupPoint(ctx context.Context, orderId int64, fromPickupPointID int64, toPickupPointID int64) error
+	GetByID(ctx context.Context, pickupPointID int64, orderId int64) (*pvz_domain.Order, error)
+	GetRecipientOrderByID(ctx context.Context, pickupPointID int64, id int64, recipientId int64) (*pvz_domain.Order, error)
+	GetByIDs(ctx context.Context, pickupPointID int64, orderIds []int64) ([]*pvz_domain.Order, error)
+	Export(ctx context.Context, pickupPointID int64, filter *pvz_domain.OrderExportFilter, afterID int64, limit int64) ([]*pvz_domain.Order, error)
+	GetStatusChanges(ctx context.Context, pickupPointID int64, afterRecordID int64, limit int64) ([]*pvz_domain.OrderStatusChange, error)
 }
 
 type OrdersCache interface {
-	GetOrder(ctx context.Context, id interface{}) (*pvz_domain.Order, error)
-	SetOrder(ctx context.Context, order *pvz_domain.Order, ttl time.Duration) error
-	DeleteOrder(ctx context.Context, orderId int64) error
+	GetOrder(ctx context.Context, pickupPointID int64, id int64, recipientId int64) (*pvz_domain.Order, error)
+	GetOrders(ctx context.Context, pickupPointID int64, ids []int64) (map[int64]*pvz_domain.Order, error)
+	SetOrder(ctx context.Context, order *pvz_domain.Order, loadTime time.Duration) error
+	SetOrders(ctx context.Context, orders []*pvz_domain.Order, loadTime time.Duration) error
+	SetOrderMissing(ctx context.Context, pickupPointID int64, id int64, recipientId int64) error
+	DeleteOrder(ctx context.Context, pickupPointID int64, orderId int64) error
+	ForgetMissingOrders(ctx context.Context, pickupPointID int64, ids []int64) error
+	InvalidateOrderPages(ctx context.Context, pickupPointID int64) error
+	GetOrderPage(ctx context.Context, pickupPointID int64, pagination *pvz_domain.Pagination) ([]*pvz_domain.Order, int64, error)
+	SetOrderPage(ctx context.Context, pickupPointID int64, generation int64, pagination *pvz_domain.Pagination, orders []*pvz_domain.Order) error
 }
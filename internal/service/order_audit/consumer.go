@@ -59,6 +59,9 @@ func (c *OrderAuditLogPartitionConsumer) log(job *sarama.ConsumerMessage) {
 		span.SetTag("order_status", string(task.OrderStatus))
 		app_logger.MyLogger.Info("audit log record",
 			zap.Int64("task_id", task.ID),
+			zap.Int64("order_id", task.OrderID),
+			zap.Int64("pickup_point_id", task.PickupPointID),
+			zap.Int64p("item_id", task.ItemID),
 			zap.String("status", task.Status),
 			zap.String("order_status", string(task.OrderStatus)),
 			zap.String("description", task.Description),
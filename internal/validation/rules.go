@@ -0,0 +1,128 @@
+package pvz_validation
+
+import (
+	"time"
+
+	"github.com/Staspol216/gh1/internal/domain/order"
+	"github.com/Staspol216/gh1/internal/service/order"
+)
+
+// MaxPageLimit caps how many orders one list call returns.
+const MaxPageLimit int64 = 100
+
+// packagingTypes lists the accepted packaging; an empty one is packed as a box.
+var packagingTypes = []string{"box", "bag", "membrana"}
+
+// The rule sets below are shared by the HTTP and gRPC transports. Field names
+// follow the API; parent names nested messages, or is empty at the top level.
+
+func OrderParams(parent string, p *pvz_domain.OrderParams, now time.Time) []Rule {
+	if p == nil {
+		return []Rule{Required(parent, false)}
+	}
+
+	rules := []Rule{
+		Positive(Nested(parent, "recipient_id"), p.RecipientId),
+		After(Nested(parent, "expiration_date"), p.ExpirationDate, now),
+		NonNegative(Nested(parent, "worth"), p.Worth.Amount),
+	}
+	// Weight of an order with items is the sum of theirs.
+	if len(p.Items) == 0 {
+		rules = append(rules, Positive(Nested(parent, "weight"), p.Weight))
+	}
+	for i, item := range p.Items {
+		rules = append(rules, OrderItemParams(Index(Nested(parent, "items"), i), item)...)
+	}
+	return rules
+}
+
+func OrderItemParams(parent string, p pvz_domain.OrderItemParams) []Rule {
+	return []Rule{
+		Required(Nested(parent, "sku"), p.SKU != ""),
+		Positive(Nested(parent, "quantity"), p.Quantity),
+		NonNegative(Nested(parent, "price"), p.Price.Amount),
+		Positive(Nested(parent, "weight"), p.Weight),
+	}
+}
+
+func PackagingType(field string, packagingType string) Rule {
+	if packagingType == "" {
+		return Rule{Field: field, Valid: true}
+	}
+	return OneOf(field, packagingType, packagingTypes...)
+}
+
+// IntakeBatch checks only the size of a courier delivery; its parcels are rejected
+// one by one during intake.
+func IntakeBatch(field string, size int) []Rule {
+	return []Rule{
+		SizeBetween(field, size, 1, pvz_domain.MaxIntakeBatchSize),
+	}
+}
+
+func ServeRecipient(p *pvz_order_service.ServeRecipientParams) []Rule {
+	rules := []Rule{
+		Required("order_ids", len(p.OrderIDs) > 0),
+		Positive("recipient_id", p.RecipientID),
+		OneOf("action", p.Action, pvz_order_service.Deliver.String(), pvz_order_service.Refund.String()),
+	}
+	for i, orderID := range p.OrderIDs {
+		rules = append(rules, Positive(Index("order_ids", i), orderID))
+	}
+	return rules
+}
+
+func Pagination(p *pvz_domain.Pagination) []Rule {
+	return []Rule{
+		NonNegative("offset", p.Offset),
+		NonNegative("limit", p.Limit),
+		AtMost("limit", p.Limit, MaxPageLimit),
+	}
+}
+
+func Cell(code string, size pvz_domain.CellSize, capacity int64, maxWeight float64) []Rule {
+	return []Rule{
+		Required("code", code != ""),
+		{
+			Field:       "size",
+			Valid:       size >= pvz_domain.CellSizeSmall && size <= pvz_domain.CellSizeLarge,
+			Description: "must be 1, 2 or 3",
+		},
+		Positive("capacity", capacity),
+		Positive("max_weight", maxWeight),
+	}
+}
+
+func OrderRef(orderID int64) []Rule {
+	return []Rule{
+		Positive("order_id", orderID),
+	}
+}
+
+func RecipientOrderRef(orderID int64, recipientID int64) []Rule {
+	return []Rule{
+		Positive("order_id", orderID),
+		Positive("recipient_id", recipientID),
+	}
+}
+
+func Transfer(orderID int64, targetField string, targetPickupPointID int64) []Rule {
+	return []Rule{
+		Positive("order_id", orderID),
+		Positive(targetField, targetPickupPointID),
+	}
+}
+
+func ExportFilter(f *pvz_domain.OrderExportFilter) []Rule {
+	rules := []Rule{
+		NonNegative("recipient_id", f.RecipientID),
+	}
+	if f.ExpiresAfter != nil && f.ExpiresBefore != nil {
+		rules = append(rules, Rule{
+			Field:       "expires_before",
+			Valid:       f.ExpiresBefore.After(*f.ExpiresAfter),
+			Description: "must be later than expires_after",
+		})
+	}
+	return rules
+}
@@ -0,0 +1,99 @@
+package pvz_validation
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Staspol216/gh1/internal/domain/order"
+	"github.com/Staspol216/gh1/internal/service/order"
+)
+
+func TestOrderParams(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		params *pvz_domain.OrderParams
+		want   []Violation
+	}{
+		{
+			name: "accepts valid order",
+			params: &pvz_domain.OrderParams{
+				RecipientId:    1,
+				ExpirationDate: now.Add(time.Hour),
+				Weight:         2,
+				Worth:          pvz_domain.NewMoney(100, pvz_domain.DefaultCurrency),
+			},
+		},
+		{
+			name:   "rejects missing order",
+			params: nil,
+			want:   []Violation{{Field: "order", Description: "is required"}},
+		},
+		{
+			name: "rejects negative weight and past expiration date",
+			params: &pvz_domain.OrderParams{
+				RecipientId:    1,
+				ExpirationDate: now.Add(-time.Hour),
+				Weight:         -1,
+			},
+			want: []Violation{
+				{Field: "order.expiration_date", Description: "can't be in the past"},
+				{Field: "order.weight", Description: "must be positive"},
+			},
+		},
+		{
+			name: "names rejected items by index",
+			params: &pvz_domain.OrderParams{
+				RecipientId:    1,
+				ExpirationDate: now.Add(time.Hour),
+				Items: []pvz_domain.OrderItemParams{
+					{SKU: "A", Quantity: 1, Weight: 1},
+					{SKU: "", Quantity: 1, Weight: 1},
+				},
+			},
+			want: []Violation{{Field: "order.items[1].sku", Description: "is required"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check(OrderParams("order", tt.params, now)...)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Check() error = %v, want nil", err)
+				}
+				return
+			}
+
+			var validationErr *Error
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("Check() error = %v, want *Error", err)
+			}
+			if !reflect.DeepEqual(validationErr.Violations, tt.want) {
+				t.Errorf("Check() violations = %v, want %v", validationErr.Violations, tt.want)
+			}
+		})
+	}
+}
+
+func TestServeRecipient(t *testing.T) {
+	err := Check(ServeRecipient(&pvz_order_service.ServeRecipientParams{
+		OrderIDs:    []int64{1, -2},
+		RecipientID: 3,
+		Action:      "",
+	})...)
+
+	var validationErr *Error
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Check() error = %v, want *Error", err)
+	}
+	want := []Violation{
+		{Field: "action", Description: "must be one of deliver, refund"},
+		{Field: "order_ids[1]", Description: "must be positive"},
+	}
+	if !reflect.DeepEqual(validationErr.Violations, want) {
+		t.Errorf("Check() violations = %v, want %v", validationErr.Violations, want)
+	}
+}
@@ -0,0 +1,90 @@
+package pvz_validation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Violation is a field of a request that broke a rule. Field is the path of the
+// field as clients name it, e.g. order.items[0].sku.
+type Violation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+// Error lists every violation of a rejected request.
+type Error struct {
+	Violations []Violation
+}
+
+func (e *Error) Error() string {
+	descriptions := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		descriptions = append(descriptions, v.Field+" "+v.Description)
+	}
+	return "invalid request: " + strings.Join(descriptions, "; ")
+}
+
+// Rule is a checked condition on one field of a request.
+type Rule struct {
+	Field       string
+	Valid       bool
+	Description string
+}
+
+// Check returns an *Error listing the broken rules, or nil if all of them hold.
+func Check(rules ...Rule) error {
+	var violations []Violation
+	for _, rule := range rules {
+		if !rule.Valid {
+			violations = append(violations, Violation{Field: rule.Field, Description: rule.Description})
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	return &Error{Violations: violations}
+}
+
+func Required(field string, present bool) Rule {
+	return Rule{Field: field, Valid: present, Description: "is required"}
+}
+
+func Positive[T int64 | float64](field string, value T) Rule {
+	return Rule{Field: field, Valid: value > 0, Description: "must be positive"}
+}
+
+func NonNegative[T int64 | float64](field string, value T) Rule {
+	return Rule{Field: field, Valid: value >= 0, Description: "can't be negative"}
+}
+
+func AtMost(field string, value int64, limit int64) Rule {
+	return Rule{Field: field, Valid: value <= limit, Description: fmt.Sprintf("can't exceed %d", limit)}
+}
+
+func SizeBetween(field string, size int, lo int, hi int) Rule {
+	return Rule{Field: field, Valid: size >= lo && size <= hi, Description: fmt.Sprintf("must hold %d to %d elements", lo, hi)}
+}
+
+func OneOf(field string, value string, allowed ...string) Rule {
+	return Rule{Field: field, Valid: slices.Contains(allowed, value), Description: "must be one of " + strings.Join(allowed, ", ")}
+}
+
+func After(field string, value time.Time, moment time.Time) Rule {
+	return Rule{Field: field, Valid: value.After(moment), Description: "can't be in the past"}
+}
+
+// Index names an element of a repeated field.
+func Index(field string, i int) string {
+	return fmt.Sprintf("%s[%d]", field, i)
+}
+
+// Nested names a field of a nested message; an empty parent names a top-level field.
+func Nested(parent string, field string) string {
+	if parent == "" {
+		return field
+	}
+	return parent + "." + field
+}
@@ -25,12 +25,16 @@ const (
 type OrderStatus int32
 
 const (
-	OrderStatus_RECEVIED     OrderStatus = 0
-	OrderStatus_RETURNED     OrderStatus = 1
-	OrderStatus_DELIVERED    OrderStatus = 2
-	OrderStatus_REFUNDED     OrderStatus = 3
-	OrderStatus_STRAGE_ENDED OrderStatus = 4
-	OrderStatus_NONE         OrderStatus = 5
+	OrderStatus_RECEVIED            OrderStatus = 0
+	OrderStatus_RETURNED            OrderStatus = 1
+	OrderStatus_DELIVERED           OrderStatus = 2
+	OrderStatus_REFUNDED            OrderStatus = 3
+	OrderStatus_STRAGE_ENDED        OrderStatus = 4
+	OrderStatus_NONE                OrderStatus = 5
+	OrderStatus_IN_TRANSFER         OrderStatus = 6
+	OrderStatus_TRANSFERRED         OrderStatus = 7
+	OrderStatus_PARTIALLY_DELIVERED OrderStatus = 8
+	OrderStatus_PARTIALLY_REFUNDED  OrderStatus = 9
 )
 
 // Enum value maps for OrderStatus.
@@ -42,14 +46,22 @@ var (
 		3: "REFUNDED",
 		4: "STRAGE_ENDED",
 		5: "NONE",
+		6: "IN_TRANSFER",
+		7: "TRANSFERRED",
+		8: "PARTIALLY_DELIVERED",
+		9: "PARTIALLY_REFUNDED",
 	}
 	OrderStatus_value = map[string]int32{
-		"RECEVIED":     0,
-		"RETURNED":     1,
-		"DELIVERED":    2,
-		"REFUNDED":     3,
-		"STRAGE_ENDED": 4,
-		"NONE":         5,
+		"RECEVIED":            0,
+		"RETURNED":            1,
+		"DELIVERED":           2,
+		"REFUNDED":            3,
+		"STRAGE_ENDED":        4,
+		"NONE":                5,
+		"IN_TRANSFER":         6,
+		"TRANSFERRED":         7,
+		"PARTIALLY_DELIVERED": 8,
+		"PARTIALLY_REFUNDED":  9,
 	}
 )
 
@@ -85,6 +97,8 @@ type OrderRecord struct {
 	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
 	Status        OrderStatus            `protobuf:"varint,2,opt,name=status,proto3,enum=orders.proto.OrderStatus" json:"status,omitempty"`
 	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	ItemId        *int64                 `protobuf:"varint,4,opt,name=item_id,json=itemId,proto3,oneof" json:"item_id,omitempty"`
+	PickupPointId int64                  `protobuf:"varint,5,opt,name=pickup_point_id,json=pickupPointId,proto3" json:"pickup_point_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -140,6 +154,73 @@ func (x *OrderRecord) GetDescription() string {
 	return ""
 }
 
+func (x *OrderRecord) GetItemId() int64 {
+	if x != nil && x.ItemId != nil {
+		return *x.ItemId
+	}
+	return 0
+}
+
+func (x *OrderRecord) GetPickupPointId() int64 {
+	if x != nil {
+		return x.PickupPointId
+	}
+	return 0
+}
+
+// Money is an exact amount in minor currency units (kopecks for RUB).
+type Money struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MinorUnits    int64                  `protobuf:"varint,1,opt,name=minor_units,json=minorUnits,proto3" json:"minor_units,omitempty"`
+	CurrencyCode  string                 `protobuf:"bytes,2,opt,name=currency_code,json=currencyCode,proto3" json:"currency_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Money) Reset() {
+	*x = Money{}
+	mi := &file_cmd_api_orders_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Money) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Money) ProtoMessage() {}
+
+func (x *Money) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_api_orders_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Money.ProtoReflect.Descriptor instead.
+func (*Money) Descriptor() ([]byte, []int) {
+	return file_cmd_api_orders_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Money) GetMinorUnits() int64 {
+	if x != nil {
+		return x.MinorUnits
+	}
+	return 0
+}
+
+func (x *Money) GetCurrencyCode() string {
+	if x != nil {
+		return x.CurrencyCode
+	}
+	return ""
+}
+
 type Order struct {
 	state          protoimpl.MessageState `protogen:"open.v1"`
 	Id             int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -150,14 +231,22 @@ type Order struct {
 	Status         OrderStatus            `protobuf:"varint,6,opt,name=status,proto3,enum=orders.proto.OrderStatus" json:"status,omitempty"`
 	History        []*OrderRecord         `protobuf:"bytes,7,rep,name=history,proto3" json:"history,omitempty"`
 	Weight         float64                `protobuf:"fixed64,8,opt,name=Weight,proto3" json:"Weight,omitempty"`
-	Worth          float64                `protobuf:"fixed64,9,opt,name=Worth,proto3" json:"Worth,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// Deprecated: Marked as deprecated in cmd/api/orders.proto.
+	Worth      float64      `protobuf:"fixed64,9,opt,name=Worth,proto3" json:"Worth,omitempty"`
+	WorthMoney *Money       `protobuf:"bytes,10,opt,name=worth_money,json=worthMoney,proto3" json:"worth_money,omitempty"`
+	Items      []*OrderItem `protobuf:"bytes,11,rep,name=items,proto3" json:"items,omitempty"`
+	// version is bumped on every change; HTTP exposes it as the order ETag.
+	Version       int64                  `protobuf:"varint,12,opt,name=version,proto3" json:"version,omitempty"`
+	PickupPointId int64                  `protobuf:"varint,13,opt,name=pickup_point_id,json=pickupPointId,proto3" json:"pickup_point_id,omitempty"`
+	ReturnedDate  *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=returned_date,json=returnedDate,proto3" json:"returned_date,omitempty"`
+	CellId        *int64                 `protobuf:"varint,15,opt,name=cell_id,json=cellId,proto3,oneof" json:"cell_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Order) Reset() {
 	*x = Order{}
-	mi := &file_cmd_api_orders_proto_msgTypes[1]
+	mi := &file_cmd_api_orders_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -169,7 +258,7 @@ func (x *Order) String() string {
 func (*Order) ProtoMessage() {}
 
 func (x *Order) ProtoReflect() protoreflect.Message {
-	mi := &file_cmd_api_orders_proto_msgTypes[1]
+	mi := &file_cmd_api_orders_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -182,7 +271,7 @@ func (x *Order) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Order.ProtoReflect.Descriptor instead.
 func (*Order) Descriptor() ([]byte, []int) {
-	return file_cmd_api_orders_proto_rawDescGZIP(), []int{1}
+	return file_cmd_api_orders_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *Order) GetId() int64 {
@@ -241,6 +330,7 @@ func (x *Order) GetWeight() float64 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in cmd/api/orders.proto.
 func (x *Order) GetWorth() float64 {
 	if x != nil {
 		return x.Worth
@@ -248,79 +338,75 @@ func (x *Order) GetWorth() float64 {
 	return 0
 }
 
-type GetOrdersRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Limit         int64                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
-	Offset        int64                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *GetOrdersRequest) Reset() {
-	*x = GetOrdersRequest{}
-	mi := &file_cmd_api_orders_proto_msgTypes[2]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *GetOrdersRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *Order) GetWorthMoney() *Money {
+	if x != nil {
+		return x.WorthMoney
+	}
+	return nil
 }
 
-func (*GetOrdersRequest) ProtoMessage() {}
-
-func (x *GetOrdersRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_cmd_api_orders_proto_msgTypes[2]
+func (x *Order) GetItems() []*OrderItem {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Items
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use GetOrdersRequest.ProtoReflect.Descriptor instead.
-func (*GetOrdersRequest) Descriptor() ([]byte, []int) {
-	return file_cmd_api_orders_proto_rawDescGZIP(), []int{2}
+func (x *Order) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
 }
 
-func (x *GetOrdersRequest) GetLimit() int64 {
+func (x *Order) GetPickupPointId() int64 {
 	if x != nil {
-		return x.Limit
+		return x.PickupPointId
 	}
 	return 0
 }
 
-func (x *GetOrdersRequest) GetOffset() int64 {
+func (x *Order) GetReturnedDate() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Offset
+		return x.ReturnedDate
+	}
+	return nil
+}
+
+func (x *Order) GetCellId() int64 {
+	if x != nil && x.CellId != nil {
+		return *x.CellId
 	}
 	return 0
 }
 
-type GetOrdersResponse struct {
+// OrderItem is a line of a multi-item order; price and weight are per unit.
+type OrderItem struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Sku           string                 `protobuf:"bytes,2,opt,name=sku,proto3" json:"sku,omitempty"`
+	Quantity      int64                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Price         *Money                 `protobuf:"bytes,4,opt,name=price,proto3" json:"price,omitempty"`
+	Weight        float64                `protobuf:"fixed64,5,opt,name=weight,proto3" json:"weight,omitempty"`
+	Status        OrderStatus            `protobuf:"varint,6,opt,name=status,proto3,enum=orders.proto.OrderStatus" json:"status,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetOrdersResponse) Reset() {
-	*x = GetOrdersResponse{}
+func (x *OrderItem) Reset() {
+	*x = OrderItem{}
 	mi := &file_cmd_api_orders_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetOrdersResponse) String() string {
+func (x *OrderItem) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetOrdersResponse) ProtoMessage() {}
+func (*OrderItem) ProtoMessage() {}
 
-func (x *GetOrdersResponse) ProtoReflect() protoreflect.Message {
+func (x *OrderItem) ProtoReflect() protoreflect.Message {
 	mi := &file_cmd_api_orders_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -332,41 +418,75 @@ func (x *GetOrdersResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetOrdersResponse.ProtoReflect.Descriptor instead.
-func (*GetOrdersResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use OrderItem.ProtoReflect.Descriptor instead.
+func (*OrderItem) Descriptor() ([]byte, []int) {
 	return file_cmd_api_orders_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *GetOrdersResponse) GetOrders() []*Order {
+func (x *OrderItem) GetId() int64 {
 	if x != nil {
-		return x.Orders
+		return x.Id
+	}
+	return 0
+}
+
+func (x *OrderItem) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *OrderItem) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *OrderItem) GetPrice() *Money {
+	if x != nil {
+		return x.Price
 	}
 	return nil
 }
 
-type CreateOrderRequest struct {
-	state            protoimpl.MessageState          `protogen:"open.v1"`
-	Order            *CreateOrderRequest_OrderParams `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
-	PackagingType    string                          `protobuf:"bytes,2,opt,name=packaging_type,json=packagingType,proto3" json:"packaging_type,omitempty"`
-	MembranaIncluded bool                            `protobuf:"varint,3,opt,name=membrana_included,json=membranaIncluded,proto3" json:"membrana_included,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+func (x *OrderItem) GetWeight() float64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
 }
 
-func (x *CreateOrderRequest) Reset() {
-	*x = CreateOrderRequest{}
+func (x *OrderItem) GetStatus() OrderStatus {
+	if x != nil {
+		return x.Status
+	}
+	return OrderStatus_RECEVIED
+}
+
+type GetOrdersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int64                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int64                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrdersRequest) Reset() {
+	*x = GetOrdersRequest{}
 	mi := &file_cmd_api_orders_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateOrderRequest) String() string {
+func (x *GetOrdersRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateOrderRequest) ProtoMessage() {}
+func (*GetOrdersRequest) ProtoMessage() {}
 
-func (x *CreateOrderRequest) ProtoReflect() protoreflect.Message {
+func (x *GetOrdersRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_cmd_api_orders_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -378,53 +498,46 @@ func (x *CreateOrderRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateOrderRequest.ProtoReflect.Descriptor instead.
-func (*CreateOrderRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetOrdersRequest.ProtoReflect.Descriptor instead.
+func (*GetOrdersRequest) Descriptor() ([]byte, []int) {
 	return file_cmd_api_orders_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *CreateOrderRequest) GetOrder() *CreateOrderRequest_OrderParams {
-	if x != nil {
-		return x.Order
-	}
-	return nil
-}
-
-func (x *CreateOrderRequest) GetPackagingType() string {
+func (x *GetOrdersRequest) GetLimit() int64 {
 	if x != nil {
-		return x.PackagingType
+		return x.Limit
 	}
-	return ""
+	return 0
 }
 
-func (x *CreateOrderRequest) GetMembranaIncluded() bool {
+func (x *GetOrdersRequest) GetOffset() int64 {
 	if x != nil {
-		return x.MembranaIncluded
+		return x.Offset
 	}
-	return false
+	return 0
 }
 
-type CreateOrderResponse struct {
+type GetOrdersResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	OrderId       int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateOrderResponse) Reset() {
-	*x = CreateOrderResponse{}
+func (x *GetOrdersResponse) Reset() {
+	*x = GetOrdersResponse{}
 	mi := &file_cmd_api_orders_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateOrderResponse) String() string {
+func (x *GetOrdersResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateOrderResponse) ProtoMessage() {}
+func (*GetOrdersResponse) ProtoMessage() {}
 
-func (x *CreateOrderResponse) ProtoReflect() protoreflect.Message {
+func (x *GetOrdersResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_cmd_api_orders_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -436,41 +549,42 @@ func (x *CreateOrderResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateOrderResponse.ProtoReflect.Descriptor instead.
-func (*CreateOrderResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetOrdersResponse.ProtoReflect.Descriptor instead.
+func (*GetOrdersResponse) Descriptor() ([]byte, []int) {
 	return file_cmd_api_orders_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *CreateOrderResponse) GetOrderId() int64 {
+func (x *GetOrdersResponse) GetOrders() []*Order {
 	if x != nil {
-		return x.OrderId
+		return x.Orders
 	}
-	return 0
+	return nil
 }
 
-type UpdateOrdersRequest struct {
+// GetOrderRequest looks an order up for its recipient; orders of other recipients
+// are reported as not found.
+type GetOrderRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	OrderIds      []int64                `protobuf:"varint,1,rep,packed,name=order_ids,json=orderIds,proto3" json:"order_ids,omitempty"`
+	OrderId       int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
 	RecipientId   int64                  `protobuf:"varint,2,opt,name=recipient_id,json=recipientId,proto3" json:"recipient_id,omitempty"`
-	Action        string                 `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateOrdersRequest) Reset() {
-	*x = UpdateOrdersRequest{}
+func (x *GetOrderRequest) Reset() {
+	*x = GetOrderRequest{}
 	mi := &file_cmd_api_orders_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateOrdersRequest) String() string {
+func (x *GetOrderRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateOrdersRequest) ProtoMessage() {}
+func (*GetOrderRequest) ProtoMessage() {}
 
-func (x *UpdateOrdersRequest) ProtoReflect() protoreflect.Message {
+func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_cmd_api_orders_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -482,52 +596,46 @@ func (x *UpdateOrdersRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateOrdersRequest.ProtoReflect.Descriptor instead.
-func (*UpdateOrdersRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetOrderRequest.ProtoReflect.Descriptor instead.
+func (*GetOrderRequest) Descriptor() ([]byte, []int) {
 	return file_cmd_api_orders_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *UpdateOrdersRequest) GetOrderIds() []int64 {
+func (x *GetOrderRequest) GetOrderId() int64 {
 	if x != nil {
-		return x.OrderIds
+		return x.OrderId
 	}
-	return nil
+	return 0
 }
 
-func (x *UpdateOrdersRequest) GetRecipientId() int64 {
+func (x *GetOrderRequest) GetRecipientId() int64 {
 	if x != nil {
 		return x.RecipientId
 	}
 	return 0
 }
 
-func (x *UpdateOrdersRequest) GetAction() string {
-	if x != nil {
-		return x.Action
-	}
-	return ""
-}
-
-type UpdateOrdersResponse struct {
+type GetOrderResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateOrdersResponse) Reset() {
-	*x = UpdateOrdersResponse{}
+func (x *GetOrderResponse) Reset() {
+	*x = GetOrderResponse{}
 	mi := &file_cmd_api_orders_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateOrdersResponse) String() string {
+func (x *GetOrderResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateOrdersResponse) ProtoMessage() {}
+func (*GetOrderResponse) ProtoMessage() {}
 
-func (x *UpdateOrdersResponse) ProtoReflect() protoreflect.Message {
+func (x *GetOrderResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_cmd_api_orders_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -539,32 +647,40 @@ func (x *UpdateOrdersResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateOrdersResponse.ProtoReflect.Descriptor instead.
-func (*UpdateOrdersResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetOrderResponse.ProtoReflect.Descriptor instead.
+func (*GetOrderResponse) Descriptor() ([]byte, []int) {
 	return file_cmd_api_orders_proto_rawDescGZIP(), []int{7}
 }
 
-type DeleteOrderRequest struct {
+func (x *GetOrderResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+type ListRefundsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	OrderId       int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Limit         int64                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int64                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteOrderRequest) Reset() {
-	*x = DeleteOrderRequest{}
+func (x *ListRefundsRequest) Reset() {
+	*x = ListRefundsRequest{}
 	mi := &file_cmd_api_orders_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteOrderRequest) String() string {
+func (x *ListRefundsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteOrderRequest) ProtoMessage() {}
+func (*ListRefundsRequest) ProtoMessage() {}
 
-func (x *DeleteOrderRequest) ProtoReflect() protoreflect.Message {
+func (x *ListRefundsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_cmd_api_orders_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -576,39 +692,971 @@ func (x *DeleteOrderRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteOrderRequest.ProtoReflect.Descriptor instead.
-func (*DeleteOrderRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use ListRefundsRequest.ProtoReflect.Descriptor instead.
+func (*ListRefundsRequest) Descriptor() ([]byte, []int) {
 	return file_cmd_api_orders_proto_rawDescGZIP(), []int{8}
 }
 
+func (x *ListRefundsRequest) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListRefundsRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type ListRefundsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRefundsResponse) Reset() {
+	*x = ListRefundsResponse{}
+	mi := &file_cmd_api_orders_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRefundsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRefundsResponse) ProtoMessage() {}
+
+func (x *ListRefundsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_api_orders_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRefundsResponse.ProtoReflect.Descriptor instead.
+func (*ListRefundsResponse) Descriptor() ([]byte, []int) {
+	return file_cmd_api_orders_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListRefundsResponse) GetOrders() []*Order {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+// GetHistoryRequest lists orders by their latest change, most recent first.
+type GetHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int64                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int64                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHistoryRequest) Reset() {
+	*x = GetHistoryRequest{}
+	mi := &file_cmd_api_orders_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHistoryRequest) ProtoMessage() {}
+
+func (x *GetHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_api_orders_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_cmd_api_orders_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetHistoryRequest) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetHistoryRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type GetHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHistoryResponse) Reset() {
+	*x = GetHistoryResponse{}
+	mi := &file_cmd_api_orders_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHistoryResponse) ProtoMessage() {}
+
+func (x *GetHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_api_orders_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_cmd_api_orders_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetHistoryResponse) GetOrders() []*Order {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+type CreateOrderRequest struct {
+	state            protoimpl.MessageState          `protogen:"open.v1"`
+	Order            *CreateOrderRequest_OrderParams `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	PackagingType    string                          `protobuf:"bytes,2,opt,name=packaging_type,json=packagingType,proto3" json:"packaging_type,omitempty"`
+	MembranaIncluded bool                            `protobuf:"varint,3,opt,name=membrana_included,json=membranaIncluded,proto3" json:"membrana_included,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *CreateOrderRequest) Reset() {
+	*x = CreateOrderRequest{}
+	mi := &file_cmd_api_orders_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrderRequest) ProtoMessage() {}
+
+func (x *CreateOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_api_orders_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrderRequest.ProtoReflect.Descriptor instead.
+func (*CreateOrderRequest) Descriptor() ([]byte, []int) {
+	return file_cmd_api_orders_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *CreateOrderRequest) GetOrder() *CreateOrderRequest_OrderParams {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *CreateOrderRequest) GetPackagingType() string {
+	if x != nil {
+		return x.PackagingType
+	}
+	return ""
+}
+
+func (x *CreateOrderRequest) GetMembranaIncluded() bool {
+	if x != nil {
+		return x.MembranaIncluded
+	}
+	return false
+}
+
+type CreateOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	PickupCode    string                 `protobuf:"bytes,2,opt,name=pickup_code,json=pickupCode,proto3" json:"pickup_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateOrderResponse) Reset() {
+	*x = CreateOrderResponse{}
+	mi := &file_cmd_api_orders_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrderResponse) ProtoMessage() {}
+
+func (x *CreateOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_api_orders_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrderResponse.ProtoReflect.Descriptor instead.
+func (*CreateOrderResponse) Descriptor() ([]byte, []int) {
+	return file_cmd_api_orders_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CreateOrderResponse) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *CreateOrderResponse) GetPickupCode() string {
+	if x != nil {
+		return x.PickupCode
+	}
+	return ""
+}
+
+// CreateOrdersRequest carries a whole courier delivery, accepted in one transaction.
+type CreateOrdersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Orders        []*CreateOrderRequest  `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateOrdersRequest) Reset() {
+	*x = CreateOrdersRequest{}
+	mi := &file_cmd_api_orders_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrdersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrdersRequest) ProtoMessage() {}
+
+func (x *CreateOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_api_orders_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrdersRequest.ProtoReflect.Descriptor instead.
+func (*CreateOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_cmd_api_orders_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CreateOrdersRequest) GetOrders() []*CreateOrderRequest {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+type CreateOrdersResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// results follow the order of the request orders.
+	Results       []*CreateOrdersResponse_Result `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateOrdersResponse) Reset() {
+	*x = CreateOrdersResponse{}
+	mi := &file_cmd_api_orders_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrdersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrdersResponse) ProtoMessage() {}
+
+func (x *CreateOrdersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_api_orders_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrdersResponse.ProtoReflect.Descriptor instead.
+func (*CreateOrdersResponse) Descriptor() ([]byte, []int) {
+	return file_cmd_api_orders_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CreateOrdersResponse) GetResults() []*CreateOrdersResponse_Result {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type UpdateOrdersRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	OrderIds    []int64                `protobuf:"varint,1,rep,packed,name=order_ids,json=orderIds,proto3" json:"order_ids,omitempty"`
+	RecipientId int64                  `protobuf:"varint,2,opt,name=recipient_id,json=recipientId,proto3" json:"recipient_id,omitempty"`
+	Action      string                 `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`
+	PickupCodes map[int64]string       `protobuf:"bytes,4,rep,name=pickup_codes,json=pickupCodes,proto3" json:"pickup_codes,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Limits the action to some items of an order; orders without an entry are served whole.
+	ItemIds       map[int64]*ItemIDs `protobuf:"bytes,5,rep,name=item_ids,json=itemIds,proto3" json:"item_ids,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateOrdersRequest) Reset() {
+	*x = UpdateOrdersRequest{}
+	mi := &file_cmd_api_orders_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateOrdersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateOrdersRequest) ProtoMessage() {}
+
+func (x *UpdateOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_api_orders_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateOrdersRequest.ProtoReflect.Descriptor instead.
+func (*UpdateOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_cmd_api_orders_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *UpdateOrdersRequest) GetOrderIds() []int64 {
+	if x != nil {
+		return x.OrderIds
+	}
+	return nil
+}
+
+func (x *UpdateOrdersRequest) GetRecipientId() int64 {
+	if x != nil {
+		return x.RecipientId
+	}
+	return 0
+}
+
+func (x *UpdateOrdersRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *UpdateOrdersRequest) GetPickupCodes() map[int64]string {
+	if x != nil {
+		return x.PickupCodes
+	}
+	return nil
+}
+
+func (x *UpdateOrdersRequest) GetItemIds() map[int64]*ItemIDs {
+	if x != nil {
+		return x.ItemIds
+	}
+	return nil
+}
+
+type ItemIDs struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ids           []int64                `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ItemIDs) Reset() {
+	*x = ItemIDs{}
+	mi := &file_cmd_api_orders_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ItemIDs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ItemIDs) ProtoMessage() {}
+
+func (x *ItemIDs) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_api_orders_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ItemIDs.ProtoReflect.Descriptor instead.
+func (*ItemIDs) Descriptor() ([]byte, []int) {
+	return file_cmd_api_orders_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ItemIDs) GetIds() []int64 {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type UpdateOrdersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateOrdersResponse) Reset() {
+	*x = UpdateOrdersResponse{}
+	mi := &file_cmd_api_orders_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateOrdersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateOrdersResponse) ProtoMessage() {}
+
+func (x *UpdateOrdersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_api_orders_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateOrdersResponse.ProtoReflect.Descriptor instead.
+func (*UpdateOrdersResponse) Descriptor() ([]byte, []int) {
+	return file_cmd_api_orders_proto_rawDescGZIP(), []int{18}
+}
+
+type DeleteOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteOrderRequest) Reset() {
+	*x = DeleteOrderRequest{}
+	mi := &file_cmd_api_orders_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteOrderRequest) ProtoMessage() {}
+
+func (x *DeleteOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_api_orders_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteOrderRequest.ProtoReflect.Descriptor instead.
+func (*DeleteOrderRequest) Descriptor() ([]byte, []int) {
+	return file_cmd_api_orders_proto_rawDescGZIP(), []int{19}
+}
+
 func (x *DeleteOrderRequest) GetOrderId() int64 {
 	if x != nil {
-		return x.OrderId
+		return x.OrderId
+	}
+	return 0
+}
+
+type DeleteOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteOrderResponse) Reset() {
+	*x = DeleteOrderResponse{}
+	mi := &file_cmd_api_orders_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteOrderResponse) ProtoMessage() {}
+
+func (x *DeleteOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_api_orders_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteOrderResponse.ProtoReflect.Descriptor instead.
+func (*DeleteOrderResponse) Descriptor() ([]byte, []int) {
+	return file_cmd_api_orders_proto_rawDescGZIP(), []int{20}
+}
+
+type TransferOrderRequest struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	OrderId             int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	TargetPickupPointId int64                  `protobuf:"varint,2,opt,name=target_pickup_point_id,json=targetPickupPointId,proto3" json:"target_pickup_point_id,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *TransferOrderRequest) Reset() {
+	*x = TransferOrderRequest{}
+	mi := &file_cmd_api_orders_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferOrderRequest) ProtoMessage() {}
+
+func (x *TransferOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_api_orders_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferOrderRequest.ProtoReflect.Descriptor instead.
+func (*TransferOrderRequest) Descriptor() ([]byte, []int) {
+	return file_cmd_api_orders_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *TransferOrderRequest) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *TransferOrderRequest) GetTargetPickupPointId() int64 {
+	if x != nil {
+		return x.TargetPickupPointId
+	}
+	return 0
+}
+
+type TransferOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransferOrderResponse) Reset() {
+	*x = TransferOrderResponse{}
+	mi := &file_cmd_api_orders_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferOrderResponse) ProtoMessage() {}
+
+func (x *TransferOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_api_orders_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferOrderResponse.ProtoReflect.Descriptor instead.
+func (*TransferOrderResponse) Descriptor() ([]byte, []int) {
+	return file_cmd_api_orders_proto_rawDescGZIP(), []int{22}
+}
+
+type AcceptTransferRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AcceptTransferRequest) Reset() {
+	*x = AcceptTransferRequest{}
+	mi := &file_cmd_api_orders_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcceptTransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcceptTransferRequest) ProtoMessage() {}
+
+func (x *AcceptTransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_api_orders_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcceptTransferRequest.ProtoReflect.Descriptor instead.
+func (*AcceptTransferRequest) Descriptor() ([]byte, []int) {
+	return file_cmd_api_orders_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *AcceptTransferRequest) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+type AcceptTransferResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AcceptTransferResponse) Reset() {
+	*x = AcceptTransferResponse{}
+	mi := &file_cmd_api_orders_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcceptTransferResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcceptTransferResponse) ProtoMessage() {}
+
+func (x *AcceptTransferResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_api_orders_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcceptTransferResponse.ProtoReflect.Descriptor instead.
+func (*AcceptTransferResponse) Descriptor() ([]byte, []int) {
+	return file_cmd_api_orders_proto_rawDescGZIP(), []int{24}
+}
+
+type RegeneratePickupCodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	RecipientId   int64                  `protobuf:"varint,2,opt,name=recipient_id,json=recipientId,proto3" json:"recipient_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegeneratePickupCodeRequest) Reset() {
+	*x = RegeneratePickupCodeRequest{}
+	mi := &file_cmd_api_orders_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegeneratePickupCodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegeneratePickupCodeRequest) ProtoMessage() {}
+
+func (x *RegeneratePickupCodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_api_orders_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegeneratePickupCodeRequest.ProtoReflect.Descriptor instead.
+func (*RegeneratePickupCodeRequest) Descriptor() ([]byte, []int) {
+	return file_cmd_api_orders_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *RegeneratePickupCodeRequest) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *RegeneratePickupCodeRequest) GetRecipientId() int64 {
+	if x != nil {
+		return x.RecipientId
+	}
+	return 0
+}
+
+type RegeneratePickupCodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PickupCode    string                 `protobuf:"bytes,1,opt,name=pickup_code,json=pickupCode,proto3" json:"pickup_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RegeneratePickupCodeResponse) Reset() {
+	*x = RegeneratePickupCodeResponse{}
+	mi := &file_cmd_api_orders_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RegeneratePickupCodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RegeneratePickupCodeResponse) ProtoMessage() {}
+
+func (x *RegeneratePickupCodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_api_orders_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RegeneratePickupCodeResponse.ProtoReflect.Descriptor instead.
+func (*RegeneratePickupCodeResponse) Descriptor() ([]byte, []int) {
+	return file_cmd_api_orders_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *RegeneratePickupCodeResponse) GetPickupCode() string {
+	if x != nil {
+		return x.PickupCode
+	}
+	return ""
+}
+
+// ExportOrdersRequest streams orders of the pickup point in id order. Unset filters
+// match every order.
+type ExportOrdersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Statuses      []OrderStatus          `protobuf:"varint,1,rep,packed,name=statuses,proto3,enum=orders.proto.OrderStatus" json:"statuses,omitempty"`
+	RecipientId   int64                  `protobuf:"varint,2,opt,name=recipient_id,json=recipientId,proto3" json:"recipient_id,omitempty"`
+	ExpiresAfter  *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expires_after,json=expiresAfter,proto3" json:"expires_after,omitempty"`
+	ExpiresBefore *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=expires_before,json=expiresBefore,proto3" json:"expires_before,omitempty"`
+	// resume_token of the last received order continues an interrupted export.
+	ResumeToken   string `protobuf:"bytes,5,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportOrdersRequest) Reset() {
+	*x = ExportOrdersRequest{}
+	mi := &file_cmd_api_orders_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportOrdersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportOrdersRequest) ProtoMessage() {}
+
+func (x *ExportOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_api_orders_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportOrdersRequest.ProtoReflect.Descriptor instead.
+func (*ExportOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_cmd_api_orders_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ExportOrdersRequest) GetStatuses() []OrderStatus {
+	if x != nil {
+		return x.Statuses
+	}
+	return nil
+}
+
+func (x *ExportOrdersRequest) GetRecipientId() int64 {
+	if x != nil {
+		return x.RecipientId
 	}
 	return 0
 }
 
-type DeleteOrderResponse struct {
+func (x *ExportOrdersRequest) GetExpiresAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAfter
+	}
+	return nil
+}
+
+func (x *ExportOrdersRequest) GetExpiresBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresBefore
+	}
+	return nil
+}
+
+func (x *ExportOrdersRequest) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+type ExportOrdersResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	ResumeToken   string                 `protobuf:"bytes,2,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteOrderResponse) Reset() {
-	*x = DeleteOrderResponse{}
-	mi := &file_cmd_api_orders_proto_msgTypes[9]
+func (x *ExportOrdersResponse) Reset() {
+	*x = ExportOrdersResponse{}
+	mi := &file_cmd_api_orders_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteOrderResponse) String() string {
+func (x *ExportOrdersResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteOrderResponse) ProtoMessage() {}
+func (*ExportOrdersResponse) ProtoMessage() {}
 
-func (x *DeleteOrderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_cmd_api_orders_proto_msgTypes[9]
+func (x *ExportOrdersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_api_orders_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -619,9 +1667,162 @@ func (x *DeleteOrderResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteOrderResponse.ProtoReflect.Descriptor instead.
-func (*DeleteOrderResponse) Descriptor() ([]byte, []int) {
-	return file_cmd_api_orders_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use ExportOrdersResponse.ProtoReflect.Descriptor instead.
+func (*ExportOrdersResponse) Descriptor() ([]byte, []int) {
+	return file_cmd_api_orders_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ExportOrdersResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *ExportOrdersResponse) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+// WatchOrdersRequest streams status changes of the pickup point as they are
+// committed. Without resume_token only changes after the call are sent.
+type WatchOrdersRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// resume_token of the last received event replays the changes missed since.
+	ResumeToken   string `protobuf:"bytes,1,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchOrdersRequest) Reset() {
+	*x = WatchOrdersRequest{}
+	mi := &file_cmd_api_orders_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchOrdersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchOrdersRequest) ProtoMessage() {}
+
+func (x *WatchOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_api_orders_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchOrdersRequest.ProtoReflect.Descriptor instead.
+func (*WatchOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_cmd_api_orders_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *WatchOrdersRequest) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
+}
+
+type OrderStatusEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	PickupPointId int64                  `protobuf:"varint,2,opt,name=pickup_point_id,json=pickupPointId,proto3" json:"pickup_point_id,omitempty"`
+	ItemId        *int64                 `protobuf:"varint,3,opt,name=item_id,json=itemId,proto3,oneof" json:"item_id,omitempty"`
+	Status        OrderStatus            `protobuf:"varint,4,opt,name=status,proto3,enum=orders.proto.OrderStatus" json:"status,omitempty"`
+	Description   string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	ResumeToken   string                 `protobuf:"bytes,7,opt,name=resume_token,json=resumeToken,proto3" json:"resume_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderStatusEvent) Reset() {
+	*x = OrderStatusEvent{}
+	mi := &file_cmd_api_orders_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderStatusEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderStatusEvent) ProtoMessage() {}
+
+func (x *OrderStatusEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_api_orders_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderStatusEvent.ProtoReflect.Descriptor instead.
+func (*OrderStatusEvent) Descriptor() ([]byte, []int) {
+	return file_cmd_api_orders_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *OrderStatusEvent) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *OrderStatusEvent) GetPickupPointId() int64 {
+	if x != nil {
+		return x.PickupPointId
+	}
+	return 0
+}
+
+func (x *OrderStatusEvent) GetItemId() int64 {
+	if x != nil && x.ItemId != nil {
+		return *x.ItemId
+	}
+	return 0
+}
+
+func (x *OrderStatusEvent) GetStatus() OrderStatus {
+	if x != nil {
+		return x.Status
+	}
+	return OrderStatus_RECEVIED
+}
+
+func (x *OrderStatusEvent) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *OrderStatusEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *OrderStatusEvent) GetResumeToken() string {
+	if x != nil {
+		return x.ResumeToken
+	}
+	return ""
 }
 
 type CreateOrderRequest_OrderParams struct {
@@ -629,14 +1830,17 @@ type CreateOrderRequest_OrderParams struct {
 	RecipientId    int64                  `protobuf:"varint,1,opt,name=recipient_id,json=recipientId,proto3" json:"recipient_id,omitempty"`
 	ExpirationDate *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=expiration_date,json=expirationDate,proto3" json:"expiration_date,omitempty"`
 	Weight         float64                `protobuf:"fixed64,3,opt,name=weight,proto3" json:"weight,omitempty"`
-	Worth          float64                `protobuf:"fixed64,4,opt,name=worth,proto3" json:"worth,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// Deprecated: Marked as deprecated in cmd/api/orders.proto.
+	Worth         float64                          `protobuf:"fixed64,4,opt,name=worth,proto3" json:"worth,omitempty"`
+	WorthMoney    *Money                           `protobuf:"bytes,5,opt,name=worth_money,json=worthMoney,proto3" json:"worth_money,omitempty"`
+	Items         []*CreateOrderRequest_ItemParams `protobuf:"bytes,6,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateOrderRequest_OrderParams) Reset() {
 	*x = CreateOrderRequest_OrderParams{}
-	mi := &file_cmd_api_orders_proto_msgTypes[10]
+	mi := &file_cmd_api_orders_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -648,7 +1852,7 @@ func (x *CreateOrderRequest_OrderParams) String() string {
 func (*CreateOrderRequest_OrderParams) ProtoMessage() {}
 
 func (x *CreateOrderRequest_OrderParams) ProtoReflect() protoreflect.Message {
-	mi := &file_cmd_api_orders_proto_msgTypes[10]
+	mi := &file_cmd_api_orders_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -661,7 +1865,7 @@ func (x *CreateOrderRequest_OrderParams) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateOrderRequest_OrderParams.ProtoReflect.Descriptor instead.
 func (*CreateOrderRequest_OrderParams) Descriptor() ([]byte, []int) {
-	return file_cmd_api_orders_proto_rawDescGZIP(), []int{4, 0}
+	return file_cmd_api_orders_proto_rawDescGZIP(), []int{12, 0}
 }
 
 func (x *CreateOrderRequest_OrderParams) GetRecipientId() int64 {
@@ -685,6 +1889,7 @@ func (x *CreateOrderRequest_OrderParams) GetWeight() float64 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in cmd/api/orders.proto.
 func (x *CreateOrderRequest_OrderParams) GetWorth() float64 {
 	if x != nil {
 		return x.Worth
@@ -692,15 +1897,166 @@ func (x *CreateOrderRequest_OrderParams) GetWorth() float64 {
 	return 0
 }
 
+func (x *CreateOrderRequest_OrderParams) GetWorthMoney() *Money {
+	if x != nil {
+		return x.WorthMoney
+	}
+	return nil
+}
+
+func (x *CreateOrderRequest_OrderParams) GetItems() []*CreateOrderRequest_ItemParams {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type CreateOrderRequest_ItemParams struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Sku           string                 `protobuf:"bytes,1,opt,name=sku,proto3" json:"sku,omitempty"`
+	Quantity      int64                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Price         *Money                 `protobuf:"bytes,3,opt,name=price,proto3" json:"price,omitempty"`
+	Weight        float64                `protobuf:"fixed64,4,opt,name=weight,proto3" json:"weight,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateOrderRequest_ItemParams) Reset() {
+	*x = CreateOrderRequest_ItemParams{}
+	mi := &file_cmd_api_orders_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrderRequest_ItemParams) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrderRequest_ItemParams) ProtoMessage() {}
+
+func (x *CreateOrderRequest_ItemParams) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_api_orders_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrderRequest_ItemParams.ProtoReflect.Descriptor instead.
+func (*CreateOrderRequest_ItemParams) Descriptor() ([]byte, []int) {
+	return file_cmd_api_orders_proto_rawDescGZIP(), []int{12, 1}
+}
+
+func (x *CreateOrderRequest_ItemParams) GetSku() string {
+	if x != nil {
+		return x.Sku
+	}
+	return ""
+}
+
+func (x *CreateOrderRequest_ItemParams) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *CreateOrderRequest_ItemParams) GetPrice() *Money {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *CreateOrderRequest_ItemParams) GetWeight() float64 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+// Result holds either the accepted order or the reason its parcel was rejected.
+type CreateOrdersResponse_Result struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       int64                  `protobuf:"varint,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	PickupCode    string                 `protobuf:"bytes,2,opt,name=pickup_code,json=pickupCode,proto3" json:"pickup_code,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateOrdersResponse_Result) Reset() {
+	*x = CreateOrdersResponse_Result{}
+	mi := &file_cmd_api_orders_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrdersResponse_Result) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrdersResponse_Result) ProtoMessage() {}
+
+func (x *CreateOrdersResponse_Result) ProtoReflect() protoreflect.Message {
+	mi := &file_cmd_api_orders_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrdersResponse_Result.ProtoReflect.Descriptor instead.
+func (*CreateOrdersResponse_Result) Descriptor() ([]byte, []int) {
+	return file_cmd_api_orders_proto_rawDescGZIP(), []int{15, 0}
+}
+
+func (x *CreateOrdersResponse_Result) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *CreateOrdersResponse_Result) GetPickupCode() string {
+	if x != nil {
+		return x.PickupCode
+	}
+	return ""
+}
+
+func (x *CreateOrdersResponse_Result) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
 var File_cmd_api_orders_proto protoreflect.FileDescriptor
 
 const file_cmd_api_orders_proto_rawDesc = "" +
 	"\n" +
-	"\x14cmd/api/orders.proto\x12\forders.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x9c\x01\n" +
+	"\x14cmd/api/orders.proto\x12\forders.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xee\x01\n" +
 	"\vOrderRecord\x128\n" +
 	"\ttimestamp\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x121\n" +
 	"\x06status\x18\x02 \x01(\x0e2\x19.orders.proto.OrderStatusR\x06status\x12 \n" +
-	"\vdescription\x18\x03 \x01(\tR\vdescription\"\x99\x03\n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1c\n" +
+	"\aitem_id\x18\x04 \x01(\x03H\x00R\x06itemId\x88\x01\x01\x12&\n" +
+	"\x0fpickup_point_id\x18\x05 \x01(\x03R\rpickupPointIdB\n" +
+	"\n" +
+	"\b_item_id\"M\n" +
+	"\x05Money\x12\x1f\n" +
+	"\vminor_units\x18\x01 \x01(\x03R\n" +
+	"minorUnits\x12#\n" +
+	"\rcurrency_code\x18\x02 \x01(\tR\fcurrencyCode\"\xaf\x05\n" +
 	"\x05Order\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12!\n" +
 	"\frecipient_id\x18\x02 \x01(\x03R\vrecipientId\x12C\n" +
@@ -709,44 +2065,154 @@ const file_cmd_api_orders_proto_rawDesc = "" +
 	"\rrefunded_date\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\frefundedDate\x121\n" +
 	"\x06status\x18\x06 \x01(\x0e2\x19.orders.proto.OrderStatusR\x06status\x123\n" +
 	"\ahistory\x18\a \x03(\v2\x19.orders.proto.OrderRecordR\ahistory\x12\x16\n" +
-	"\x06Weight\x18\b \x01(\x01R\x06Weight\x12\x14\n" +
-	"\x05Worth\x18\t \x01(\x01R\x05Worth\"@\n" +
+	"\x06Weight\x18\b \x01(\x01R\x06Weight\x12\x18\n" +
+	"\x05Worth\x18\t \x01(\x01B\x02\x18\x01R\x05Worth\x124\n" +
+	"\vworth_money\x18\n" +
+	" \x01(\v2\x13.orders.proto.MoneyR\n" +
+	"worthMoney\x12-\n" +
+	"\x05items\x18\v \x03(\v2\x17.orders.proto.OrderItemR\x05items\x12\x18\n" +
+	"\aversion\x18\f \x01(\x03R\aversion\x12&\n" +
+	"\x0fpickup_point_id\x18\r \x01(\x03R\rpickupPointId\x12?\n" +
+	"\rreturned_date\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\freturnedDate\x12\x1c\n" +
+	"\acell_id\x18\x0f \x01(\x03H\x00R\x06cellId\x88\x01\x01B\n" +
+	"\n" +
+	"\b_cell_id\"\xbf\x01\n" +
+	"\tOrderItem\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x10\n" +
+	"\x03sku\x18\x02 \x01(\tR\x03sku\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x03R\bquantity\x12)\n" +
+	"\x05price\x18\x04 \x01(\v2\x13.orders.proto.MoneyR\x05price\x12\x16\n" +
+	"\x06weight\x18\x05 \x01(\x01R\x06weight\x121\n" +
+	"\x06status\x18\x06 \x01(\x0e2\x19.orders.proto.OrderStatusR\x06status\"@\n" +
 	"\x10GetOrdersRequest\x12\x14\n" +
 	"\x05limit\x18\x01 \x01(\x03R\x05limit\x12\x16\n" +
 	"\x06offset\x18\x02 \x01(\x03R\x06offset\"@\n" +
 	"\x11GetOrdersResponse\x12+\n" +
-	"\x06orders\x18\x01 \x03(\v2\x13.orders.proto.OrderR\x06orders\"\xd2\x02\n" +
+	"\x06orders\x18\x01 \x03(\v2\x13.orders.proto.OrderR\x06orders\"O\n" +
+	"\x0fGetOrderRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x03R\aorderId\x12!\n" +
+	"\frecipient_id\x18\x02 \x01(\x03R\vrecipientId\"=\n" +
+	"\x10GetOrderResponse\x12)\n" +
+	"\x05order\x18\x01 \x01(\v2\x13.orders.proto.OrderR\x05order\"B\n" +
+	"\x12ListRefundsRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x03R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x03R\x06offset\"B\n" +
+	"\x13ListRefundsResponse\x12+\n" +
+	"\x06orders\x18\x01 \x03(\v2\x13.orders.proto.OrderR\x06orders\"A\n" +
+	"\x11GetHistoryRequest\x12\x14\n" +
+	"\x05limit\x18\x01 \x01(\x03R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x03R\x06offset\"A\n" +
+	"\x12GetHistoryResponse\x12+\n" +
+	"\x06orders\x18\x01 \x03(\v2\x13.orders.proto.OrderR\x06orders\"\xce\x04\n" +
 	"\x12CreateOrderRequest\x12B\n" +
 	"\x05order\x18\x01 \x01(\v2,.orders.proto.CreateOrderRequest.OrderParamsR\x05order\x12%\n" +
 	"\x0epackaging_type\x18\x02 \x01(\tR\rpackagingType\x12+\n" +
-	"\x11membrana_included\x18\x03 \x01(\bR\x10membranaIncluded\x1a\xa3\x01\n" +
+	"\x11membrana_included\x18\x03 \x01(\bR\x10membranaIncluded\x1a\xa0\x02\n" +
 	"\vOrderParams\x12!\n" +
 	"\frecipient_id\x18\x01 \x01(\x03R\vrecipientId\x12C\n" +
 	"\x0fexpiration_date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x0eexpirationDate\x12\x16\n" +
-	"\x06weight\x18\x03 \x01(\x01R\x06weight\x12\x14\n" +
-	"\x05worth\x18\x04 \x01(\x01R\x05worth\"0\n" +
+	"\x06weight\x18\x03 \x01(\x01R\x06weight\x12\x18\n" +
+	"\x05worth\x18\x04 \x01(\x01B\x02\x18\x01R\x05worth\x124\n" +
+	"\vworth_money\x18\x05 \x01(\v2\x13.orders.proto.MoneyR\n" +
+	"worthMoney\x12A\n" +
+	"\x05items\x18\x06 \x03(\v2+.orders.proto.CreateOrderRequest.ItemParamsR\x05items\x1a}\n" +
+	"\n" +
+	"ItemParams\x12\x10\n" +
+	"\x03sku\x18\x01 \x01(\tR\x03sku\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x03R\bquantity\x12)\n" +
+	"\x05price\x18\x03 \x01(\v2\x13.orders.proto.MoneyR\x05price\x12\x16\n" +
+	"\x06weight\x18\x04 \x01(\x01R\x06weight\"Q\n" +
 	"\x13CreateOrderResponse\x12\x19\n" +
-	"\border_id\x18\x01 \x01(\x03R\aorderId\"m\n" +
+	"\border_id\x18\x01 \x01(\x03R\aorderId\x12\x1f\n" +
+	"\vpickup_code\x18\x02 \x01(\tR\n" +
+	"pickupCode\"O\n" +
+	"\x13CreateOrdersRequest\x128\n" +
+	"\x06orders\x18\x01 \x03(\v2 .orders.proto.CreateOrderRequestR\x06orders\"\xb7\x01\n" +
+	"\x14CreateOrdersResponse\x12C\n" +
+	"\aresults\x18\x01 \x03(\v2).orders.proto.CreateOrdersResponse.ResultR\aresults\x1aZ\n" +
+	"\x06Result\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x03R\aorderId\x12\x1f\n" +
+	"\vpickup_code\x18\x02 \x01(\tR\n" +
+	"pickupCode\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"\xa2\x03\n" +
 	"\x13UpdateOrdersRequest\x12\x1b\n" +
 	"\torder_ids\x18\x01 \x03(\x03R\borderIds\x12!\n" +
 	"\frecipient_id\x18\x02 \x01(\x03R\vrecipientId\x12\x16\n" +
-	"\x06action\x18\x03 \x01(\tR\x06action\"\x16\n" +
+	"\x06action\x18\x03 \x01(\tR\x06action\x12U\n" +
+	"\fpickup_codes\x18\x04 \x03(\v22.orders.proto.UpdateOrdersRequest.PickupCodesEntryR\vpickupCodes\x12I\n" +
+	"\bitem_ids\x18\x05 \x03(\v2..orders.proto.UpdateOrdersRequest.ItemIdsEntryR\aitemIds\x1a>\n" +
+	"\x10PickupCodesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\x03R\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1aQ\n" +
+	"\fItemIdsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\x03R\x03key\x12+\n" +
+	"\x05value\x18\x02 \x01(\v2\x15.orders.proto.ItemIDsR\x05value:\x028\x01\"\x1b\n" +
+	"\aItemIDs\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\x03R\x03ids\"\x16\n" +
 	"\x14UpdateOrdersResponse\"/\n" +
 	"\x12DeleteOrderRequest\x12\x19\n" +
 	"\border_id\x18\x01 \x01(\x03R\aorderId\"\x15\n" +
-	"\x13DeleteOrderResponse*b\n" +
+	"\x13DeleteOrderResponse\"f\n" +
+	"\x14TransferOrderRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x03R\aorderId\x123\n" +
+	"\x16target_pickup_point_id\x18\x02 \x01(\x03R\x13targetPickupPointId\"\x17\n" +
+	"\x15TransferOrderResponse\"2\n" +
+	"\x15AcceptTransferRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x03R\aorderId\"\x18\n" +
+	"\x16AcceptTransferResponse\"[\n" +
+	"\x1bRegeneratePickupCodeRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x03R\aorderId\x12!\n" +
+	"\frecipient_id\x18\x02 \x01(\x03R\vrecipientId\"?\n" +
+	"\x1cRegeneratePickupCodeResponse\x12\x1f\n" +
+	"\vpickup_code\x18\x01 \x01(\tR\n" +
+	"pickupCode\"\x96\x02\n" +
+	"\x13ExportOrdersRequest\x125\n" +
+	"\bstatuses\x18\x01 \x03(\x0e2\x19.orders.proto.OrderStatusR\bstatuses\x12!\n" +
+	"\frecipient_id\x18\x02 \x01(\x03R\vrecipientId\x12?\n" +
+	"\rexpires_after\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\fexpiresAfter\x12A\n" +
+	"\x0eexpires_before\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\rexpiresBefore\x12!\n" +
+	"\fresume_token\x18\x05 \x01(\tR\vresumeToken\"d\n" +
+	"\x14ExportOrdersResponse\x12)\n" +
+	"\x05order\x18\x01 \x01(\v2\x13.orders.proto.OrderR\x05order\x12!\n" +
+	"\fresume_token\x18\x02 \x01(\tR\vresumeToken\"7\n" +
+	"\x12WatchOrdersRequest\x12!\n" +
+	"\fresume_token\x18\x01 \x01(\tR\vresumeToken\"\xb1\x02\n" +
+	"\x10OrderStatusEvent\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\x03R\aorderId\x12&\n" +
+	"\x0fpickup_point_id\x18\x02 \x01(\x03R\rpickupPointId\x12\x1c\n" +
+	"\aitem_id\x18\x03 \x01(\x03H\x00R\x06itemId\x88\x01\x01\x121\n" +
+	"\x06status\x18\x04 \x01(\x0e2\x19.orders.proto.OrderStatusR\x06status\x12 \n" +
+	"\vdescription\x18\x05 \x01(\tR\vdescription\x128\n" +
+	"\ttimestamp\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12!\n" +
+	"\fresume_token\x18\a \x01(\tR\vresumeTokenB\n" +
+	"\n" +
+	"\b_item_id*\xb5\x01\n" +
 	"\vOrderStatus\x12\f\n" +
 	"\bRECEVIED\x10\x00\x12\f\n" +
 	"\bRETURNED\x10\x01\x12\r\n" +
 	"\tDELIVERED\x10\x02\x12\f\n" +
 	"\bREFUNDED\x10\x03\x12\x10\n" +
 	"\fSTRAGE_ENDED\x10\x04\x12\b\n" +
-	"\x04NONE\x10\x052\xdc\x02\n" +
+	"\x04NONE\x10\x05\x12\x0f\n" +
+	"\vIN_TRANSFER\x10\x06\x12\x0f\n" +
+	"\vTRANSFERRED\x10\a\x12\x17\n" +
+	"\x13PARTIALLY_DELIVERED\x10\b\x12\x16\n" +
+	"\x12PARTIALLY_REFUNDED\x10\t2\xf5\b\n" +
 	"\rOrdersService\x12L\n" +
-	"\tGetOrders\x12\x1e.orders.proto.GetOrdersRequest\x1a\x1f.orders.proto.GetOrdersResponse\x12U\n" +
+	"\tGetOrders\x12\x1e.orders.proto.GetOrdersRequest\x1a\x1f.orders.proto.GetOrdersResponse\x12I\n" +
+	"\bGetOrder\x12\x1d.orders.proto.GetOrderRequest\x1a\x1e.orders.proto.GetOrderResponse\x12R\n" +
+	"\vListRefunds\x12 .orders.proto.ListRefundsRequest\x1a!.orders.proto.ListRefundsResponse\x12O\n" +
+	"\n" +
+	"GetHistory\x12\x1f.orders.proto.GetHistoryRequest\x1a .orders.proto.GetHistoryResponse\x12U\n" +
 	"\fUpdateOrders\x12!.orders.proto.UpdateOrdersRequest\x1a\".orders.proto.UpdateOrdersResponse\x12R\n" +
-	"\vCreateOrder\x12 .orders.proto.CreateOrderRequest\x1a!.orders.proto.CreateOrderResponse\x12R\n" +
-	"\vDeleteOrder\x12 .orders.proto.DeleteOrderRequest\x1a!.orders.proto.DeleteOrderResponseB\x0eZ\forders.protob\x06proto3"
+	"\vCreateOrder\x12 .orders.proto.CreateOrderRequest\x1a!.orders.proto.CreateOrderResponse\x12U\n" +
+	"\fCreateOrders\x12!.orders.proto.CreateOrdersRequest\x1a\".orders.proto.CreateOrdersResponse\x12R\n" +
+	"\vDeleteOrder\x12 .orders.proto.DeleteOrderRequest\x1a!.orders.proto.DeleteOrderResponse\x12X\n" +
+	"\rTransferOrder\x12\".orders.proto.TransferOrderRequest\x1a#.orders.proto.TransferOrderResponse\x12[\n" +
+	"\x0eAcceptTransfer\x12#.orders.proto.AcceptTransferRequest\x1a$.orders.proto.AcceptTransferResponse\x12m\n" +
+	"\x14RegeneratePickupCode\x12).orders.proto.RegeneratePickupCodeRequest\x1a*.orders.proto.RegeneratePickupCodeResponse\x12W\n" +
+	"\fExportOrders\x12!.orders.proto.ExportOrdersRequest\x1a\".orders.proto.ExportOrdersResponse0\x01\x12Q\n" +
+	"\vWatchOrders\x12 .orders.proto.WatchOrdersRequest\x1a\x1e.orders.proto.OrderStatusEvent0\x01B\x0eZ\forders.protob\x06proto3"
 
 var (
 	file_cmd_api_orders_proto_rawDescOnce sync.Once
@@ -761,46 +2227,111 @@ func file_cmd_api_orders_proto_rawDescGZIP() []byte {
 }
 
 var file_cmd_api_orders_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_cmd_api_orders_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
+var file_cmd_api_orders_proto_msgTypes = make([]protoimpl.MessageInfo, 36)
 var file_cmd_api_orders_proto_goTypes = []any{
 	(OrderStatus)(0),                       // 0: orders.proto.OrderStatus
 	(*OrderRecord)(nil),                    // 1: orders.proto.OrderRecord
-	(*Order)(nil),                          // 2: orders.proto.Order
-	(*GetOrdersRequest)(nil),               // 3: orders.proto.GetOrdersRequest
-	(*GetOrdersResponse)(nil),              // 4: orders.proto.GetOrdersResponse
-	(*CreateOrderRequest)(nil),             // 5: orders.proto.CreateOrderRequest
-	(*CreateOrderResponse)(nil),            // 6: orders.proto.CreateOrderResponse
-	(*UpdateOrdersRequest)(nil),            // 7: orders.proto.UpdateOrdersRequest
-	(*UpdateOrdersResponse)(nil),           // 8: orders.proto.UpdateOrdersResponse
-	(*DeleteOrderRequest)(nil),             // 9: orders.proto.DeleteOrderRequest
-	(*DeleteOrderResponse)(nil),            // 10: orders.proto.DeleteOrderResponse
-	(*CreateOrderRequest_OrderParams)(nil), // 11: orders.proto.CreateOrderRequest.OrderParams
-	(*timestamppb.Timestamp)(nil),          // 12: google.protobuf.Timestamp
+	(*Money)(nil),                          // 2: orders.proto.Money
+	(*Order)(nil),                          // 3: orders.proto.Order
+	(*OrderItem)(nil),                      // 4: orders.proto.OrderItem
+	(*GetOrdersRequest)(nil),               // 5: orders.proto.GetOrdersRequest
+	(*GetOrdersResponse)(nil),              // 6: orders.proto.GetOrdersResponse
+	(*GetOrderRequest)(nil),                // 7: orders.proto.GetOrderRequest
+	(*GetOrderResponse)(nil),               // 8: orders.proto.GetOrderResponse
+	(*ListRefundsRequest)(nil),             // 9: orders.proto.ListRefundsRequest
+	(*ListRefundsResponse)(nil),            // 10: orders.proto.ListRefundsResponse
+	(*GetHistoryRequest)(nil),              // 11: orders.proto.GetHistoryRequest
+	(*GetHistoryResponse)(nil),             // 12: orders.proto.GetHistoryResponse
+	(*CreateOrderRequest)(nil),             // 13: orders.proto.CreateOrderRequest
+	(*CreateOrderResponse)(nil),            // 14: orders.proto.CreateOrderResponse
+	(*CreateOrdersRequest)(nil),            // 15: orders.proto.CreateOrdersRequest
+	(*CreateOrdersResponse)(nil),           // 16: orders.proto.CreateOrdersResponse
+	(*UpdateOrdersRequest)(nil),            // 17: orders.proto.UpdateOrdersRequest
+	(*ItemIDs)(nil),                        // 18: orders.proto.ItemIDs
+	(*UpdateOrdersResponse)(nil),           // 19: orders.proto.UpdateOrdersResponse
+	(*DeleteOrderRequest)(nil),             // 20: orders.proto.DeleteOrderRequest
+	(*DeleteOrderResponse)(nil),            // 21: orders.proto.DeleteOrderResponse
+	(*TransferOrderRequest)(nil),           // 22: orders.proto.TransferOrderRequest
+	(*TransferOrderResponse)(nil),          // 23: orders.proto.TransferOrderResponse
+	(*AcceptTransferRequest)(nil),          // 24: orders.proto.AcceptTransferRequest
+	(*AcceptTransferResponse)(nil),         // 25: orders.proto.AcceptTransferResponse
+	(*RegeneratePickupCodeRequest)(nil),    // 26: orders.proto.RegeneratePickupCodeRequest
+	(*RegeneratePickupCodeResponse)(nil),   // 27: orders.proto.RegeneratePickupCodeResponse
+	(*ExportOrdersRequest)(nil),            // 28: orders.proto.ExportOrdersRequest
+	(*ExportOrdersResponse)(nil),           // 29: orders.proto.ExportOrdersResponse
+	(*WatchOrdersRequest)(nil),             // 30: orders.proto.WatchOrdersRequest
+	(*OrderStatusEvent)(nil),               // 31: orders.proto.OrderStatusEvent
+	(*CreateOrderRequest_OrderParams)(nil), // 32: orders.proto.CreateOrderRequest.OrderParams
+	(*CreateOrderRequest_ItemParams)(nil),  // 33: orders.proto.CreateOrderRequest.ItemParams
+	(*CreateOrdersResponse_Result)(nil),    // 34: orders.proto.CreateOrdersResponse.Result
+	nil,                                    // 35: orders.proto.UpdateOrdersRequest.PickupCodesEntry
+	nil,                                    // 36: orders.proto.UpdateOrdersRequest.ItemIdsEntry
+	(*timestamppb.Timestamp)(nil),          // 37: google.protobuf.Timestamp
 }
 var file_cmd_api_orders_proto_depIdxs = []int32{
-	12, // 0: orders.proto.OrderRecord.timestamp:type_name -> google.protobuf.Timestamp
+	37, // 0: orders.proto.OrderRecord.timestamp:type_name -> google.protobuf.Timestamp
 	0,  // 1: orders.proto.OrderRecord.status:type_name -> orders.proto.OrderStatus
-	12, // 2: orders.proto.Order.expiration_date:type_name -> google.protobuf.Timestamp
-	12, // 3: orders.proto.Order.delivered_date:type_name -> google.protobuf.Timestamp
-	12, // 4: orders.proto.Order.refunded_date:type_name -> google.protobuf.Timestamp
+	37, // 2: orders.proto.Order.expiration_date:type_name -> google.protobuf.Timestamp
+	37, // 3: orders.proto.Order.delivered_date:type_name -> google.protobuf.Timestamp
+	37, // 4: orders.proto.Order.refunded_date:type_name -> google.protobuf.Timestamp
 	0,  // 5: orders.proto.Order.status:type_name -> orders.proto.OrderStatus
 	1,  // 6: orders.proto.Order.history:type_name -> orders.proto.OrderRecord
-	2,  // 7: orders.proto.GetOrdersResponse.orders:type_name -> orders.proto.Order
-	11, // 8: orders.proto.CreateOrderRequest.order:type_name -> orders.proto.CreateOrderRequest.OrderParams
-	12, // 9: orders.proto.CreateOrderRequest.OrderParams.expiration_date:type_name -> google.protobuf.Timestamp
-	3,  // 10: orders.proto.OrdersService.GetOrders:input_type -> orders.proto.GetOrdersRequest
-	7,  // 11: orders.proto.OrdersService.UpdateOrders:input_type -> orders.proto.UpdateOrdersRequest
-	5,  // 12: orders.proto.OrdersService.CreateOrder:input_type -> orders.proto.CreateOrderRequest
-	9,  // 13: orders.proto.OrdersService.DeleteOrder:input_type -> orders.proto.DeleteOrderRequest
-	4,  // 14: orders.proto.OrdersService.GetOrders:output_type -> orders.proto.GetOrdersResponse
-	8,  // 15: orders.proto.OrdersService.UpdateOrders:output_type -> orders.proto.UpdateOrdersResponse
-	6,  // 16: orders.proto.OrdersService.CreateOrder:output_type -> orders.proto.CreateOrderResponse
-	10, // 17: orders.proto.OrdersService.DeleteOrder:output_type -> orders.proto.DeleteOrderResponse
-	14, // [14:18] is the sub-list for method output_type
-	10, // [10:14] is the sub-list for method input_type
-	10, // [10:10] is the sub-list for extension type_name
-	10, // [10:10] is the sub-list for extension extendee
-	0,  // [0:10] is the sub-list for field type_name
+	2,  // 7: orders.proto.Order.worth_money:type_name -> orders.proto.Money
+	4,  // 8: orders.proto.Order.items:type_name -> orders.proto.OrderItem
+	37, // 9: orders.proto.Order.returned_date:type_name -> google.protobuf.Timestamp
+	2,  // 10: orders.proto.OrderItem.price:type_name -> orders.proto.Money
+	0,  // 11: orders.proto.OrderItem.status:type_name -> orders.proto.OrderStatus
+	3,  // 12: orders.proto.GetOrdersResponse.orders:type_name -> orders.proto.Order
+	3,  // 13: orders.proto.GetOrderResponse.order:type_name -> orders.proto.Order
+	3,  // 14: orders.proto.ListRefundsResponse.orders:type_name -> orders.proto.Order
+	3,  // 15: orders.proto.GetHistoryResponse.orders:type_name -> orders.proto.Order
+	32, // 16: orders.proto.CreateOrderRequest.order:type_name -> orders.proto.CreateOrderRequest.OrderParams
+	13, // 17: orders.proto.CreateOrdersRequest.orders:type_name -> orders.proto.CreateOrderRequest
+	34, // 18: orders.proto.CreateOrdersResponse.results:type_name -> orders.proto.CreateOrdersResponse.Result
+	35, // 19: orders.proto.UpdateOrdersRequest.pickup_codes:type_name -> orders.proto.UpdateOrdersRequest.PickupCodesEntry
+	36, // 20: orders.proto.UpdateOrdersRequest.item_ids:type_name -> orders.proto.UpdateOrdersRequest.ItemIdsEntry
+	0,  // 21: orders.proto.ExportOrdersRequest.statuses:type_name -> orders.proto.OrderStatus
+	37, // 22: orders.proto.ExportOrdersRequest.expires_after:type_name -> google.protobuf.Timestamp
+	37, // 23: orders.proto.ExportOrdersRequest.expires_before:type_name -> google.protobuf.Timestamp
+	3,  // 24: orders.proto.ExportOrdersResponse.order:type_name -> orders.proto.Order
+	0,  // 25: orders.proto.OrderStatusEvent.status:type_name -> orders.proto.OrderStatus
+	37, // 26: orders.proto.OrderStatusEvent.timestamp:type_name -> google.protobuf.Timestamp
+	37, // 27: orders.proto.CreateOrderRequest.OrderParams.expiration_date:type_name -> google.protobuf.Timestamp
+	2,  // 28: orders.proto.CreateOrderRequest.OrderParams.worth_money:type_name -> orders.proto.Money
+	33, // 29: orders.proto.CreateOrderRequest.OrderParams.items:type_name -> orders.proto.CreateOrderRequest.ItemParams
+	2,  // 30: orders.proto.CreateOrderRequest.ItemParams.price:type_name -> orders.proto.Money
+	18, // 31: orders.proto.UpdateOrdersRequest.ItemIdsEntry.value:type_name -> orders.proto.ItemIDs
+	5,  // 32: orders.proto.OrdersService.GetOrders:input_type -> orders.proto.GetOrdersRequest
+	7,  // 33: orders.proto.OrdersService.GetOrder:input_type -> orders.proto.GetOrderRequest
+	9,  // 34: orders.proto.OrdersService.ListRefunds:input_type -> orders.proto.ListRefundsRequest
+	11, // 35: orders.proto.OrdersService.GetHistory:input_type -> orders.proto.GetHistoryRequest
+	17, // 36: orders.proto.OrdersService.UpdateOrders:input_type -> orders.proto.UpdateOrdersRequest
+	13, // 37: orders.proto.OrdersService.CreateOrder:input_type -> orders.proto.CreateOrderRequest
+	15, // 38: orders.proto.OrdersService.CreateOrders:input_type -> orders.proto.CreateOrdersRequest
+	20, // 39: orders.proto.OrdersService.DeleteOrder:input_type -> orders.proto.DeleteOrderRequest
+	22, // 40: orders.proto.OrdersService.TransferOrder:input_type -> orders.proto.TransferOrderRequest
+	24, // 41: orders.proto.OrdersService.AcceptTransfer:input_type -> orders.proto.AcceptTransferRequest
+	26, // 42: orders.proto.OrdersService.RegeneratePickupCode:input_type -> orders.proto.RegeneratePickupCodeRequest
+	28, // 43: orders.proto.OrdersService.ExportOrders:input_type -> orders.proto.ExportOrdersRequest
+	30, // 44: orders.proto.OrdersService.WatchOrders:input_type -> orders.proto.WatchOrdersRequest
+	6,  // 45: orders.proto.OrdersService.GetOrders:output_type -> orders.proto.GetOrdersResponse
+	8,  // 46: orders.proto.OrdersService.GetOrder:output_type -> orders.proto.GetOrderResponse
+	10, // 47: orders.proto.OrdersService.ListRefunds:output_type -> orders.proto.ListRefundsResponse
+	12, // 48: orders.proto.OrdersService.GetHistory:output_type -> orders.proto.GetHistoryResponse
+	19, // 49: orders.proto.OrdersService.UpdateOrders:output_type -> orders.proto.UpdateOrdersResponse
+	14, // 50: orders.proto.OrdersService.CreateOrder:output_type -> orders.proto.CreateOrderResponse
+	16, // 51: orders.proto.OrdersService.CreateOrders:output_type -> orders.proto.CreateOrdersResponse
+	21, // 52: orders.proto.OrdersService.DeleteOrder:output_type -> orders.proto.DeleteOrderResponse
+	23, // 53: orders.proto.OrdersService.TransferOrder:output_type -> orders.proto.TransferOrderResponse
+	25, // 54: orders.proto.OrdersService.AcceptTransfer:output_type -> orders.proto.AcceptTransferResponse
+	27, // 55: orders.proto.OrdersService.RegeneratePickupCode:output_type -> orders.proto.RegeneratePickupCodeResponse
+	29, // 56: orders.proto.OrdersService.ExportOrders:output_type -> orders.proto.ExportOrdersResponse
+	31, // 57: orders.proto.OrdersService.WatchOrders:output_type -> orders.proto.OrderStatusEvent
+	45, // [45:58] is the sub-list for method output_type
+	32, // [32:45] is the sub-list for method input_type
+	32, // [32:32] is the sub-list for extension type_name
+	32, // [32:32] is the sub-list for extension extendee
+	0,  // [0:32] is the sub-list for field type_name
 }
 
 func init() { file_cmd_api_orders_proto_init() }
@@ -808,13 +2339,16 @@ func file_cmd_api_orders_proto_init() {
 	if File_cmd_api_orders_proto != nil {
 		return
 	}
+	file_cmd_api_orders_proto_msgTypes[0].OneofWrappers = []any{}
+	file_cmd_api_orders_proto_msgTypes[2].OneofWrappers = []any{}
+	file_cmd_api_orders_proto_msgTypes[30].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_cmd_api_orders_proto_rawDesc), len(file_cmd_api_orders_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   11,
+			NumMessages:   36,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -43,13 +43,38 @@ var (
 	orderOperationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Name: "order_operations_total",
 		Help: "Total number of order service operations.",
-	}, []string{"operation", "status"})
+	}, []string{"operation", "pickup_point", "status"})
+
+	ordersUnshelvedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "orders_unshelved_total",
+		Help: "Total number of received orders left without a storage cell.",
+	}, []string{"pickup_point"})
 
 	cacheOperationsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Name: "cache_operations_total",
 		Help: "Total number of cache operations.",
 	}, []string{"operation", "status"})
 
+	orderL1CacheEntries = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "order_l1_cache_entries",
+		Help: "Number of orders held in the in-process cache.",
+	})
+
+	cacheCircuitOpen = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "cache_circuit_open",
+		Help: "Whether the cache circuit breaker is open (1) and requests bypass the cache.",
+	})
+
+	orderWatchers = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "order_watchers",
+		Help: "Number of open order status watch streams.",
+	})
+
+	orderWatchersDroppedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "order_watchers_dropped_total",
+		Help: "Total number of order status watchers cut off by the feed.",
+	}, []string{"reason"})
+
 	outboxBatchesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Name: "outbox_batches_total",
 		Help: "Total number of outbox polling batches.",
@@ -66,6 +91,26 @@ var (
 		Buckets: []float64{0, 1, 5, 10, 25, 50, 100},
 	})
 
+	txRetriesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "tx_retries_total",
+		Help: "Total number of transactions replayed or given up after a retryable SQLSTATE.",
+	}, []string{"isolation", "sqlstate", "status"})
+
+	dbReadsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_reads_total",
+		Help: "Total number of reads outside of write transactions by target pool.",
+	}, []string{"target"})
+
+	dbReplicaLagSeconds = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "db_replica_lag_seconds",
+		Help: "Replication lag of the read replica at the last check.",
+	})
+
+	dbReplicaLagChecksTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_replica_lag_checks_total",
+		Help: "Total number of replica lag checks.",
+	}, []string{"status"})
+
 	kafkaMessagesTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Name: "kafka_messages_total",
 		Help: "Total number of Kafka audit messages.",
@@ -88,13 +133,17 @@ func ObserveGRPCRequest(method string, err error, duration time.Duration) {
 	grpcRequestDuration.WithLabelValues(method, status).Observe(duration.Seconds())
 }
 
-func ObserveOrderOperation(operation string, err error) {
+func ObserveOrderOperation(operation string, pickupPoint string, err error) {
 	operationStatus := statusSuccess
 	if err != nil {
 		operationStatus = statusError
 	}
 
-	orderOperationsTotal.WithLabelValues(operation, operationStatus).Inc()
+	orderOperationsTotal.WithLabelValues(operation, normalizeLabel(pickupPoint), operationStatus).Inc()
+}
+
+func ObserveUnshelvedOrder(pickupPoint string) {
+	ordersUnshelvedTotal.WithLabelValues(normalizeLabel(pickupPoint)).Inc()
 }
 
 func ObserveCacheOperation(operation string, err error) {
@@ -106,6 +155,26 @@ func ObserveCacheOperation(operation string, err error) {
 	cacheOperationsTotal.WithLabelValues(operation, operationStatus).Inc()
 }
 
+func SetL1CacheEntries(entries int) {
+	orderL1CacheEntries.Set(float64(entries))
+}
+
+func SetCacheCircuitOpen(open bool) {
+	value := 0.0
+	if open {
+		value = 1
+	}
+	cacheCircuitOpen.Set(value)
+}
+
+func SetOrderWatchers(watchers int) {
+	orderWatchers.Set(float64(watchers))
+}
+
+func ObserveOrderWatcherDropped(reason string) {
+	orderWatchersDroppedTotal.WithLabelValues(reason).Inc()
+}
+
 func ObserveOutboxBatch(status string, tasksCount int) {
 	outboxBatchesTotal.WithLabelValues(status).Inc()
 	outboxTasksLocked.Observe(float64(tasksCount))
@@ -120,6 +189,30 @@ func ObserveOutboxTask(operation string, err error) {
 	outboxTasksTotal.WithLabelValues(operation, operationStatus).Inc()
 }
 
+func ObserveTxRetry(isolation string, sqlState string, exhausted bool) {
+	retryStatus := "retried"
+	if exhausted {
+		retryStatus = "exhausted"
+	}
+
+	txRetriesTotal.WithLabelValues(normalizeLabel(isolation), sqlState, retryStatus).Inc()
+}
+
+func ObserveReadRouting(target string) {
+	dbReadsTotal.WithLabelValues(target).Inc()
+}
+
+func ObserveReplicaLag(lagSeconds float64, err error) {
+	checkStatus := statusSuccess
+	if err != nil {
+		checkStatus = statusError
+	} else {
+		dbReplicaLagSeconds.Set(lagSeconds)
+	}
+
+	dbReplicaLagChecksTotal.WithLabelValues(checkStatus).Inc()
+}
+
 func ObserveKafkaMessage(operation string, err error) {
 	operationStatus := statusSuccess
 	if err != nil {
@@ -168,11 +261,15 @@ func StartMetricsServer() {
 }
 
 func normalizeRoute(route string) string {
-	if route == "" {
+	return normalizeLabel(route)
+}
+
+func normalizeLabel(value string) string {
+	if value == "" {
 		return "unknown"
 	}
 
-	return route
+	return value
 }
 
 func init() {
@@ -182,10 +279,19 @@ func init() {
 		grpcRequestsTotal,
 		grpcRequestDuration,
 		orderOperationsTotal,
+		ordersUnshelvedTotal,
 		cacheOperationsTotal,
+		orderL1CacheEntries,
+		cacheCircuitOpen,
+		orderWatchers,
+		orderWatchersDroppedTotal,
 		outboxBatchesTotal,
 		outboxTasksTotal,
 		outboxTasksLocked,
+		txRetriesTotal,
+		dbReadsTotal,
+		dbReplicaLagSeconds,
+		dbReplicaLagChecksTotal,
 		kafkaMessagesTotal,
 	)
 }
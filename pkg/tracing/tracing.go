@@ -31,6 +31,34 @@ func UnaryInterceptor(
 	return resp, err
 }
 
+func StreamInterceptor(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) (err error) {
+	span, ctx := opentracing.StartSpanFromContext(ss.Context(), info.FullMethod)
+	defer span.Finish()
+
+	err = handler(srv, &tracedServerStream{ServerStream: ss, ctx: ctx})
+
+	if err != nil {
+		RecordError(span, err)
+	}
+
+	return err
+}
+
+// tracedServerStream carries the span of a streaming call in its context.
+type tracedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *tracedServerStream) Context() context.Context {
+	return s.ctx
+}
+
 func StartSpanFromContext(ctx context.Context, operationName string) (opentracing.Span, context.Context) {
 	return opentracing.StartSpanFromContext(ctx, operationName)
 }